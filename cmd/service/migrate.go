@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"time"
+	"todo/pkg/db"
+	"todo/pkg/io"
+	service "todo/pkg/service"
+
+	"github.com/jinzhu/gorm"
+)
+
+// migration is a one-off change to the data. Each runs once, in its own
+// transaction, and is recorded in schema_migrations by version, so a later
+// boot skips it. The list is kept by version, and a version is never
+// reused.
+type migration struct {
+	version uint
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+var migrations = []migration{
+	// Todos from before workflows get the status of their category matching
+	// Complete.
+	{1, "backfill statuses", service.BackfillStatuses},
+	// Todos completed before the completion history count as completed when
+	// last updated.
+	{2, "backfill completions", service.BackfillCompletions},
+	// The history of the items from before it starts with their current
+	// state.
+	{3, "snapshot history", service.SnapshotHistory},
+}
+
+// schemaMigration records an applied migration.
+type schemaMigration struct {
+	Version   uint `gorm:"primary_key;auto_increment:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// migrate brings the database up to date over a single connection,
+// stopping at the first error: the tables and indexes, then the
+// migrations not applied yet, in order.
+func migrate() error {
+	session := db.ConnectPGDB()
+	defer session.Close()
+	err := session.AutoMigrate(&io.Todo{}, &io.TodoCategory{}, &io.Webhook{}, &io.WebhookDelivery{}, &io.OutboxEvent{},
+		&io.Workflow{}, &io.Dependency{}, &io.TimeEntry{}, &io.Completion{}, &io.Change{}, &schemaMigration{}).Error
+	if err != nil {
+		return err
+	}
+	// Backs the one running timer per user check against concurrent starts.
+	err = session.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_running_timer ON time_entries (user_name) WHERE ended_at IS NULL").Error
+	for i := 0; err == nil && i < len(migrations); i++ {
+		err = apply(session, migrations[i])
+	}
+	return err
+}
+
+// apply runs m unless it was applied already. Instances booting together
+// take turns on the lock, so only the first one runs it.
+func apply(session *gorm.DB, m migration) error {
+	tx := session.Begin()
+	err := tx.Exec("LOCK TABLE schema_migrations IN EXCLUSIVE MODE").Error
+	var count int
+	if err == nil {
+		err = tx.Model(&schemaMigration{}).Where("version = ?", m.version).Count(&count).Error
+	}
+	if err == nil && count == 0 {
+		err = m.up(tx)
+		if err == nil {
+			err = tx.Create(&schemaMigration{Version: m.version, Name: m.name, AppliedAt: time.Now()}).Error
+		}
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration %d, %s: %v", m.version, m.name, err)
+	}
+	return tx.Commit().Error
+}
@@ -8,13 +8,21 @@ import (
 	http2 "net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
-	"todo/pkg/db"
+	"time"
 	endpoint "todo/pkg/endpoint"
+	events "todo/pkg/events"
+	graphql "todo/pkg/graphql"
 	http1 "todo/pkg/http"
-	"todo/pkg/io"
+	outbox "todo/pkg/outbox"
 	service "todo/pkg/service"
+	thrift1 "todo/pkg/thrift"
+	trash "todo/pkg/trash"
+	webhook "todo/pkg/webhook"
+	websocket "todo/pkg/websocket"
 
+	thrift "github.com/apache/thrift/lib/go/thrift"
 	endpoint1 "github.com/go-kit/kit/endpoint"
 	log "github.com/go-kit/kit/log"
 	prometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -33,6 +41,7 @@ import (
 
 var tracer opentracinggo.Tracer
 var logger log.Logger
+var broker *events.Broker
 
 // Define our flags. Your service probably won't need to bind listeners for
 // all* supported transports, but we do it here for demonstration purposes.
@@ -47,6 +56,17 @@ var thriftFramed = fs.Bool("thrift-framed", false, "true to enable framing")
 var zipkinURL = fs.String("zipkin-url", "", "Enable Zipkin tracing via a collector URL e.g. http://localhost:9411/api/v1/spans")
 var lightstepToken = fs.String("lightstep-token", "", "Enable LightStep tracing via a LightStep access token")
 var appdashAddr = fs.String("appdash-addr", "", "Enable Appdash tracing via an Appdash server host:port")
+var outboxInterval = fs.Duration("outbox-interval", 200*time.Millisecond, "How often the outbox relay checks for new events")
+var outboxRetention = fs.Duration("outbox-retention", 7*24*time.Hour, "How long published events are kept in the outbox")
+var outboxFile = fs.String("outbox-file", "", "Also append every event to this JSON lines file")
+var outboxNATS = fs.String("outbox-nats", "", "Also publish every event to the NATS server at this URL")
+var outboxNATSPrefix = fs.String("outbox-nats-prefix", "todo", "Prefix of the NATS subjects, followed by the event type")
+var webhookAttempts = fs.Int("webhook-attempts", 8, "Deliveries failing this many times become dead letters")
+var webhookBackoff = fs.Duration("webhook-backoff", 10*time.Second, "Delay before the first webhook retry, doubled on every later one")
+var trashRetention = fs.Duration("trash-retention", 30*24*time.Hour, "How long deleted todos and categories stay in the trash, 0 for ever")
+var propagateCompletion = fs.Bool("propagate-completion", false, "Complete a todo once all its subtasks are, reopen it when one of them is")
+var allowedOrigins = fs.String("allowed-origins", "", "Comma separated origins, or *, browsers may open /events and /ws connections from besides the server's own")
+var enforceDependencies = fs.Bool("enforce-dependencies", false, "Refuse to complete a todo while it has open blockers")
 
 func Run() {
 	viper.SetConfigFile("config.json")
@@ -55,8 +75,9 @@ func Run() {
 		panic(err.Error())
 	}
 
-	db.ConnectPGDB().AutoMigrate(&io.Todo{})
-	db.ConnectPGDB().AutoMigrate(&io.TodoCategory{})
+	if err := migrate(); err != nil {
+		panic(err.Error())
+	}
 	fs.Parse(os.Args[1:])
 
 	// Create a single logger, which we'll use and give to other components.
@@ -96,9 +117,16 @@ func Run() {
 		tracer = opentracinggo.GlobalTracer()
 	}
 
-	svc := service.New(getServiceMiddleware(logger))
+	broker = events.NewBroker(1024)
+	svc := service.New(getServiceMiddleware(logger), service.Config{
+		PropagateCompletion: *propagateCompletion,
+		EnforceDependencies: *enforceDependencies,
+	})
 	eps := endpoint.New(svc, getEndpointMiddleware(logger))
 	g := createService(eps)
+	dispatcher := initWebhookDispatcher(g)
+	initOutboxRelay(g, dispatcher)
+	initTrashJanitor(g)
 	initMetricsEndpoint(g)
 	initCancelInterrupt(g)
 	logger.Log("exit", g.Run())
@@ -107,19 +135,129 @@ func initHttpHandler(endpoints endpoint.Endpoints, g *group.Group) {
 	options := defaultHttpOptions(logger, tracer)
 	// Add your http options here
 
-	httpHandler := http1.NewHTTPHandler(endpoints, options)
+	httpHandler := http2.NewServeMux()
+	httpHandler.Handle("/graphql", graphql.NewHandler(endpoints))
+	// The categories each actor may follow on /events and /ws.
+	visible := events.ActorVisibility(viper.GetStringMapStringSlice("visibility"), endpoints.GetCategoryTree)
+	httpHandler.Handle("/events", events.NewSSEHandler(broker, visible, origins(*allowedOrigins)))
+	httpHandler.Handle("/ws", websocket.NewHandler(endpoints, broker, visible, origins(*allowedOrigins)))
+	httpHandler.Handle("/", http1.NewHTTPHandler(endpoints, options))
 	httpListener, err := net.Listen("tcp", *httpAddr)
 	if err != nil {
 		logger.Log("transport", "HTTP", "during", "Listen", "err", err)
 	}
 	g.Add(func() error {
 		logger.Log("transport", "HTTP", "addr", *httpAddr)
-		return http2.Serve(httpListener, httpHandler)
+		return http2.Serve(httpListener, http1.RequestInfo(httpHandler))
 	}, func(error) {
 		httpListener.Close()
 	})
 
 }
+
+// origins splits the comma separated list s.
+func origins(s string) (o []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			o = append(o, v)
+		}
+	}
+	return o
+}
+func initThriftHandler(endpoints endpoint.Endpoints, g *group.Group) {
+	var protocolFactory thrift.TProtocolFactory
+	switch *thriftProtocol {
+	case "binary":
+		protocolFactory = thrift.NewTBinaryProtocolFactoryDefault()
+	case "compact":
+		protocolFactory = thrift.NewTCompactProtocolFactory()
+	case "json":
+		protocolFactory = thrift.NewTJSONProtocolFactory()
+	case "simplejson":
+		protocolFactory = thrift.NewTSimpleJSONProtocolFactory()
+	default:
+		logger.Log("transport", "Thrift", "during", "Setup", "err", fmt.Sprintf("invalid protocol %q", *thriftProtocol))
+		return
+	}
+	var transportFactory thrift.TTransportFactory
+	if *thriftBuffer > 0 {
+		transportFactory = thrift.NewTBufferedTransportFactory(*thriftBuffer)
+	} else {
+		transportFactory = thrift.NewTTransportFactory()
+	}
+	if *thriftFramed {
+		transportFactory = thrift.NewTFramedTransportFactory(transportFactory)
+	}
+	thriftSocket, err := thrift.NewTServerSocket(*thriftAddr)
+	if err != nil {
+		logger.Log("transport", "Thrift", "during", "Listen", "err", err)
+		return
+	}
+	g.Add(func() error {
+		logger.Log("transport", "Thrift", "addr", *thriftAddr, "protocol", *thriftProtocol, "buffer", *thriftBuffer, "framed", *thriftFramed)
+		return thrift.NewTSimpleServer4(thrift1.NewThriftHandler(endpoints), thriftSocket, transportFactory, protocolFactory).Serve()
+	}, func(error) {
+		thriftSocket.Close()
+	})
+}
+func initOutboxRelay(g *group.Group, dispatcher *webhook.Dispatcher) {
+	// The webhook deliveries come first: an event is only marked published
+	// once they are queued.
+	publishers := outbox.Publishers{dispatcher, outbox.BrokerPublisher{Broker: broker}}
+	var closers []func() error
+	if *outboxFile != "" {
+		p, err := outbox.NewFilePublisher(*outboxFile)
+		if err != nil {
+			logger.Log("component", "outbox", "during", "Open", "err", err)
+			os.Exit(1)
+		}
+		closers = append(closers, p.Close)
+		publishers = append(publishers, p)
+	}
+	if *outboxNATS != "" {
+		p, err := outbox.NewNATSPublisher(*outboxNATS, *outboxNATSPrefix)
+		if err != nil {
+			logger.Log("component", "outbox", "during", "Connect", "err", err)
+			os.Exit(1)
+		}
+		closers = append(closers, p.Close)
+		publishers = append(publishers, p)
+	}
+	relay := outbox.NewRelay(publishers, log.With(logger, "component", "outbox"), *outboxInterval, *outboxRetention)
+	g.Add(func() error {
+		logger.Log("component", "outbox", "file", *outboxFile, "nats", *outboxNATS)
+		defer func() {
+			for _, c := range closers {
+				c()
+			}
+		}()
+		return relay.Run()
+	}, func(error) {
+		relay.Stop()
+	})
+}
+func initWebhookDispatcher(g *group.Group) *webhook.Dispatcher {
+	dispatcher := webhook.NewDispatcher(log.With(logger, "component", "webhook"), *webhookAttempts, *webhookBackoff)
+	g.Add(func() error {
+		logger.Log("component", "webhook", "attempts", *webhookAttempts, "backoff", *webhookBackoff)
+		return dispatcher.Run()
+	}, func(error) {
+		dispatcher.Stop()
+	})
+	return dispatcher
+}
+func initTrashJanitor(g *group.Group) {
+	if *trashRetention == 0 {
+		return
+	}
+	janitor := trash.NewJanitor(log.With(logger, "component", "trash"), *trashRetention, time.Hour)
+	g.Add(func() error {
+		logger.Log("component", "trash", "retention", *trashRetention)
+		return janitor.Run()
+	}, func(error) {
+		janitor.Stop()
+	})
+}
 func getServiceMiddleware(logger log.Logger) (mw []service.Middleware) {
 	mw = []service.Middleware{}
 	mw = addDefaultServiceMiddleware(logger, mw)
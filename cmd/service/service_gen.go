@@ -17,24 +17,67 @@ import (
 func createService(endpoints endpoint.Endpoints) (g *group.Group) {
 	g = &group.Group{}
 	initHttpHandler(endpoints, g)
+	initThriftHandler(endpoints, g)
 	return g
 }
 func defaultHttpOptions(logger log.Logger, tracer opentracinggo.Tracer) map[string][]http.ServerOption {
 	options := map[string][]http.ServerOption{
-		"Add":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Add", logger))},
-		"AddCategory":    {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "AddCategory", logger))},
-		"Delete":         {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Delete", logger))},
-		"DeleteCategory": {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "DeleteCategory", logger))},
-		"Get":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Get", logger))},
-		"GetCatChildes":  {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCatChildes", logger))},
-		"GetCategory":    {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategory", logger))},
-		"GetChildes":     {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetChildes", logger))},
-		"RemoveComplete": {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "RemoveComplete", logger))},
-		"ReplyTo":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "ReplyTo", logger))},
-		"SetComplete":    {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetComplete", logger))},
-		"SetStar":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetStar", logger))},
-		"Update":         {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Update", logger))},
-		"UpdateCategory": {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateCategory", logger))},
+		"Add":                  {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Add", logger))},
+		"AddCategory":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "AddCategory", logger))},
+		"Delete":               {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Delete", logger))},
+		"DeleteCategory":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "DeleteCategory", logger))},
+		"Get":                  {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Get", logger))},
+		"GetCatChildes":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCatChildes", logger))},
+		"GetCategory":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategory", logger))},
+		"GetChildes":           {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetChildes", logger))},
+		"RemoveComplete":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "RemoveComplete", logger))},
+		"ReplyTo":              {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "ReplyTo", logger))},
+		"SetComplete":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetComplete", logger))},
+		"SetStar":              {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetStar", logger))},
+		"Update":               {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Update", logger))},
+		"UpdateCategory":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateCategory", logger))},
+		"AddWebhook":           {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "AddWebhook", logger))},
+		"GetWebhooks":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetWebhooks", logger))},
+		"DeleteWebhook":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "DeleteWebhook", logger))},
+		"GetWebhookDeliveries": {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetWebhookDeliveries", logger))},
+		"GetDeadLetters":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetDeadLetters", logger))},
+		"RetryDelivery":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "RetryDelivery", logger))},
+		"GetTree":              {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetTree", logger))},
+		"Move":                 {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Move", logger))},
+		"MoveCategory":         {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "MoveCategory", logger))},
+		"ListTrash":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "ListTrash", logger))},
+		"Restore":              {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Restore", logger))},
+		"Purge":                {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Purge", logger))},
+		"GetCategoryTree":      {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategoryTree", logger))},
+		"GetCategoryPath":      {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategoryPath", logger))},
+		"Reorder":              {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Reorder", logger))},
+		"Transition":           {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Transition", logger))},
+		"GetWorkflow":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetWorkflow", logger))},
+		"SetWorkflow":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetWorkflow", logger))},
+		"GetBoard":             {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetBoard", logger))},
+		"SetPriority":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "SetPriority", logger))},
+		"GetMatrix":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetMatrix", logger))},
+		"AddDependency":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "AddDependency", logger))},
+		"RemoveDependency":     {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "RemoveDependency", logger))},
+		"GetBlocked":           {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetBlocked", logger))},
+		"GetReady":             {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetReady", logger))},
+		"GetDependencyGraph":   {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetDependencyGraph", logger))},
+		"GetCategoryPlan":      {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategoryPlan", logger))},
+		"GetTreePlan":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetTreePlan", logger))},
+		"StartTimer":           {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "StartTimer", logger))},
+		"StopTimer":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "StopTimer", logger))},
+		"AddTimeEntry":         {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "AddTimeEntry", logger))},
+		"GetTimeEntries":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetTimeEntries", logger))},
+		"UpdateTimeEntry":      {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "UpdateTimeEntry", logger))},
+		"DeleteTimeEntry":      {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "DeleteTimeEntry", logger))},
+		"GetTimeReport":        {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetTimeReport", logger))},
+		"GetBurndown":          {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetBurndown", logger))},
+		"GetTodoHistory":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetTodoHistory", logger))},
+		"GetCategoryHistory":   {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "GetCategoryHistory", logger))},
+		"Undo":                 {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Undo", logger))},
+		"Redo":                 {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "Redo", logger))},
+		"FindTodos":            {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "FindTodos", logger))},
+		"FindCategories":       {http.ServerErrorEncoder(http1.ErrorEncoder), http.ServerErrorLogger(logger), http.ServerBefore(opentracing.HTTPToContext(tracer, "FindCategories", logger))},
 	}
 	return options
 }
@@ -53,12 +96,54 @@ func addDefaultEndpointMiddleware(logger log.Logger, duration *prometheus.Summar
 	mw["UpdateCategory"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "UpdateCategory")), endpoint.InstrumentingMiddleware(duration.With("method", "UpdateCategory"))}
 	mw["DeleteCategory"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "DeleteCategory")), endpoint.InstrumentingMiddleware(duration.With("method", "DeleteCategory"))}
 	mw["GetCatChildes"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetCatChildes")), endpoint.InstrumentingMiddleware(duration.With("method", "GetCatChildes"))}
+	mw["AddWebhook"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "AddWebhook")), endpoint.InstrumentingMiddleware(duration.With("method", "AddWebhook"))}
+	mw["GetWebhooks"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetWebhooks")), endpoint.InstrumentingMiddleware(duration.With("method", "GetWebhooks"))}
+	mw["DeleteWebhook"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "DeleteWebhook")), endpoint.InstrumentingMiddleware(duration.With("method", "DeleteWebhook"))}
+	mw["GetWebhookDeliveries"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetWebhookDeliveries")), endpoint.InstrumentingMiddleware(duration.With("method", "GetWebhookDeliveries"))}
+	mw["GetDeadLetters"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetDeadLetters")), endpoint.InstrumentingMiddleware(duration.With("method", "GetDeadLetters"))}
+	mw["RetryDelivery"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "RetryDelivery")), endpoint.InstrumentingMiddleware(duration.With("method", "RetryDelivery"))}
+	mw["GetTree"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetTree")), endpoint.InstrumentingMiddleware(duration.With("method", "GetTree"))}
+	mw["Move"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Move")), endpoint.InstrumentingMiddleware(duration.With("method", "Move"))}
+	mw["MoveCategory"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "MoveCategory")), endpoint.InstrumentingMiddleware(duration.With("method", "MoveCategory"))}
+	mw["ListTrash"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "ListTrash")), endpoint.InstrumentingMiddleware(duration.With("method", "ListTrash"))}
+	mw["Restore"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Restore")), endpoint.InstrumentingMiddleware(duration.With("method", "Restore"))}
+	mw["Purge"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Purge")), endpoint.InstrumentingMiddleware(duration.With("method", "Purge"))}
+	mw["GetCategoryTree"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetCategoryTree")), endpoint.InstrumentingMiddleware(duration.With("method", "GetCategoryTree"))}
+	mw["GetCategoryPath"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetCategoryPath")), endpoint.InstrumentingMiddleware(duration.With("method", "GetCategoryPath"))}
+	mw["Reorder"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Reorder")), endpoint.InstrumentingMiddleware(duration.With("method", "Reorder"))}
+	mw["Transition"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Transition")), endpoint.InstrumentingMiddleware(duration.With("method", "Transition"))}
+	mw["GetWorkflow"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetWorkflow")), endpoint.InstrumentingMiddleware(duration.With("method", "GetWorkflow"))}
+	mw["SetWorkflow"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "SetWorkflow")), endpoint.InstrumentingMiddleware(duration.With("method", "SetWorkflow"))}
+	mw["GetBoard"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetBoard")), endpoint.InstrumentingMiddleware(duration.With("method", "GetBoard"))}
+	mw["SetPriority"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "SetPriority")), endpoint.InstrumentingMiddleware(duration.With("method", "SetPriority"))}
+	mw["GetMatrix"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetMatrix")), endpoint.InstrumentingMiddleware(duration.With("method", "GetMatrix"))}
+	mw["AddDependency"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "AddDependency")), endpoint.InstrumentingMiddleware(duration.With("method", "AddDependency"))}
+	mw["RemoveDependency"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "RemoveDependency")), endpoint.InstrumentingMiddleware(duration.With("method", "RemoveDependency"))}
+	mw["GetBlocked"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetBlocked")), endpoint.InstrumentingMiddleware(duration.With("method", "GetBlocked"))}
+	mw["GetReady"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetReady")), endpoint.InstrumentingMiddleware(duration.With("method", "GetReady"))}
+	mw["GetDependencyGraph"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetDependencyGraph")), endpoint.InstrumentingMiddleware(duration.With("method", "GetDependencyGraph"))}
+	mw["GetCategoryPlan"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetCategoryPlan")), endpoint.InstrumentingMiddleware(duration.With("method", "GetCategoryPlan"))}
+	mw["GetTreePlan"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetTreePlan")), endpoint.InstrumentingMiddleware(duration.With("method", "GetTreePlan"))}
+	mw["StartTimer"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "StartTimer")), endpoint.InstrumentingMiddleware(duration.With("method", "StartTimer"))}
+	mw["StopTimer"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "StopTimer")), endpoint.InstrumentingMiddleware(duration.With("method", "StopTimer"))}
+	mw["AddTimeEntry"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "AddTimeEntry")), endpoint.InstrumentingMiddleware(duration.With("method", "AddTimeEntry"))}
+	mw["GetTimeEntries"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetTimeEntries")), endpoint.InstrumentingMiddleware(duration.With("method", "GetTimeEntries"))}
+	mw["UpdateTimeEntry"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "UpdateTimeEntry")), endpoint.InstrumentingMiddleware(duration.With("method", "UpdateTimeEntry"))}
+	mw["DeleteTimeEntry"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "DeleteTimeEntry")), endpoint.InstrumentingMiddleware(duration.With("method", "DeleteTimeEntry"))}
+	mw["GetTimeReport"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetTimeReport")), endpoint.InstrumentingMiddleware(duration.With("method", "GetTimeReport"))}
+	mw["GetBurndown"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetBurndown")), endpoint.InstrumentingMiddleware(duration.With("method", "GetBurndown"))}
+	mw["GetTodoHistory"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetTodoHistory")), endpoint.InstrumentingMiddleware(duration.With("method", "GetTodoHistory"))}
+	mw["GetCategoryHistory"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "GetCategoryHistory")), endpoint.InstrumentingMiddleware(duration.With("method", "GetCategoryHistory"))}
+	mw["Undo"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Undo")), endpoint.InstrumentingMiddleware(duration.With("method", "Undo"))}
+	mw["Redo"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "Redo")), endpoint.InstrumentingMiddleware(duration.With("method", "Redo"))}
+	mw["FindTodos"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "FindTodos")), endpoint.InstrumentingMiddleware(duration.With("method", "FindTodos"))}
+	mw["FindCategories"] = []endpoint1.Middleware{endpoint.LoggingMiddleware(log.With(logger, "method", "FindCategories")), endpoint.InstrumentingMiddleware(duration.With("method", "FindCategories"))}
 }
 func addDefaultServiceMiddleware(logger log.Logger, mw []service.Middleware) []service.Middleware {
 	return append(mw, service.LoggingMiddleware(logger))
 }
 func addEndpointMiddlewareToAllMethods(mw map[string][]endpoint1.Middleware, m endpoint1.Middleware) {
-	methods := []string{"Get", "Add", "SetComplete", "RemoveComplete", "Delete", "Update", "SetStar", "ReplyTo", "GetChildes", "GetCategory", "AddCategory", "UpdateCategory", "DeleteCategory", "GetCatChildes"}
+	methods := []string{"Get", "Add", "SetComplete", "RemoveComplete", "Delete", "Update", "SetStar", "ReplyTo", "GetChildes", "GetCategory", "AddCategory", "UpdateCategory", "DeleteCategory", "GetCatChildes", "AddWebhook", "GetWebhooks", "DeleteWebhook", "GetWebhookDeliveries", "GetDeadLetters", "RetryDelivery", "GetTree", "Move", "MoveCategory", "ListTrash", "Restore", "Purge", "GetCategoryTree", "GetCategoryPath", "Reorder", "Transition", "GetWorkflow", "SetWorkflow", "GetBoard", "SetPriority", "GetMatrix", "AddDependency", "RemoveDependency", "GetBlocked", "GetReady", "GetDependencyGraph", "GetCategoryPlan", "GetTreePlan", "StartTimer", "StopTimer", "AddTimeEntry", "GetTimeEntries", "UpdateTimeEntry", "DeleteTimeEntry", "GetTimeReport", "GetBurndown", "GetTodoHistory", "GetCategoryHistory", "Undo", "Redo", "FindTodos", "FindCategories"}
 	for _, v := range methods {
 		mw[v] = append(mw[v], m)
 	}
@@ -0,0 +1,606 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	io "todo/pkg/io"
+	service "todo/pkg/service"
+)
+
+type command func(ctx context.Context, svc service.TodoService, args []string) error
+
+var commands = map[string]command{
+	"add":     add,
+	"ls":      ls,
+	"done":    each(service.TodoService.SetComplete),
+	"undone":  each(service.TodoService.RemoveComplete),
+	"rm":      remove(service.TodoService.Delete),
+	"star":    star,
+	"prio":    prio,
+	"matrix":  matrix,
+	"reply":   reply,
+	"mv":      mv,
+	"order":   order,
+	"status":  status,
+	"board":   board,
+	"tree":    tree,
+	"cat":     cat,
+	"trash":   trash,
+	"dep":     dep,
+	"blocked": list(service.TodoService.GetBlocked),
+	"ready":   list(service.TodoService.GetReady),
+	"plan":    plan,
+	"burn":    burn,
+	"history": history,
+	"undo":    replay(service.TodoService.Undo),
+	"redo":    replay(service.TodoService.Redo),
+	"time":    track,
+	"ui":      ui,
+}
+
+var catCommands = map[string]command{
+	"add":  catAdd,
+	"ls":   catLs,
+	"rm":   remove(service.TodoService.DeleteCategory),
+	"mv":   catMv,
+	"path": catPath,
+}
+
+var timeCommands = map[string]command{
+	"start":  timeStart,
+	"stop":   timeStop,
+	"log":    timeLog,
+	"ls":     timeLs,
+	"rm":     each(service.TodoService.DeleteTimeEntry),
+	"report": timeReport,
+}
+
+var trashCommands = map[string]command{
+	"ls":      trashLs,
+	"restore": untrash(service.TodoService.Restore),
+	"purge":   untrash(service.TodoService.Purge),
+}
+
+func add(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	description := fs.String("d", "", "Description")
+	category := fs.Uint("cat", 0, "Category id")
+	parent := fs.Uint("parent", 0, "Parent todo id")
+	stars := fs.Uint("star", 0, "Star, 0 to 5")
+	priority := fs.Uint("p", 0, "Priority, 1 to 4")
+	due := fs.String("due", "", "Due date, 2006-01-02 or RFC 3339")
+	estimate := fs.Float64("est", 0, "Estimated hours of work")
+	points := fs.Float64("pts", 0, "Story points")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("add: missing title")
+	}
+	var dueAt *time.Time
+	if *due != "" {
+		d, err := parseDue(*due)
+		if err != nil {
+			return fmt.Errorf("add: %v", err)
+		}
+		dueAt = &d
+	}
+	t, err := svc.Add(ctx, io.Todo{
+		Title:       strings.Join(fs.Args(), " "),
+		Description: *description,
+		CategoryID:  *category,
+		ParentID:    *parent,
+		Priority:    uint8(*priority),
+		Due:         dueAt,
+		Estimate:    *estimate,
+		Points:      *points,
+		Star:        uint8(*stars),
+	})
+	if err != nil {
+		return err
+	}
+	return printTodos([]io.Todo{t})
+}
+
+func ls(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	category := fs.Uint("cat", 0, "Only todos of this category")
+	open := fs.Bool("open", false, "Only open todos")
+	complete := fs.Bool("done", false, "Only completed todos")
+	fs.Parse(args)
+	t, err := svc.Get(ctx)
+	if err != nil {
+		return err
+	}
+	filtered := t[:0]
+	for _, v := range t {
+		if *category != 0 && v.CategoryID != *category {
+			continue
+		}
+		if (*open && v.Complete) || (*complete && !v.Complete) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return printTodos(filtered)
+}
+
+// each returns a command that calls fn for every id argument.
+func each(fn func(service.TodoService, context.Context, string) error) command {
+	return func(ctx context.Context, svc service.TodoService, args []string) error {
+		if len(args) == 0 {
+			return errors.New("missing id")
+		}
+		for _, id := range args {
+			if err := fn(svc, ctx, id); err != nil {
+				return fmt.Errorf("%s: %v", id, err)
+			}
+		}
+		return nil
+	}
+}
+
+// list returns a command printing the todos fn returns.
+func list(fn func(service.TodoService, context.Context) ([]io.Todo, error)) command {
+	return func(ctx context.Context, svc service.TodoService, args []string) error {
+		t, err := fn(svc, ctx)
+		if err != nil {
+			return err
+		}
+		return printTodos(t)
+	}
+}
+
+// remove returns a command deleting every id argument with the policy of
+// its -policy flag.
+func remove(fn func(service.TodoService, context.Context, string, string) error) command {
+	return func(ctx context.Context, svc service.TodoService, args []string) error {
+		fs := flag.NewFlagSet("rm", flag.ExitOnError)
+		policy := fs.String("policy", io.DeleteRefuse, "What to do with children: refuse, cascade or reparent")
+		fs.Parse(args)
+		return each(func(svc service.TodoService, ctx context.Context, id string) error {
+			return fn(svc, ctx, id, *policy)
+		})(ctx, svc, fs.Args())
+	}
+}
+
+func star(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 2 {
+		return errors.New("star: want <id> <0-5>")
+	}
+	n, err := strconv.ParseUint(args[1], 10, 8)
+	if err != nil {
+		return fmt.Errorf("star: %v", err)
+	}
+	return svc.SetStar(ctx, args[0], uint8(n))
+}
+
+func prio(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 2 {
+		return errors.New("prio: want <id> <0-4>")
+	}
+	n, err := strconv.ParseUint(args[1], 10, 8)
+	if err != nil {
+		return fmt.Errorf("prio: %v", err)
+	}
+	return svc.SetPriority(ctx, args[0], uint8(n))
+}
+
+func matrix(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("matrix", flag.ExitOnError)
+	hours := fs.Int("hours", 0, "Todos due within this many hours are urgent, 48 if 0")
+	fs.Parse(args)
+	m, err := svc.GetMatrix(ctx, *hours)
+	if err != nil {
+		return err
+	}
+	return printMatrix(m)
+}
+
+// plan prints the schedule of a todo and its subtasks, or with -cat of a
+// category and its sub categories.
+func plan(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	category := fs.Bool("cat", false, "The id is a category")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("plan: want <id>")
+	}
+	get := svc.GetTreePlan
+	if *category {
+		get = svc.GetCategoryPlan
+	}
+	p, err := get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printPlan(p)
+}
+
+func burn(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("burn", flag.ExitOnError)
+	from := fs.String("from", "", "First day, two weeks ago if empty")
+	to := fs.String("to", "", "Last day, today if empty")
+	unit := fs.String("unit", io.UnitPoints, "Unit: points, hours or todos")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("burn: want <category id>")
+	}
+	b, err := svc.GetBurndown(ctx, fs.Arg(0), *from, *to, *unit)
+	if err != nil {
+		return err
+	}
+	return printBurndown(b)
+}
+
+// history prints the changes to a todo, or with -cat to a category.
+func history(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	category := fs.Bool("cat", false, "The id is a category")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("history: want <id>")
+	}
+	get := svc.GetTodoHistory
+	if *category {
+		get = svc.GetCategoryHistory
+	}
+	h, err := get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printHistory(h)
+}
+
+// replay returns a command undoing or redoing the last n operations, one
+// by default, printing the changes it made.
+func replay(fn func(service.TodoService, context.Context, int) ([]io.Change, error)) command {
+	return func(ctx context.Context, svc service.TodoService, args []string) error {
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return err
+			}
+			steps = n
+		}
+		h, err := fn(svc, ctx, steps)
+		if err != nil {
+			return err
+		}
+		return printHistory(h)
+	}
+}
+
+// parseDue reads a due date as a day, midnight local time, or an RFC 3339
+// time.
+func parseDue(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func reply(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("reply", flag.ExitOnError)
+	description := fs.String("d", "", "Description")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		return errors.New("reply: want <parent id> <title>")
+	}
+	parent, err := strconv.ParseUint(fs.Arg(0), 10, 0)
+	if err != nil {
+		return fmt.Errorf("reply: %v", err)
+	}
+	t, err := svc.ReplyTo(ctx, uint(parent), io.Todo{
+		Title:       strings.Join(fs.Args()[1:], " "),
+		Description: *description,
+	})
+	if err != nil {
+		return err
+	}
+	return printTodos([]io.Todo{t})
+}
+
+// mv makes a todo a subtask of another one, or a top level todo when the
+// parent id is 0.
+func mv(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 2 {
+		return errors.New("mv: want <id> <parent id>")
+	}
+	parent, err := strconv.ParseUint(args[1], 10, 0)
+	if err != nil {
+		return fmt.Errorf("mv: %v", err)
+	}
+	t, err := svc.Move(ctx, args[0], uint(parent))
+	if err != nil {
+		return err
+	}
+	return printTodos([]io.Todo{t})
+}
+
+// tree prints the subtasks below id, or every todo when id is omitted.
+func order(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 3 || (args[1] != "before" && args[1] != "after") {
+		return errors.New("order: want <id> before|after <sibling id>")
+	}
+	sibling, err := strconv.ParseUint(args[2], 10, 0)
+	if err != nil {
+		return fmt.Errorf("order: %v", err)
+	}
+	var before, after uint
+	if args[1] == "before" {
+		before = uint(sibling)
+	} else {
+		after = uint(sibling)
+	}
+	t, err := svc.Reorder(ctx, args[0], before, after)
+	if err != nil {
+		return err
+	}
+	return printTodos([]io.Todo{t})
+}
+
+func status(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) < 2 {
+		return errors.New("status: want <id> <status>")
+	}
+	t, err := svc.Transition(ctx, args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return err
+	}
+	return printTodos([]io.Todo{t})
+}
+
+func board(ctx context.Context, svc service.TodoService, args []string) error {
+	category := "0"
+	if len(args) > 0 {
+		category = args[0]
+	}
+	b, err := svc.GetBoard(ctx, category)
+	if err != nil {
+		return err
+	}
+	return printBoard(b)
+}
+
+func tree(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	depth := fs.Int("depth", 0, "Levels of subtasks to print, 0 for all")
+	fs.Parse(args)
+	switch fs.NArg() {
+	case 0:
+		t, err := svc.Get(ctx)
+		if err != nil {
+			return err
+		}
+		return printTree(service.Nest(t, 0, *depth))
+	case 1:
+		t, err := svc.GetTree(ctx, fs.Arg(0), *depth)
+		if err != nil {
+			return err
+		}
+		return printTree(t.Children)
+	default:
+		return errors.New("tree: want at most one id")
+	}
+}
+
+func cat(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) == 0 {
+		return errors.New("cat: missing subcommand")
+	}
+	cmd, ok := catCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("cat: unknown subcommand %q", args[0])
+	}
+	return cmd(ctx, svc, args[1:])
+}
+
+func catAdd(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("cat add", flag.ExitOnError)
+	parent := fs.Uint("parent", 0, "Parent category id")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("cat add: missing name")
+	}
+	c, err := svc.AddCategory(ctx, io.TodoCategory{
+		Name:     strings.Join(fs.Args(), " "),
+		ParentID: *parent,
+	})
+	if err != nil {
+		return err
+	}
+	return printCategories([]io.TodoCategory{c})
+}
+
+func catLs(ctx context.Context, svc service.TodoService, args []string) error {
+	c, err := svc.GetCategoryTree(ctx)
+	if err != nil {
+		return err
+	}
+	return printCategoryTree(c)
+}
+
+func catPath(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 1 {
+		return errors.New("cat path: want <id>")
+	}
+	c, err := svc.GetCategoryPath(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if *output == "json" {
+		return printJSON(c)
+	}
+	names := []string{}
+	for _, v := range c {
+		names = append(names, v.Name)
+	}
+	fmt.Println(strings.Join(names, service.PathSeparator))
+	return nil
+}
+
+func catMv(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 2 {
+		return errors.New("cat mv: want <id> <parent id>")
+	}
+	parent, err := strconv.ParseUint(args[1], 10, 0)
+	if err != nil {
+		return fmt.Errorf("cat mv: %v", err)
+	}
+	c, err := svc.MoveCategory(ctx, args[0], uint(parent))
+	if err != nil {
+		return err
+	}
+	return printCategories([]io.TodoCategory{c})
+}
+
+func trash(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) == 0 {
+		return trashLs(ctx, svc, args)
+	}
+	cmd, ok := trashCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("trash: unknown subcommand %q", args[0])
+	}
+	return cmd(ctx, svc, args[1:])
+}
+
+func trashLs(ctx context.Context, svc service.TodoService, args []string) error {
+	t, err := svc.ListTrash(ctx)
+	if err != nil {
+		return err
+	}
+	if *output == "json" {
+		return printJSON(t)
+	}
+	if err := printTodos(t.Todos); err != nil {
+		return err
+	}
+	fmt.Println()
+	return printCategories(t.Categories)
+}
+
+// untrash returns a command restoring or purging every id argument, a
+// todo or with -cat a category.
+func untrash(fn func(service.TodoService, context.Context, string, string, bool) error) command {
+	return func(ctx context.Context, svc service.TodoService, args []string) error {
+		fs := flag.NewFlagSet("trash", flag.ExitOnError)
+		category := fs.Bool("cat", false, "The ids are categories")
+		subtree := fs.Bool("subtree", false, "Also the trashed items below")
+		fs.Parse(args)
+		kind := io.TrashTodo
+		if *category {
+			kind = io.TrashCategory
+		}
+		return each(func(svc service.TodoService, ctx context.Context, id string) error {
+			return fn(svc, ctx, kind, id, *subtree)
+		})(ctx, svc, fs.Args())
+	}
+}
+
+func dep(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) == 1 && args[0] == "graph" {
+		g, err := svc.GetDependencyGraph(ctx)
+		if err != nil {
+			return err
+		}
+		return printGraph(g)
+	}
+	if len(args) != 3 || (args[0] != "add" && args[0] != "rm") {
+		return errors.New("dep: want add|rm <id> <blocker id>, or graph")
+	}
+	blocker, err := strconv.ParseUint(args[2], 10, 0)
+	if err != nil {
+		return fmt.Errorf("dep: %v", err)
+	}
+	if args[0] == "rm" {
+		return svc.RemoveDependency(ctx, args[1], uint(blocker))
+	}
+	_, err = svc.AddDependency(ctx, args[1], uint(blocker))
+	return err
+}
+
+func track(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) == 0 {
+		return errors.New("time: missing subcommand")
+	}
+	cmd, ok := timeCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("time: unknown subcommand %q", args[0])
+	}
+	return cmd(ctx, svc, args[1:])
+}
+
+func timeStart(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 1 {
+		return errors.New("time start: want <id>")
+	}
+	e, err := svc.StartTimer(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printTimeEntries([]io.TimeEntry{e})
+}
+
+func timeStop(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 0 {
+		return errors.New("time stop: want no arguments")
+	}
+	e, err := svc.StopTimer(ctx)
+	if err != nil {
+		return err
+	}
+	return printTimeEntries([]io.TimeEntry{e})
+}
+
+// timeLog logs time already spent on a todo, from a start to an end time.
+func timeLog(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("time log", flag.ExitOnError)
+	note := fs.String("note", "", "What the time went to")
+	fs.Parse(args)
+	if fs.NArg() != 3 {
+		return errors.New("time log: want <id> <start> <end>")
+	}
+	start, err := parseDue(fs.Arg(1))
+	if err != nil {
+		return fmt.Errorf("time log: %v", err)
+	}
+	end, err := parseDue(fs.Arg(2))
+	if err != nil {
+		return fmt.Errorf("time log: %v", err)
+	}
+	e, err := svc.AddTimeEntry(ctx, fs.Arg(0), io.TimeEntry{Note: *note, StartedAt: start, EndedAt: &end})
+	if err != nil {
+		return err
+	}
+	return printTimeEntries([]io.TimeEntry{e})
+}
+
+func timeLs(ctx context.Context, svc service.TodoService, args []string) error {
+	if len(args) != 1 {
+		return errors.New("time ls: want <id>")
+	}
+	e, err := svc.GetTimeEntries(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return printTimeEntries(e)
+}
+
+func timeReport(ctx context.Context, svc service.TodoService, args []string) error {
+	fs := flag.NewFlagSet("time report", flag.ExitOnError)
+	from := fs.String("from", "", "First day or time reported")
+	to := fs.String("to", "", "Day or time the report stops at, excluded")
+	user := fs.String("user", "", "Only the time of this user")
+	fs.Parse(args)
+	r, err := svc.GetTimeReport(ctx, *from, *to, *user)
+	if err != nil {
+		return err
+	}
+	return printTimeReport(r)
+}
@@ -0,0 +1,142 @@
+// Command todo is a terminal client for the todo service. It talks to the
+// HTTP API through pkg/client and reads the server URL, the token and the
+// actor changes are made as, the login name by default, from a config
+// file, e.g. ~/.todo.json:
+//
+//	{
+//	  "server": "http://localhost:8081",
+//	  "token": "<access token>",
+//	  "actor": "ana"
+//	}
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	client "todo/pkg/client"
+	service "todo/pkg/service"
+
+	http "github.com/go-kit/kit/transport/http"
+	"github.com/spf13/viper"
+)
+
+var fs = flag.NewFlagSet("todo", flag.ExitOnError)
+var configFile = fs.String("config", defaultConfigFile(), "Config file holding server and token")
+var server = fs.String("server", "", "Server URL, overrides the config file")
+var output = fs.String("o", "table", "Output format: table or json")
+var asOf = fs.String("as-of", "", "List todos and categories as they were at this day or RFC 3339 time")
+
+func main() {
+	fs.Usage = usage
+	fs.Parse(os.Args[1:])
+	if fs.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		fail(fmt.Errorf("unknown output format %q", *output))
+	}
+
+	svc, err := newClient()
+	if err != nil {
+		fail(err)
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fail(fmt.Errorf("unknown command %q", fs.Arg(0)))
+	}
+	if err := cmd(context.Background(), svc, fs.Args()[1:]); err != nil {
+		fail(err)
+	}
+}
+
+func newClient() (service.TodoService, error) {
+	viper.SetDefault("server", "http://localhost:8081")
+	viper.SetDefault("timeout", 30*time.Second)
+	viper.SetDefault("actor", os.Getenv("USER"))
+	viper.SetEnvPrefix("todo")
+	viper.AutomaticEnv()
+	viper.SetConfigFile(*configFile)
+	if err := viper.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if *server != "" {
+		viper.Set("server", *server)
+	}
+	options := []http.ClientOption{client.Timeout(viper.GetDuration("timeout"))}
+	if token := viper.GetString("token"); token != "" {
+		options = append(options, client.Token(token))
+	}
+	if actor := viper.GetString("actor"); actor != "" {
+		options = append(options, client.Actor(actor))
+	}
+	if *asOf != "" {
+		options = append(options, client.AsOf(*asOf))
+	}
+	return client.New(viper.GetString("server"), client.AllMethods(options...))
+}
+
+func defaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".todo.json"
+	}
+	return filepath.Join(home, ".todo.json")
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: todo [flags] <command> [args]
+
+commands:
+  add [-d desc] [-cat id] [-parent id] [-star n] [-p n] [-due date] [-est hours] [-pts n] <title>
+  ls [-cat id] [-open] [-done]
+  done <id>...
+  undone <id>...
+  rm [-policy refuse|cascade|reparent] <id>...
+  star <id> <0-5>
+  prio <id> <0-4>
+  matrix [-hours n]
+  reply [-d desc] <parent id> <title>
+  mv <id> <parent id, 0 for none>
+  order <id> before|after <sibling id>
+  status <id> <status>
+  board [category id]
+  tree [-depth n] [id]
+  cat add [-parent id] <name>
+  cat ls
+  cat rm [-policy refuse|cascade|reparent] <id>...
+  cat mv <id> <parent id, 0 for none>
+  cat path <id>
+  dep add|rm <id> <blocker id>
+  dep graph
+  blocked
+  ready
+  plan [-cat] <id>
+  history [-cat] <id>
+  undo [n]
+  redo [n]
+  burn [-from date] [-to date] [-unit points|hours|todos] <category id>
+  time start <id>
+  time stop
+  time log [-note text] <id> <start> <end>
+  time ls <id>
+  time rm <entry id>...
+  time report [-from date] [-to date] [-user name]
+  trash [ls]
+  trash restore [-cat] [-subtree] <id>...
+  trash purge [-cat] [-subtree] <id>...
+  ui
+
+flags:
+`)
+	fs.PrintDefaults()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "todo:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+	io "todo/pkg/io"
+)
+
+func printJSON(v interface{}) error {
+	e := json.NewEncoder(os.Stdout)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+func printTodos(t []io.Todo) error {
+	if *output == "json" {
+		return printJSON(t)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDONE\tPROG\tPRIO\tSTAR\tCAT\tPARENT\tTITLE")
+	for _, v := range t {
+		fmt.Fprintf(w, "%d\t%s\t%d%%\t%s\t%s\t%s\t%s\t%s\n", v.ID, check(v.Complete), v.Progress, priority(v.Priority), stars(v.Star), optional(v.CategoryID), optional(v.ParentID), v.Title)
+	}
+	return w.Flush()
+}
+
+func printCategories(c []io.TodoCategory) error {
+	if *output == "json" {
+		return printJSON(c)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPARENT\tNAME")
+	for _, v := range c {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", v.ID, optional(v.ParentID), v.Name)
+	}
+	return w.Flush()
+}
+
+// printCategoryTree lists the categories depth first, each by its path.
+func printCategoryTree(nodes []io.CategoryNode) error {
+	if *output == "json" {
+		return printJSON(nodes)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tOPEN\tDONE\tPATH")
+	var walk func(nodes []io.CategoryNode)
+	walk = func(nodes []io.CategoryNode) {
+		for _, n := range nodes {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\n", n.ID, n.Open, n.Completed, n.Path)
+			walk(n.Children)
+		}
+	}
+	walk(nodes)
+	return w.Flush()
+}
+
+func printBoard(b io.Board) error {
+	if *output == "json" {
+		return printJSON(b)
+	}
+	for i, c := range b.Columns {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d)\n", c.Status, len(c.Todos))
+		for _, v := range c.Todos {
+			fmt.Printf("  %s (#%d)\n", v.Title, v.ID)
+		}
+	}
+	return nil
+}
+
+func printMatrix(m io.Matrix) error {
+	if *output == "json" {
+		return printJSON(m)
+	}
+	quadrants := []struct {
+		name  string
+		todos []io.Todo
+	}{
+		{"Do first (urgent, important)", m.DoFirst},
+		{"Schedule (important)", m.Schedule},
+		{"Delegate (urgent)", m.Delegate},
+		{"Eliminate", m.Eliminate},
+	}
+	for i, q := range quadrants {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s (%d)\n", q.name, len(q.todos))
+		for _, v := range q.todos {
+			due := ""
+			if v.Due != nil {
+				due = " due " + v.Due.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Printf("  %s %s (#%d)%s\n", priority(v.Priority), v.Title, v.ID, due)
+		}
+	}
+	return nil
+}
+
+// printPlan lists the planned todos by earliest start, in hours from the
+// start of the plan, marking the critical ones with * and the ones that
+// can not make their due date with !.
+func printPlan(p io.Plan) error {
+	if *output == "json" {
+		return printJSON(p)
+	}
+	todos := append([]io.PlannedTodo{}, p.Todos...)
+	sort.SliceStable(todos, func(i, j int) bool { return todos[i].EarliestStart < todos[j].EarliestStart })
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEST\tSTART\tFINISH\tSLACK\t\tTITLE")
+	for _, v := range todos {
+		mark := ""
+		if v.Critical {
+			mark += "*"
+		}
+		if v.Infeasible {
+			mark += "!"
+		}
+		title := v.Title
+		if len(v.BlockedBy) > 0 {
+			title += fmt.Sprintf(" (waits for %s)", strings.Trim(fmt.Sprint(v.BlockedBy), "[]"))
+		}
+		fmt.Fprintf(w, "%d\t%g\t%g\t%g\t%g\t%s\t%s\n", v.ID, v.Estimate, v.EarliestStart, v.EarliestFinish, v.Slack, mark, title)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	finish := p.Start.Add(time.Duration(p.Duration * float64(time.Hour)))
+	fmt.Printf("\n%gh, done by %s\n", p.Duration, finish.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+func printTimeEntries(e []io.TimeEntry) error {
+	if *output == "json" {
+		return printJSON(e)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTODO\tUSER\tSTART\tEND\tHOURS\tNOTE")
+	for _, v := range e {
+		end := "running"
+		if v.EndedAt != nil {
+			end = v.EndedAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%.2f\t%s\n", v.ID, v.TodoID, v.User, v.StartedAt.Local().Format("2006-01-02 15:04"), end, v.Hours, v.Note)
+	}
+	return w.Flush()
+}
+
+// printTimeReport lists the hours per todo, then per category, each
+// category counting its sub categories.
+func printTimeReport(r io.TimeReport) error {
+	if *output == "json" {
+		return printJSON(r)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TODO\tHOURS\tTITLE")
+	for _, v := range r.Todos {
+		fmt.Fprintf(w, "%d\t%.2f\t%s\n", v.TodoID, v.Hours, v.Title)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "CAT\tHOURS\tPATH")
+	for _, v := range r.Categories {
+		fmt.Fprintf(w, "%s\t%.2f\t%s\n", optional(v.CategoryID), v.Hours, v.Path)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%.2fh in total\n", r.Hours)
+	return nil
+}
+
+func printBurndown(b io.Burndown) error {
+	if *output == "json" {
+		return printJSON(b)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "DAY\tSCOPE\tDONE\tLEFT\tIDEAL\t(%s)\n", b.Unit)
+	for _, v := range b.Series {
+		fmt.Fprintf(w, "%s\t%g\t%g\t%g\t%.1f\t\n", v.Time.Local().Format("2006-01-02"), v.Scope, v.Completed, v.Remaining, v.Ideal)
+	}
+	return w.Flush()
+}
+
+// printHistory lists the changes, the oldest first, each with the fields
+// it changed.
+func printHistory(h []io.Change) error {
+	if *output == "json" {
+		return printJSON(h)
+	}
+	for _, c := range h {
+		actor := c.Actor
+		if actor == "" {
+			actor = "-"
+		}
+		fmt.Printf("%s %s by %s (request %s)\n", c.CreatedAt.Local().Format("2006-01-02 15:04:05"), c.Operation, actor, c.RequestID)
+		for _, f := range c.Diff {
+			fmt.Printf("  %s: %v -> %v\n", f.Field, f.Before, f.After)
+		}
+	}
+	return nil
+}
+
+// printGraph writes the dependency graph in the Graphviz DOT language,
+// with arrows from blockers to the todos waiting for them.
+func printGraph(g io.DependencyGraph) error {
+	if *output == "json" {
+		return printJSON(g)
+	}
+	fmt.Println("digraph dependencies {")
+	for _, v := range g.Nodes {
+		style := ""
+		if v.Complete {
+			style = ", style=dashed"
+		}
+		fmt.Printf("  %d [label=%q%s];\n", v.ID, v.Title, style)
+	}
+	for _, v := range g.Edges {
+		fmt.Printf("  %d -> %d;\n", v.BlockerID, v.TodoID)
+	}
+	fmt.Println("}")
+	return nil
+}
+
+func printTree(nodes []io.TodoNode) error {
+	if *output == "json" {
+		return printJSON(nodes)
+	}
+	var walk func(nodes []io.TodoNode, prefix string)
+	walk = func(nodes []io.TodoNode, prefix string) {
+		for i, n := range nodes {
+			branch, next := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, next = "└── ", "    "
+			}
+			progress := ""
+			if n.Total > 0 {
+				progress = fmt.Sprintf(" %d/%d", n.Completed, n.Total)
+			}
+			fmt.Printf("%s%s[%s] %s (#%d)%s\n", prefix, branch, check(n.Complete), n.Title, n.ID, progress)
+			walk(n.Children, prefix+next)
+		}
+	}
+	walk(nodes, "")
+	return nil
+}
+
+func check(complete bool) string {
+	if complete {
+		return "x"
+	}
+	return " "
+}
+
+func stars(n uint8) string {
+	return strings.Repeat("*", int(n))
+}
+
+func priority(p uint8) string {
+	if p == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("P%d", p)
+}
+
+func optional(id uint) string {
+	if id == 0 {
+		return "-"
+	}
+	return fmt.Sprint(id)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	io "todo/pkg/io"
+	service "todo/pkg/service"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const uiHelp = "tab switch pane  enter expand  space done  0-5 star  e edit  a add  r add subtask  q quit"
+
+// tui is the full-screen client started by the ui command. The sidebar is
+// the category tree, the main pane lists the todos of the selected category
+// with their subtasks.
+type tui struct {
+	ctx        context.Context
+	svc        service.TodoService
+	app        *tview.Application
+	pages      *tview.Pages
+	categories *tview.TreeView
+	todos      *tview.TreeView
+	status     *tview.TextView
+	category   uint
+}
+
+func ui(ctx context.Context, svc service.TodoService, args []string) error {
+	u := &tui{
+		ctx:        ctx,
+		svc:        svc,
+		app:        tview.NewApplication(),
+		pages:      tview.NewPages(),
+		categories: tview.NewTreeView(),
+		todos:      tview.NewTreeView(),
+		status:     tview.NewTextView(),
+	}
+
+	root := tview.NewTreeNode("All").SetReference(uint(0))
+	u.categories.SetRoot(root).SetCurrentNode(root)
+	u.categories.SetChangedFunc(u.selectCategory)
+	u.categories.SetSelectedFunc(u.expandCategory)
+	u.categories.SetBorder(true).SetTitle("Categories")
+	u.expandCategory(root)
+
+	u.todos.SetRoot(tview.NewTreeNode("")).SetTopLevel(1)
+	u.todos.SetSelectedFunc(u.expandTodo)
+	u.todos.SetInputCapture(u.todoKeys)
+	u.todos.SetBorder(true).SetTitle("Todos")
+	u.loadTodos()
+
+	u.status.SetText(uiHelp)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(u.categories, 0, 1, false).
+			AddItem(u.todos, 0, 3, true), 0, 1, true).
+		AddItem(u.status, 1, 0, false)
+	u.pages.AddPage("main", layout, true, true)
+
+	u.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if name, _ := u.pages.GetFrontPage(); name != "main" {
+			return event
+		}
+		switch {
+		case event.Key() == tcell.KeyTab:
+			if u.categories.HasFocus() {
+				u.app.SetFocus(u.todos)
+			} else {
+				u.app.SetFocus(u.categories)
+			}
+			return nil
+		case event.Rune() == 'q':
+			u.app.Stop()
+			return nil
+		}
+		return event
+	})
+	return u.app.SetRoot(u.pages, true).SetFocus(u.todos).Run()
+}
+
+// report shows err in the status line, or the key help when err is nil.
+func (u *tui) report(err error) bool {
+	if err != nil {
+		u.status.SetText("[red]" + tview.Escape(err.Error()))
+		return false
+	}
+	u.status.SetText(uiHelp)
+	return true
+}
+
+func (u *tui) selectCategory(node *tview.TreeNode) {
+	u.category = node.GetReference().(uint)
+	u.loadTodos()
+}
+
+// expandCategory loads the sub categories of node the first time it is
+// expanded and toggles it afterwards.
+func (u *tui) expandCategory(node *tview.TreeNode) {
+	if len(node.GetChildren()) > 0 {
+		node.SetExpanded(!node.IsExpanded())
+		return
+	}
+	id := strconv.FormatUint(uint64(node.GetReference().(uint)), 10)
+	c, err := u.svc.GetCatChildes(u.ctx, id)
+	if !u.report(err) {
+		return
+	}
+	for _, v := range c {
+		node.AddChild(tview.NewTreeNode(tview.Escape(v.Name)).SetReference(v.ID).SetColor(tcell.ColorGreen))
+	}
+	node.SetExpanded(true)
+}
+
+// loadTodos lists the top level todos of the selected category.
+func (u *tui) loadTodos() {
+	root := u.todos.GetRoot().ClearChildren()
+	t, err := u.svc.Get(u.ctx)
+	if !u.report(err) {
+		return
+	}
+	for i := range t {
+		if t[i].ParentID != 0 || (u.category != 0 && t[i].CategoryID != u.category) {
+			continue
+		}
+		root.AddChild(todoNode(&t[i]))
+	}
+	if children := root.GetChildren(); len(children) > 0 {
+		u.todos.SetCurrentNode(children[0])
+	}
+}
+
+func todoNode(t *io.Todo) *tview.TreeNode {
+	node := tview.NewTreeNode("").SetReference(t).SetExpanded(false)
+	renderTodo(node)
+	return node
+}
+
+func renderTodo(node *tview.TreeNode) {
+	t := node.GetReference().(*io.Todo)
+	text := fmt.Sprintf("[%s] %s %s", check(t.Complete), t.Title, stars(t.Star))
+	node.SetText(tview.Escape(strings.TrimSpace(text)))
+	if t.Complete {
+		node.SetColor(tcell.ColorGray)
+	} else {
+		node.SetColor(tcell.ColorWhite)
+	}
+}
+
+// expandTodo loads the subtasks of node the first time it is expanded and
+// toggles it afterwards.
+func (u *tui) expandTodo(node *tview.TreeNode) {
+	if len(node.GetChildren()) > 0 {
+		node.SetExpanded(!node.IsExpanded())
+		return
+	}
+	u.loadChildes(node)
+}
+
+func (u *tui) loadChildes(node *tview.TreeNode) {
+	t := node.GetReference().(*io.Todo)
+	childes, err := u.svc.GetChildes(u.ctx, strconv.FormatUint(uint64(t.ID), 10))
+	if !u.report(err) {
+		return
+	}
+	node.ClearChildren()
+	for i := range childes {
+		node.AddChild(todoNode(&childes[i]))
+	}
+	node.SetExpanded(true)
+}
+
+func (u *tui) todoKeys(event *tcell.EventKey) *tcell.EventKey {
+	node := u.todos.GetCurrentNode()
+	var t *io.Todo
+	if node != nil && node != u.todos.GetRoot() {
+		t = node.GetReference().(*io.Todo)
+	}
+	r := event.Rune()
+	switch {
+	case r == 'a':
+		u.edit("Add todo", io.Todo{CategoryID: u.category}, func(v io.Todo) error {
+			_, err := u.svc.Add(u.ctx, v)
+			if err == nil {
+				u.loadTodos()
+			}
+			return err
+		})
+	case t == nil:
+		return event
+	case r == ' ':
+		id := strconv.FormatUint(uint64(t.ID), 10)
+		var err error
+		if t.Complete {
+			err = u.svc.RemoveComplete(u.ctx, id)
+		} else {
+			err = u.svc.SetComplete(u.ctx, id)
+		}
+		if u.report(err) {
+			t.Complete = !t.Complete
+			renderTodo(node)
+		}
+	case r >= '0' && r <= '5':
+		star := uint8(r - '0')
+		if u.report(u.svc.SetStar(u.ctx, strconv.FormatUint(uint64(t.ID), 10), star)) {
+			t.Star = star
+			renderTodo(node)
+		}
+	case r == 'e':
+		u.edit("Edit todo", *t, func(v io.Todo) error {
+			updated, err := u.svc.Update(u.ctx, v)
+			if err == nil {
+				*t = updated
+				renderTodo(node)
+			}
+			return err
+		})
+	case r == 'r':
+		u.edit("Add subtask", io.Todo{CategoryID: t.CategoryID}, func(v io.Todo) error {
+			_, err := u.svc.ReplyTo(u.ctx, t.ID, v)
+			if err == nil {
+				u.loadChildes(node)
+			}
+			return err
+		})
+	default:
+		return event
+	}
+	return nil
+}
+
+// edit shows a form for the title and description of t and calls save with
+// the edited todo.
+func (u *tui) edit(title string, t io.Todo, save func(io.Todo) error) {
+	done := func() {
+		u.pages.RemovePage("edit")
+		u.app.SetFocus(u.todos)
+	}
+	form := tview.NewForm().
+		AddInputField("Title", t.Title, 60, nil, func(text string) { t.Title = text }).
+		AddInputField("Description", t.Description, 60, nil, func(text string) { t.Description = text })
+	form.AddButton("Save", func() {
+		if u.report(save(t)) {
+			done()
+		}
+	}).AddButton("Cancel", done)
+	form.SetCancelFunc(done)
+	form.SetBorder(true).SetTitle(title)
+
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 9, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+	u.pages.AddPage("edit", modal, true, true)
+	u.app.SetFocus(form)
+}
@@ -3,16 +3,24 @@ module todo
 go 1.14
 
 require (
+	github.com/apache/thrift v0.13.0
+	github.com/gdamore/tcell v1.3.0
 	github.com/go-kit/kit v0.10.0
 	github.com/gorilla/handlers v1.4.2
 	github.com/gorilla/mux v1.7.4
+	github.com/gorilla/websocket v1.4.2
+	github.com/graph-gophers/graphql-go v0.0.0-20200309224638-dae41bde9ef9
 	github.com/jinzhu/gorm v1.9.12
+	github.com/lib/pq v1.1.1
 	github.com/lightstep/lightstep-tracer-go v0.20.0
+	github.com/nats-io/nats.go v1.9.1
+	github.com/nats-io/nkeys v0.1.4 // indirect
 	github.com/oklog/oklog v0.3.2
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/openzipkin-contrib/zipkin-go-opentracing v0.4.5
 	github.com/openzipkin/zipkin-go v0.2.2
 	github.com/prometheus/client_golang v1.5.1
+	github.com/rivo/tview v0.0.0-20200404204604-ca37f83cb2e7
 	github.com/spf13/viper v1.6.3
 	sourcegraph.com/sourcegraph/appdash v0.0.0-20190731080439-ebfcffb1b5c0
 )
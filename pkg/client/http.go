@@ -0,0 +1,1469 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	http1 "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	endpoint1 "todo/pkg/endpoint"
+	http2 "todo/pkg/http"
+	service "todo/pkg/service"
+
+	endpoint "github.com/go-kit/kit/endpoint"
+	log "github.com/go-kit/kit/log"
+	opentracing "github.com/go-kit/kit/tracing/opentracing"
+	http "github.com/go-kit/kit/transport/http"
+	opentracinggo "github.com/opentracing/opentracing-go"
+)
+
+// New returns a TodoService backed by an HTTP server living at the remote
+// instance. instance is either "host:port" or a base URL such as
+// "https://example.com/todo"; routes are resolved relative to its path.
+// options are keyed by method name, see AllMethods to apply the same
+// options to every method.
+func New(instance string, options map[string][]http.ClientOption) (service.TodoService, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+	var getEndpoint endpoint.Endpoint
+	{
+		getEndpoint = http.NewClient("GET", copyURL(u, "/"), encodeHTTPGenericRequest, decodeGetResponse, options["Get"]...).Endpoint()
+	}
+	var addEndpoint endpoint.Endpoint
+	{
+		addEndpoint = http.NewClient("POST", copyURL(u, "/add"), encodeAddRequest, decodeAddResponse, options["Add"]...).Endpoint()
+	}
+	var setCompleteEndpoint endpoint.Endpoint
+	{
+		setCompleteEndpoint = http.NewClient("PUT", copyURL(u, "/set-complete"), encodeHTTPGenericRequest, decodeSetCompleteResponse, options["SetComplete"]...).Endpoint()
+	}
+	var removeCompleteEndpoint endpoint.Endpoint
+	{
+		removeCompleteEndpoint = http.NewClient("PUT", copyURL(u, "/remove-complete"), encodeHTTPGenericRequest, decodeRemoveCompleteResponse, options["RemoveComplete"]...).Endpoint()
+	}
+	var deleteEndpoint endpoint.Endpoint
+	{
+		deleteEndpoint = http.NewClient("DELETE", copyURL(u, "/delete"), encodeDeleteRequest, decodeDeleteResponse, options["Delete"]...).Endpoint()
+	}
+	var updateEndpoint endpoint.Endpoint
+	{
+		updateEndpoint = http.NewClient("PUT", copyURL(u, "/update"), encodeUpdateRequest, decodeUpdateResponse, options["Update"]...).Endpoint()
+	}
+	var setStarEndpoint endpoint.Endpoint
+	{
+		setStarEndpoint = http.NewClient("PUT", copyURL(u, "/set-star"), encodeHTTPGenericRequest, decodeSetStarResponse, options["SetStar"]...).Endpoint()
+	}
+	var replyToEndpoint endpoint.Endpoint
+	{
+		replyToEndpoint = http.NewClient("POST", copyURL(u, "/reply-to"), encodeHTTPGenericRequest, decodeReplyToResponse, options["ReplyTo"]...).Endpoint()
+	}
+	var getChildesEndpoint endpoint.Endpoint
+	{
+		getChildesEndpoint = http.NewClient("GET", copyURL(u, "/get-childes"), encodeGetChildesRequest, decodeGetChildesResponse, options["GetChildes"]...).Endpoint()
+	}
+	var getCategoryEndpoint endpoint.Endpoint
+	{
+		getCategoryEndpoint = http.NewClient("GET", copyURL(u, "/get-category"), encodeHTTPGenericRequest, decodeGetCategoryResponse, options["GetCategory"]...).Endpoint()
+	}
+	var addCategoryEndpoint endpoint.Endpoint
+	{
+		addCategoryEndpoint = http.NewClient("POST", copyURL(u, "/add-category"), encodeAddCategoryRequest, decodeAddCategoryResponse, options["AddCategory"]...).Endpoint()
+	}
+	var updateCategoryEndpoint endpoint.Endpoint
+	{
+		updateCategoryEndpoint = http.NewClient("PUT", copyURL(u, "/update-category"), encodeUpdateCategoryRequest, decodeUpdateCategoryResponse, options["UpdateCategory"]...).Endpoint()
+	}
+	var deleteCategoryEndpoint endpoint.Endpoint
+	{
+		deleteCategoryEndpoint = http.NewClient("DELETE", copyURL(u, "/delete-category"), encodeHTTPGenericRequest, decodeDeleteCategoryResponse, options["DeleteCategory"]...).Endpoint()
+	}
+	var getCatChildesEndpoint endpoint.Endpoint
+	{
+		getCatChildesEndpoint = http.NewClient("GET", copyURL(u, "/get-cat-childes"), encodeHTTPGenericRequest, decodeGetCatChildesResponse, options["GetCatChildes"]...).Endpoint()
+	}
+	var addWebhookEndpoint endpoint.Endpoint
+	{
+		addWebhookEndpoint = http.NewClient("POST", copyURL(u, "/webhooks"), encodeHTTPGenericRequest, decodeAddWebhookResponse, options["AddWebhook"]...).Endpoint()
+	}
+	var getWebhooksEndpoint endpoint.Endpoint
+	{
+		getWebhooksEndpoint = http.NewClient("GET", copyURL(u, "/webhooks"), encodeHTTPGenericRequest, decodeGetWebhooksResponse, options["GetWebhooks"]...).Endpoint()
+	}
+	var deleteWebhookEndpoint endpoint.Endpoint
+	{
+		deleteWebhookEndpoint = http.NewClient("DELETE", copyURL(u, "/webhooks/{id}"), encodeDeleteWebhookRequest, decodeDeleteWebhookResponse, options["DeleteWebhook"]...).Endpoint()
+	}
+	var getWebhookDeliveriesEndpoint endpoint.Endpoint
+	{
+		getWebhookDeliveriesEndpoint = http.NewClient("GET", copyURL(u, "/webhooks/{id}/deliveries"), encodeGetWebhookDeliveriesRequest, decodeGetWebhookDeliveriesResponse, options["GetWebhookDeliveries"]...).Endpoint()
+	}
+	var getDeadLettersEndpoint endpoint.Endpoint
+	{
+		getDeadLettersEndpoint = http.NewClient("GET", copyURL(u, "/webhooks/dead-letters"), encodeHTTPGenericRequest, decodeGetDeadLettersResponse, options["GetDeadLetters"]...).Endpoint()
+	}
+	var retryDeliveryEndpoint endpoint.Endpoint
+	{
+		retryDeliveryEndpoint = http.NewClient("POST", copyURL(u, "/webhooks/deliveries/{id}/retry"), encodeRetryDeliveryRequest, decodeRetryDeliveryResponse, options["RetryDelivery"]...).Endpoint()
+	}
+	var getTreeEndpoint endpoint.Endpoint
+	{
+		getTreeEndpoint = http.NewClient("GET", copyURL(u, "/todos/{id}/tree"), encodeGetTreeRequest, decodeGetTreeResponse, options["GetTree"]...).Endpoint()
+	}
+	var moveEndpoint endpoint.Endpoint
+	{
+		moveEndpoint = http.NewClient("PUT", copyURL(u, "/todos/{id}/move"), encodeMoveRequest, decodeMoveResponse, options["Move"]...).Endpoint()
+	}
+	var moveCategoryEndpoint endpoint.Endpoint
+	{
+		moveCategoryEndpoint = http.NewClient("PUT", copyURL(u, "/categories/{id}/move"), encodeMoveCategoryRequest, decodeMoveCategoryResponse, options["MoveCategory"]...).Endpoint()
+	}
+	var listTrashEndpoint endpoint.Endpoint
+	{
+		listTrashEndpoint = http.NewClient("GET", copyURL(u, "/trash"), encodeHTTPGenericRequest, decodeListTrashResponse, options["ListTrash"]...).Endpoint()
+	}
+	var restoreEndpoint endpoint.Endpoint
+	{
+		restoreEndpoint = http.NewClient("POST", copyURL(u, "/trash/{kind}/{id}/restore"), encodeRestoreRequest, decodeRestoreResponse, options["Restore"]...).Endpoint()
+	}
+	var purgeEndpoint endpoint.Endpoint
+	{
+		purgeEndpoint = http.NewClient("DELETE", copyURL(u, "/trash/{kind}/{id}"), encodePurgeRequest, decodePurgeResponse, options["Purge"]...).Endpoint()
+	}
+	var getCategoryTreeEndpoint endpoint.Endpoint
+	{
+		getCategoryTreeEndpoint = http.NewClient("GET", copyURL(u, "/categories/tree"), encodeHTTPGenericRequest, decodeGetCategoryTreeResponse, options["GetCategoryTree"]...).Endpoint()
+	}
+	var getCategoryPathEndpoint endpoint.Endpoint
+	{
+		getCategoryPathEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/path"), encodeGetCategoryPathRequest, decodeGetCategoryPathResponse, options["GetCategoryPath"]...).Endpoint()
+	}
+	var reorderEndpoint endpoint.Endpoint
+	{
+		reorderEndpoint = http.NewClient("PUT", copyURL(u, "/todos/{id}/reorder"), encodeReorderRequest, decodeReorderResponse, options["Reorder"]...).Endpoint()
+	}
+	var transitionEndpoint endpoint.Endpoint
+	{
+		transitionEndpoint = http.NewClient("PUT", copyURL(u, "/todos/{id}/transition"), encodeTransitionRequest, decodeTransitionResponse, options["Transition"]...).Endpoint()
+	}
+	var getWorkflowEndpoint endpoint.Endpoint
+	{
+		getWorkflowEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/workflow"), encodeGetWorkflowRequest, decodeGetWorkflowResponse, options["GetWorkflow"]...).Endpoint()
+	}
+	var setWorkflowEndpoint endpoint.Endpoint
+	{
+		setWorkflowEndpoint = http.NewClient("PUT", copyURL(u, "/categories/{id}/workflow"), encodeSetWorkflowRequest, decodeSetWorkflowResponse, options["SetWorkflow"]...).Endpoint()
+	}
+	var getBoardEndpoint endpoint.Endpoint
+	{
+		getBoardEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/board"), encodeGetBoardRequest, decodeGetBoardResponse, options["GetBoard"]...).Endpoint()
+	}
+	var setPriorityEndpoint endpoint.Endpoint
+	{
+		setPriorityEndpoint = http.NewClient("PUT", copyURL(u, "/todos/{id}/priority"), encodeSetPriorityRequest, decodeSetPriorityResponse, options["SetPriority"]...).Endpoint()
+	}
+	var getMatrixEndpoint endpoint.Endpoint
+	{
+		getMatrixEndpoint = http.NewClient("GET", copyURL(u, "/todos/matrix"), encodeGetMatrixRequest, decodeGetMatrixResponse, options["GetMatrix"]...).Endpoint()
+	}
+	var addDependencyEndpoint endpoint.Endpoint
+	{
+		addDependencyEndpoint = http.NewClient("POST", copyURL(u, "/todos/{id}/dependencies"), encodeAddDependencyRequest, decodeAddDependencyResponse, options["AddDependency"]...).Endpoint()
+	}
+	var removeDependencyEndpoint endpoint.Endpoint
+	{
+		removeDependencyEndpoint = http.NewClient("DELETE", copyURL(u, "/todos/{id}/dependencies/{blocker_id}"), encodeRemoveDependencyRequest, decodeRemoveDependencyResponse, options["RemoveDependency"]...).Endpoint()
+	}
+	var getBlockedEndpoint endpoint.Endpoint
+	{
+		getBlockedEndpoint = http.NewClient("GET", copyURL(u, "/todos/blocked"), encodeHTTPGenericRequest, decodeGetBlockedResponse, options["GetBlocked"]...).Endpoint()
+	}
+	var getReadyEndpoint endpoint.Endpoint
+	{
+		getReadyEndpoint = http.NewClient("GET", copyURL(u, "/todos/ready"), encodeHTTPGenericRequest, decodeGetReadyResponse, options["GetReady"]...).Endpoint()
+	}
+	var getDependencyGraphEndpoint endpoint.Endpoint
+	{
+		getDependencyGraphEndpoint = http.NewClient("GET", copyURL(u, "/dependencies/graph"), encodeHTTPGenericRequest, decodeGetDependencyGraphResponse, options["GetDependencyGraph"]...).Endpoint()
+	}
+	var getCategoryPlanEndpoint endpoint.Endpoint
+	{
+		getCategoryPlanEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/plan"), encodeGetCategoryPlanRequest, decodeGetCategoryPlanResponse, options["GetCategoryPlan"]...).Endpoint()
+	}
+	var getTreePlanEndpoint endpoint.Endpoint
+	{
+		getTreePlanEndpoint = http.NewClient("GET", copyURL(u, "/todos/{id}/plan"), encodeGetTreePlanRequest, decodeGetTreePlanResponse, options["GetTreePlan"]...).Endpoint()
+	}
+	var startTimerEndpoint endpoint.Endpoint
+	{
+		startTimerEndpoint = http.NewClient("POST", copyURL(u, "/todos/{id}/timer"), encodeStartTimerRequest, decodeStartTimerResponse, options["StartTimer"]...).Endpoint()
+	}
+	var stopTimerEndpoint endpoint.Endpoint
+	{
+		stopTimerEndpoint = http.NewClient("POST", copyURL(u, "/timers/stop"), encodeHTTPGenericRequest, decodeStopTimerResponse, options["StopTimer"]...).Endpoint()
+	}
+	var addTimeEntryEndpoint endpoint.Endpoint
+	{
+		addTimeEntryEndpoint = http.NewClient("POST", copyURL(u, "/todos/{id}/time"), encodeAddTimeEntryRequest, decodeAddTimeEntryResponse, options["AddTimeEntry"]...).Endpoint()
+	}
+	var getTimeEntriesEndpoint endpoint.Endpoint
+	{
+		getTimeEntriesEndpoint = http.NewClient("GET", copyURL(u, "/todos/{id}/time"), encodeGetTimeEntriesRequest, decodeGetTimeEntriesResponse, options["GetTimeEntries"]...).Endpoint()
+	}
+	var updateTimeEntryEndpoint endpoint.Endpoint
+	{
+		updateTimeEntryEndpoint = http.NewClient("PUT", copyURL(u, "/time/{id}"), encodeUpdateTimeEntryRequest, decodeUpdateTimeEntryResponse, options["UpdateTimeEntry"]...).Endpoint()
+	}
+	var deleteTimeEntryEndpoint endpoint.Endpoint
+	{
+		deleteTimeEntryEndpoint = http.NewClient("DELETE", copyURL(u, "/time/{id}"), encodeDeleteTimeEntryRequest, decodeDeleteTimeEntryResponse, options["DeleteTimeEntry"]...).Endpoint()
+	}
+	var getTimeReportEndpoint endpoint.Endpoint
+	{
+		getTimeReportEndpoint = http.NewClient("GET", copyURL(u, "/time/report"), encodeGetTimeReportRequest, decodeGetTimeReportResponse, options["GetTimeReport"]...).Endpoint()
+	}
+	var getBurndownEndpoint endpoint.Endpoint
+	{
+		getBurndownEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/burndown"), encodeGetBurndownRequest, decodeGetBurndownResponse, options["GetBurndown"]...).Endpoint()
+	}
+	var getTodoHistoryEndpoint endpoint.Endpoint
+	{
+		getTodoHistoryEndpoint = http.NewClient("GET", copyURL(u, "/todos/{id}/history"), encodeGetTodoHistoryRequest, decodeGetTodoHistoryResponse, options["GetTodoHistory"]...).Endpoint()
+	}
+	var getCategoryHistoryEndpoint endpoint.Endpoint
+	{
+		getCategoryHistoryEndpoint = http.NewClient("GET", copyURL(u, "/categories/{id}/history"), encodeGetCategoryHistoryRequest, decodeGetCategoryHistoryResponse, options["GetCategoryHistory"]...).Endpoint()
+	}
+	var undoEndpoint endpoint.Endpoint
+	{
+		undoEndpoint = http.NewClient("POST", copyURL(u, "/undo"), encodeHTTPGenericRequest, decodeUndoResponse, options["Undo"]...).Endpoint()
+	}
+	var redoEndpoint endpoint.Endpoint
+	{
+		redoEndpoint = http.NewClient("POST", copyURL(u, "/redo"), encodeHTTPGenericRequest, decodeRedoResponse, options["Redo"]...).Endpoint()
+	}
+	var findTodosEndpoint endpoint.Endpoint
+	{
+		findTodosEndpoint = http.NewClient("POST", copyURL(u, "/todos/find"), encodeHTTPGenericRequest, decodeFindTodosResponse, options["FindTodos"]...).Endpoint()
+	}
+	var findCategoriesEndpoint endpoint.Endpoint
+	{
+		findCategoriesEndpoint = http.NewClient("POST", copyURL(u, "/categories/find"), encodeHTTPGenericRequest, decodeFindCategoriesResponse, options["FindCategories"]...).Endpoint()
+	}
+
+	return endpoint1.Endpoints{
+		AddCategoryEndpoint:          addCategoryEndpoint,
+		AddEndpoint:                  addEndpoint,
+		DeleteCategoryEndpoint:       deleteCategoryEndpoint,
+		DeleteEndpoint:               deleteEndpoint,
+		GetCatChildesEndpoint:        getCatChildesEndpoint,
+		GetCategoryEndpoint:          getCategoryEndpoint,
+		GetChildesEndpoint:           getChildesEndpoint,
+		GetEndpoint:                  getEndpoint,
+		RemoveCompleteEndpoint:       removeCompleteEndpoint,
+		ReplyToEndpoint:              replyToEndpoint,
+		SetCompleteEndpoint:          setCompleteEndpoint,
+		SetStarEndpoint:              setStarEndpoint,
+		UpdateCategoryEndpoint:       updateCategoryEndpoint,
+		UpdateEndpoint:               updateEndpoint,
+		AddWebhookEndpoint:           addWebhookEndpoint,
+		GetWebhooksEndpoint:          getWebhooksEndpoint,
+		DeleteWebhookEndpoint:        deleteWebhookEndpoint,
+		GetWebhookDeliveriesEndpoint: getWebhookDeliveriesEndpoint,
+		GetDeadLettersEndpoint:       getDeadLettersEndpoint,
+		RetryDeliveryEndpoint:        retryDeliveryEndpoint,
+		GetTreeEndpoint:              getTreeEndpoint,
+		MoveEndpoint:                 moveEndpoint,
+		MoveCategoryEndpoint:         moveCategoryEndpoint,
+		ListTrashEndpoint:            listTrashEndpoint,
+		RestoreEndpoint:              restoreEndpoint,
+		PurgeEndpoint:                purgeEndpoint,
+		GetCategoryTreeEndpoint:      getCategoryTreeEndpoint,
+		GetCategoryPathEndpoint:      getCategoryPathEndpoint,
+		ReorderEndpoint:              reorderEndpoint,
+		TransitionEndpoint:           transitionEndpoint,
+		GetWorkflowEndpoint:          getWorkflowEndpoint,
+		SetWorkflowEndpoint:          setWorkflowEndpoint,
+		GetBoardEndpoint:             getBoardEndpoint,
+		SetPriorityEndpoint:          setPriorityEndpoint,
+		GetMatrixEndpoint:            getMatrixEndpoint,
+		AddDependencyEndpoint:        addDependencyEndpoint,
+		RemoveDependencyEndpoint:     removeDependencyEndpoint,
+		GetBlockedEndpoint:           getBlockedEndpoint,
+		GetReadyEndpoint:             getReadyEndpoint,
+		GetDependencyGraphEndpoint:   getDependencyGraphEndpoint,
+		GetCategoryPlanEndpoint:      getCategoryPlanEndpoint,
+		GetTreePlanEndpoint:          getTreePlanEndpoint,
+		StartTimerEndpoint:           startTimerEndpoint,
+		StopTimerEndpoint:            stopTimerEndpoint,
+		AddTimeEntryEndpoint:         addTimeEntryEndpoint,
+		GetTimeEntriesEndpoint:       getTimeEntriesEndpoint,
+		UpdateTimeEntryEndpoint:      updateTimeEntryEndpoint,
+		DeleteTimeEntryEndpoint:      deleteTimeEntryEndpoint,
+		GetTimeReportEndpoint:        getTimeReportEndpoint,
+		GetBurndownEndpoint:          getBurndownEndpoint,
+		GetTodoHistoryEndpoint:       getTodoHistoryEndpoint,
+		GetCategoryHistoryEndpoint:   getCategoryHistoryEndpoint,
+		UndoEndpoint:                 undoEndpoint,
+		RedoEndpoint:                 redoEndpoint,
+		FindTodosEndpoint:            findTodosEndpoint,
+		FindCategoriesEndpoint:       findCategoriesEndpoint,
+	}, nil
+}
+
+// AllMethods returns an options map that applies options to every method
+// of the client.
+func AllMethods(options ...http.ClientOption) map[string][]http.ClientOption {
+	methods := []string{"Get", "Add", "SetComplete", "RemoveComplete", "Delete", "Update", "SetStar", "ReplyTo", "GetChildes", "GetCategory", "AddCategory", "UpdateCategory", "DeleteCategory", "GetCatChildes", "AddWebhook", "GetWebhooks", "DeleteWebhook", "GetWebhookDeliveries", "GetDeadLetters", "RetryDelivery", "GetTree", "Move", "MoveCategory", "ListTrash", "Restore", "Purge", "GetCategoryTree", "GetCategoryPath", "Reorder", "Transition", "GetWorkflow", "SetWorkflow", "GetBoard", "SetPriority", "GetMatrix", "AddDependency", "RemoveDependency", "GetBlocked", "GetReady", "GetDependencyGraph", "GetCategoryPlan", "GetTreePlan", "StartTimer", "StopTimer", "AddTimeEntry", "GetTimeEntries", "UpdateTimeEntry", "DeleteTimeEntry", "GetTimeReport", "GetBurndown", "GetTodoHistory", "GetCategoryHistory", "Undo", "Redo", "FindTodos", "FindCategories"}
+	m := map[string][]http.ClientOption{}
+	for _, v := range methods {
+		m[v] = append(m[v], options...)
+	}
+	return m
+}
+
+// Timeout bounds every request, including reading the response body.
+func Timeout(d time.Duration) http.ClientOption {
+	return http.SetClient(&http1.Client{Timeout: d})
+}
+
+// Actor names actor as the author of the changes, in the X-Actor header.
+func Actor(actor string) http.ClientOption {
+	return http.ClientBefore(func(ctx context.Context, r *http1.Request) context.Context {
+		r.Header.Set("X-Actor", actor)
+		return ctx
+	})
+}
+
+// AsOf asks for the todos and categories as they were at asOf, a day or an
+// RFC 3339 time, in the as_of query parameter.
+func AsOf(asOf string) http.ClientOption {
+	return http.ClientBefore(func(ctx context.Context, r *http1.Request) context.Context {
+		q := r.URL.Query()
+		q.Set("as_of", asOf)
+		r.URL.RawQuery = q.Encode()
+		return ctx
+	})
+}
+
+// Token sends token as a bearer token in the Authorization header.
+func Token(token string) http.ClientOption {
+	return http.ClientBefore(func(ctx context.Context, r *http1.Request) context.Context {
+		r.Header.Set("Authorization", "Bearer "+token)
+		return ctx
+	})
+}
+
+// Tracing propagates the span found in the request context to the server.
+func Tracing(tracer opentracinggo.Tracer, logger log.Logger) http.ClientOption {
+	return http.ClientBefore(opentracing.ContextToHTTP(tracer, logger))
+}
+
+// encodeHTTPGenericRequest is a transport/http.EncodeRequestFunc that
+// JSON-encodes any request to the request body. Primarily useful in a client.
+func encodeHTTPGenericRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	return encodeJSONBody(r, request)
+}
+
+// encodeAddRequest sends the todo itself as the body, which is what the
+// /add route decodes.
+func encodeAddRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	return encodeJSONBody(r, request.(endpoint1.AddRequest).Todo)
+}
+
+// encodeDeleteRequest puts the id in the path of the /delete/{id} route
+// and the policy in its query. An empty policy sends none, which the
+// server takes as refuse: Delete and DeleteCategory then fail with 409
+// Conflict on an item with children instead of deleting it alone.
+func encodeDeleteRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.DeleteRequest)
+	r.URL.Path += "/" + url.PathEscape(req.Id)
+	if req.Policy != "" {
+		r.URL.RawQuery = url.Values{"policy": {req.Policy}}.Encode()
+	}
+	return nil
+}
+
+// encodeUpdateRequest sends the todo itself as the body.
+func encodeUpdateRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	return encodeJSONBody(r, request.(endpoint1.UpdateRequest).Todo)
+}
+
+// encodeGetChildesRequest puts the id in the path of the /get-childes/{id}
+// route; the body repeats it since the route decodes one.
+func encodeGetChildesRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	r.URL.Path += "/" + url.PathEscape(request.(endpoint1.GetChildesRequest).Id)
+	return encodeJSONBody(r, request)
+}
+
+// encodeAddCategoryRequest sends the category itself as the body.
+func encodeAddCategoryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	return encodeJSONBody(r, request.(endpoint1.AddCategoryRequest).Category)
+}
+
+// encodeUpdateCategoryRequest sends the category itself as the body.
+func encodeUpdateCategoryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	return encodeJSONBody(r, request.(endpoint1.UpdateCategoryRequest).Category)
+}
+
+func encodeJSONBody(r *http1.Request, v interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	r.ContentLength = int64(buf.Len())
+	r.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+// setPathVars fills the {name} placeholders of the route in r's path.
+func setPathVars(r *http1.Request, vars map[string]string) {
+	for k, v := range vars {
+		r.URL.Path = strings.Replace(r.URL.Path, "{"+k+"}", v, 1)
+	}
+	r.URL.RawPath = ""
+}
+
+// decodeGetResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeAddResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeAddResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.AddResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeSetCompleteResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeSetCompleteResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.SetCompleteResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeRemoveCompleteResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeRemoveCompleteResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.RemoveCompleteResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeDeleteResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeDeleteResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.DeleteResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeUpdateResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeUpdateResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.UpdateResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeSetStarResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeSetStarResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.SetStarResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeReplyToResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeReplyToResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.ReplyToResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetChildesResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetChildesResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetChildesResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetCategoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCategoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCategoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeAddCategoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeAddCategoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.AddCategoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeUpdateCategoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeUpdateCategoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.UpdateCategoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeDeleteCategoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeDeleteCategoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.DeleteCategoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetCatChildesResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCatChildesResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCatChildesResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeAddWebhookResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeAddWebhookResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.AddWebhookResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetWebhooksResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetWebhooksResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetWebhooksResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeDeleteWebhookRequest fills the path of the /webhooks/{id} route.
+func encodeDeleteWebhookRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.DeleteWebhookRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeDeleteWebhookResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeDeleteWebhookResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.DeleteWebhookResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetWebhookDeliveriesRequest fills the path of the /webhooks/{id}/deliveries route.
+func encodeGetWebhookDeliveriesRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetWebhookDeliveriesRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetWebhookDeliveriesResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetWebhookDeliveriesResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetWebhookDeliveriesResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetDeadLettersResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetDeadLettersResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetDeadLettersResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeRetryDeliveryRequest fills the path of the /webhooks/deliveries/{id}/retry route.
+func encodeRetryDeliveryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.RetryDeliveryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeRetryDeliveryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeRetryDeliveryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.RetryDeliveryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetTreeRequest fills the path and query of the /todos/{id}/tree route.
+func encodeGetTreeRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetTreeRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	q := r.URL.Query()
+	if req.MaxDepth != 0 {
+		q.Set("max_depth", strconv.Itoa(req.MaxDepth))
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodeGetTreeResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetTreeResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetTreeResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeMoveRequest fills the path of the /todos/{id}/move route and sends the request as the body.
+func encodeMoveRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.MoveRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeMoveResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeMoveResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.MoveResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeMoveCategoryRequest fills the path of the /categories/{id}/move route and sends the request as the body.
+func encodeMoveCategoryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.MoveCategoryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeMoveCategoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeMoveCategoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.MoveCategoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeListTrashResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeListTrashResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.ListTrashResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeRestoreRequest fills the path and query of the /trash/{kind}/{id}/restore route.
+func encodeRestoreRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.RestoreRequest)
+	setPathVars(r, map[string]string{
+		"kind": req.Kind,
+		"id":   req.Id,
+	})
+	q := r.URL.Query()
+	if req.Subtree {
+		q.Set("subtree", strconv.FormatBool(req.Subtree))
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodeRestoreResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeRestoreResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.RestoreResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodePurgeRequest fills the path and query of the /trash/{kind}/{id} route.
+func encodePurgeRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.PurgeRequest)
+	setPathVars(r, map[string]string{
+		"kind": req.Kind,
+		"id":   req.Id,
+	})
+	q := r.URL.Query()
+	if req.Subtree {
+		q.Set("subtree", strconv.FormatBool(req.Subtree))
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodePurgeResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodePurgeResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.PurgeResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetCategoryTreeResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCategoryTreeResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCategoryTreeResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetCategoryPathRequest fills the path of the /categories/{id}/path route.
+func encodeGetCategoryPathRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetCategoryPathRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetCategoryPathResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCategoryPathResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCategoryPathResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeReorderRequest fills the path of the /todos/{id}/reorder route and sends the request as the body.
+func encodeReorderRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.ReorderRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeReorderResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeReorderResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.ReorderResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeTransitionRequest fills the path of the /todos/{id}/transition route and sends the request as the body.
+func encodeTransitionRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.TransitionRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeTransitionResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeTransitionResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.TransitionResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetWorkflowRequest fills the path of the /categories/{id}/workflow route.
+func encodeGetWorkflowRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetWorkflowRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetWorkflowResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetWorkflowResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetWorkflowResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeSetWorkflowRequest fills the path of the /categories/{id}/workflow route and sends the request as the body.
+func encodeSetWorkflowRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.SetWorkflowRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeSetWorkflowResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeSetWorkflowResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.SetWorkflowResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetBoardRequest fills the path of the /categories/{id}/board route.
+func encodeGetBoardRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetBoardRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetBoardResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetBoardResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetBoardResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeSetPriorityRequest fills the path of the /todos/{id}/priority route and sends the request as the body.
+func encodeSetPriorityRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.SetPriorityRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeSetPriorityResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeSetPriorityResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.SetPriorityResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetMatrixRequest fills the query of the /todos/matrix route.
+func encodeGetMatrixRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetMatrixRequest)
+	q := r.URL.Query()
+	if req.UrgentHours != 0 {
+		q.Set("urgent_hours", strconv.Itoa(req.UrgentHours))
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodeGetMatrixResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetMatrixResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetMatrixResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeAddDependencyRequest fills the path of the /todos/{id}/dependencies route and sends the request as the body.
+func encodeAddDependencyRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.AddDependencyRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeAddDependencyResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeAddDependencyResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.AddDependencyResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeRemoveDependencyRequest fills the path of the /todos/{id}/dependencies/{blocker_id} route.
+func encodeRemoveDependencyRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.RemoveDependencyRequest)
+	setPathVars(r, map[string]string{
+		"id":         req.Id,
+		"blocker_id": strconv.FormatUint(uint64(req.BlockerId), 10),
+	})
+	return nil
+}
+
+// decodeRemoveDependencyResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeRemoveDependencyResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.RemoveDependencyResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetBlockedResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetBlockedResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetBlockedResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetReadyResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetReadyResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetReadyResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeGetDependencyGraphResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetDependencyGraphResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetDependencyGraphResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetCategoryPlanRequest fills the path of the /categories/{id}/plan route.
+func encodeGetCategoryPlanRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetCategoryPlanRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetCategoryPlanResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCategoryPlanResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCategoryPlanResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetTreePlanRequest fills the path of the /todos/{id}/plan route.
+func encodeGetTreePlanRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetTreePlanRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetTreePlanResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetTreePlanResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetTreePlanResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeStartTimerRequest fills the path of the /todos/{id}/timer route.
+func encodeStartTimerRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.StartTimerRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeStartTimerResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeStartTimerResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.StartTimerResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeStopTimerResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeStopTimerResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.StopTimerResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeAddTimeEntryRequest fills the path of the /todos/{id}/time route and sends the request as the body.
+func encodeAddTimeEntryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.AddTimeEntryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeAddTimeEntryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeAddTimeEntryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.AddTimeEntryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetTimeEntriesRequest fills the path of the /todos/{id}/time route.
+func encodeGetTimeEntriesRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetTimeEntriesRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetTimeEntriesResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetTimeEntriesResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetTimeEntriesResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeUpdateTimeEntryRequest fills the path of the /time/{id} route and sends the request as the body.
+func encodeUpdateTimeEntryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.UpdateTimeEntryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return encodeJSONBody(r, req)
+}
+
+// decodeUpdateTimeEntryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeUpdateTimeEntryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.UpdateTimeEntryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeDeleteTimeEntryRequest fills the path of the /time/{id} route.
+func encodeDeleteTimeEntryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.DeleteTimeEntryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeDeleteTimeEntryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeDeleteTimeEntryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.DeleteTimeEntryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetTimeReportRequest fills the query of the /time/report route.
+func encodeGetTimeReportRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetTimeReportRequest)
+	q := r.URL.Query()
+	if req.From != "" {
+		q.Set("from", req.From)
+	}
+	if req.To != "" {
+		q.Set("to", req.To)
+	}
+	if req.User != "" {
+		q.Set("user", req.User)
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodeGetTimeReportResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetTimeReportResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetTimeReportResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetBurndownRequest fills the path and query of the /categories/{id}/burndown route.
+func encodeGetBurndownRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetBurndownRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	q := r.URL.Query()
+	if req.From != "" {
+		q.Set("from", req.From)
+	}
+	if req.To != "" {
+		q.Set("to", req.To)
+	}
+	if req.Unit != "" {
+		q.Set("unit", req.Unit)
+	}
+	r.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// decodeGetBurndownResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetBurndownResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetBurndownResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetTodoHistoryRequest fills the path of the /todos/{id}/history route.
+func encodeGetTodoHistoryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetTodoHistoryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetTodoHistoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetTodoHistoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetTodoHistoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// encodeGetCategoryHistoryRequest fills the path of the /categories/{id}/history route.
+func encodeGetCategoryHistoryRequest(_ context.Context, r *http1.Request, request interface{}) error {
+	req := request.(endpoint1.GetCategoryHistoryRequest)
+	setPathVars(r, map[string]string{
+		"id": req.Id,
+	})
+	return nil
+}
+
+// decodeGetCategoryHistoryResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeGetCategoryHistoryResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.GetCategoryHistoryResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeUndoResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeUndoResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.UndoResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeRedoResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeRedoResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.RedoResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeFindTodosResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeFindTodosResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.FindTodosResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+// decodeFindCategoriesResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded response from the HTTP response body. If the response
+// has a non-200 status code, we will interpret that as an error and attempt
+// to decode the specific error message from the response body.
+func decodeFindCategoriesResponse(_ context.Context, r *http1.Response) (interface{}, error) {
+	if r.StatusCode != http1.StatusOK {
+		return nil, http2.ErrorDecoder(r)
+	}
+	var resp endpoint1.FindCategoriesResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+func copyURL(base *url.URL, path string) (next *url.URL) {
+	n := *base
+	n.Path = strings.TrimSuffix(base.Path, "/") + path
+	next = &n
+	return
+}
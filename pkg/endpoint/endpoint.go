@@ -112,7 +112,8 @@ func (r RemoveCompleteResponse) Failed() error {
 
 // DeleteRequest collects the request parameters for the Delete method.
 type DeleteRequest struct {
-	Id string `json:"id"`
+	Id     string `json:"id"`
+	Policy string `json:"policy"`
 }
 
 // DeleteResponse collects the response parameters for the Delete method.
@@ -124,7 +125,7 @@ type DeleteResponse struct {
 func MakeDeleteEndpoint(s service.TodoService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(DeleteRequest)
-		error := s.Delete(ctx, req.Id)
+		error := s.Delete(ctx, req.Id, req.Policy)
 		return DeleteResponse{Error: error}, nil
 	}
 }
@@ -146,7 +147,7 @@ func (e Endpoints) Get(ctx context.Context) (t []io.Todo, error error) {
 	request := GetRequest{}
 	response, err := e.GetEndpoint(ctx, request)
 	if err != nil {
-		return
+		return t, err
 	}
 	return response.(GetResponse).T, response.(GetResponse).Error
 }
@@ -156,7 +157,7 @@ func (e Endpoints) Add(ctx context.Context, todo io.Todo) (t io.Todo, error erro
 	request := AddRequest{Todo: todo}
 	response, err := e.AddEndpoint(ctx, request)
 	if err != nil {
-		return
+		return t, err
 	}
 	return response.(AddResponse).T, response.(AddResponse).Error
 }
@@ -166,7 +167,7 @@ func (e Endpoints) SetComplete(ctx context.Context, id string) (error error) {
 	request := SetCompleteRequest{Id: id}
 	response, err := e.SetCompleteEndpoint(ctx, request)
 	if err != nil {
-		return
+		return err
 	}
 	return response.(SetCompleteResponse).Error
 }
@@ -176,17 +177,20 @@ func (e Endpoints) RemoveComplete(ctx context.Context, id string) (error error)
 	request := RemoveCompleteRequest{Id: id}
 	response, err := e.RemoveCompleteEndpoint(ctx, request)
 	if err != nil {
-		return
+		return err
 	}
 	return response.(RemoveCompleteResponse).Error
 }
 
 // Delete implements Service. Primarily useful in a client.
-func (e Endpoints) Delete(ctx context.Context, id string) (error error) {
-	request := DeleteRequest{Id: id}
+func (e Endpoints) Delete(ctx context.Context, id string, policy string) (error error) {
+	request := DeleteRequest{
+		Id:     id,
+		Policy: policy,
+	}
 	response, err := e.DeleteEndpoint(ctx, request)
 	if err != nil {
-		return
+		return err
 	}
 	return response.(DeleteResponse).Error
 }
@@ -224,7 +228,7 @@ func (e Endpoints) Update(ctx context.Context, todo io.Todo) (t io.Todo, error e
 	request := UpdateRequest{Todo: todo}
 	response, err := e.UpdateEndpoint(ctx, request)
 	if err != nil {
-		return
+		return t, err
 	}
 	return response.(UpdateResponse).T, response.(UpdateResponse).Error
 }
@@ -235,11 +239,14 @@ func (r SetStarResponse) Failed() error {
 }
 
 // SetStar implements Service. Primarily useful in a client.
-func (e Endpoints) SetStar(ctx context.Context, star uint8) (error error) {
-	request := SetStarRequest{Star: star}
+func (e Endpoints) SetStar(ctx context.Context, id string, star uint8) (error error) {
+	request := SetStarRequest{
+		Id:   id,
+		Star: star,
+	}
 	response, err := e.SetStarEndpoint(ctx, request)
 	if err != nil {
-		return
+		return err
 	}
 	return response.(SetStarResponse).Error
 }
@@ -301,7 +308,7 @@ func (e Endpoints) ReplyTo(ctx context.Context, parentId uint, todo io.Todo) (t
 	}
 	response, err := e.ReplyToEndpoint(ctx, request)
 	if err != nil {
-		return
+		return t, err
 	}
 	return response.(ReplyToResponse).T, response.(ReplyToResponse).Error
 }
@@ -339,7 +346,7 @@ func (e Endpoints) GetChildes(ctx context.Context, id string) (t []io.Todo, erro
 	request := GetChildesRequest{Id: id}
 	response, err := e.GetChildesEndpoint(ctx, request)
 	if err != nil {
-		return
+		return t, err
 	}
 	return response.(GetChildesResponse).T, response.(GetChildesResponse).Error
 }
@@ -377,7 +384,7 @@ func (e Endpoints) AddCategory(ctx context.Context, category io.TodoCategory) (c
 	request := AddCategoryRequest{Category: category}
 	response, err := e.AddCategoryEndpoint(ctx, request)
 	if err != nil {
-		return
+		return c, err
 	}
 	return response.(AddCategoryResponse).C, response.(AddCategoryResponse).Error
 }
@@ -437,7 +444,8 @@ func (r UpdateCategoryResponse) Failed() error {
 
 // DeleteCategoryRequest collects the request parameters for the DeleteCategory method.
 type DeleteCategoryRequest struct {
-	Id string `json:"id"`
+	Id     string `json:"id"`
+	Policy string `json:"policy"`
 }
 
 // DeleteCategoryResponse collects the response parameters for the DeleteCategory method.
@@ -449,7 +457,7 @@ type DeleteCategoryResponse struct {
 func MakeDeleteCategoryEndpoint(s service.TodoService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(DeleteCategoryRequest)
-		error := s.DeleteCategory(ctx, req.Id)
+		error := s.DeleteCategory(ctx, req.Id, req.Policy)
 		return DeleteCategoryResponse{Error: error}, nil
 	}
 }
@@ -464,7 +472,7 @@ func (e Endpoints) GetCategory(ctx context.Context) (c []io.TodoCategory, error
 	request := GetCategoryRequest{}
 	response, err := e.GetCategoryEndpoint(ctx, request)
 	if err != nil {
-		return
+		return c, err
 	}
 	return response.(GetCategoryResponse).C, response.(GetCategoryResponse).Error
 }
@@ -474,17 +482,20 @@ func (e Endpoints) UpdateCategory(ctx context.Context, category io.TodoCategory)
 	request := UpdateCategoryRequest{Category: category}
 	response, err := e.UpdateCategoryEndpoint(ctx, request)
 	if err != nil {
-		return
+		return c, err
 	}
 	return response.(UpdateCategoryResponse).C, response.(UpdateCategoryResponse).Error
 }
 
 // DeleteCategory implements Service. Primarily useful in a client.
-func (e Endpoints) DeleteCategory(ctx context.Context, id string) (error error) {
-	request := DeleteCategoryRequest{Id: id}
+func (e Endpoints) DeleteCategory(ctx context.Context, id string, policy string) (error error) {
+	request := DeleteCategoryRequest{
+		Id:     id,
+		Policy: policy,
+	}
 	response, err := e.DeleteCategoryEndpoint(ctx, request)
 	if err != nil {
-		return
+		return err
 	}
 	return response.(DeleteCategoryResponse).Error
 }
@@ -522,7 +533,1635 @@ func (e Endpoints) GetCatChildes(ctx context.Context, id string) (c []io.TodoCat
 	request := GetCatChildesRequest{Id: id}
 	response, err := e.GetCatChildesEndpoint(ctx, request)
 	if err != nil {
-		return
+		return c, err
 	}
 	return response.(GetCatChildesResponse).C, response.(GetCatChildesResponse).Error
 }
+
+// AddWebhookRequest collects the request parameters for the AddWebhook method.
+type AddWebhookRequest struct {
+	Webhook io.Webhook `json:"webhook"`
+}
+
+// AddWebhookResponse collects the response parameters for the AddWebhook method.
+type AddWebhookResponse struct {
+	W     io.Webhook `json:"w"`
+	Error error      `json:"error"`
+}
+
+// MakeAddWebhookEndpoint returns an endpoint that invokes AddWebhook on the service.
+func MakeAddWebhookEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AddWebhookRequest)
+		w, error := s.AddWebhook(ctx, req.Webhook)
+		return AddWebhookResponse{
+			W:     w,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r AddWebhookResponse) Failed() error {
+	return r.Error
+}
+
+// AddWebhook implements Service. Primarily useful in a client.
+func (e Endpoints) AddWebhook(ctx context.Context, webhook io.Webhook) (w io.Webhook, error error) {
+	request := AddWebhookRequest{Webhook: webhook}
+	response, err := e.AddWebhookEndpoint(ctx, request)
+	if err != nil {
+		return w, err
+	}
+	return response.(AddWebhookResponse).W, response.(AddWebhookResponse).Error
+}
+
+// GetWebhooksRequest collects the request parameters for the GetWebhooks method.
+type GetWebhooksRequest struct{}
+
+// GetWebhooksResponse collects the response parameters for the GetWebhooks method.
+type GetWebhooksResponse struct {
+	W     []io.Webhook `json:"w"`
+	Error error        `json:"error"`
+}
+
+// MakeGetWebhooksEndpoint returns an endpoint that invokes GetWebhooks on the service.
+func MakeGetWebhooksEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		w, error := s.GetWebhooks(ctx)
+		return GetWebhooksResponse{
+			W:     w,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetWebhooksResponse) Failed() error {
+	return r.Error
+}
+
+// GetWebhooks implements Service. Primarily useful in a client.
+func (e Endpoints) GetWebhooks(ctx context.Context) (w []io.Webhook, error error) {
+	request := GetWebhooksRequest{}
+	response, err := e.GetWebhooksEndpoint(ctx, request)
+	if err != nil {
+		return w, err
+	}
+	return response.(GetWebhooksResponse).W, response.(GetWebhooksResponse).Error
+}
+
+// DeleteWebhookRequest collects the request parameters for the DeleteWebhook method.
+type DeleteWebhookRequest struct {
+	Id string `json:"id"`
+}
+
+// DeleteWebhookResponse collects the response parameters for the DeleteWebhook method.
+type DeleteWebhookResponse struct {
+	Error error `json:"error"`
+}
+
+// MakeDeleteWebhookEndpoint returns an endpoint that invokes DeleteWebhook on the service.
+func MakeDeleteWebhookEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DeleteWebhookRequest)
+		error := s.DeleteWebhook(ctx, req.Id)
+		return DeleteWebhookResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r DeleteWebhookResponse) Failed() error {
+	return r.Error
+}
+
+// DeleteWebhook implements Service. Primarily useful in a client.
+func (e Endpoints) DeleteWebhook(ctx context.Context, id string) (error error) {
+	request := DeleteWebhookRequest{Id: id}
+	response, err := e.DeleteWebhookEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(DeleteWebhookResponse).Error
+}
+
+// GetWebhookDeliveriesRequest collects the request parameters for the GetWebhookDeliveries method.
+type GetWebhookDeliveriesRequest struct {
+	Id string `json:"id"`
+}
+
+// GetWebhookDeliveriesResponse collects the response parameters for the GetWebhookDeliveries method.
+type GetWebhookDeliveriesResponse struct {
+	D     []io.WebhookDelivery `json:"d"`
+	Error error                `json:"error"`
+}
+
+// MakeGetWebhookDeliveriesEndpoint returns an endpoint that invokes GetWebhookDeliveries on the service.
+func MakeGetWebhookDeliveriesEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetWebhookDeliveriesRequest)
+		d, error := s.GetWebhookDeliveries(ctx, req.Id)
+		return GetWebhookDeliveriesResponse{
+			D:     d,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetWebhookDeliveriesResponse) Failed() error {
+	return r.Error
+}
+
+// GetWebhookDeliveries implements Service. Primarily useful in a client.
+func (e Endpoints) GetWebhookDeliveries(ctx context.Context, id string) (d []io.WebhookDelivery, error error) {
+	request := GetWebhookDeliveriesRequest{Id: id}
+	response, err := e.GetWebhookDeliveriesEndpoint(ctx, request)
+	if err != nil {
+		return d, err
+	}
+	return response.(GetWebhookDeliveriesResponse).D, response.(GetWebhookDeliveriesResponse).Error
+}
+
+// GetDeadLettersRequest collects the request parameters for the GetDeadLetters method.
+type GetDeadLettersRequest struct{}
+
+// GetDeadLettersResponse collects the response parameters for the GetDeadLetters method.
+type GetDeadLettersResponse struct {
+	D     []io.WebhookDelivery `json:"d"`
+	Error error                `json:"error"`
+}
+
+// MakeGetDeadLettersEndpoint returns an endpoint that invokes GetDeadLetters on the service.
+func MakeGetDeadLettersEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		d, error := s.GetDeadLetters(ctx)
+		return GetDeadLettersResponse{
+			D:     d,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetDeadLettersResponse) Failed() error {
+	return r.Error
+}
+
+// GetDeadLetters implements Service. Primarily useful in a client.
+func (e Endpoints) GetDeadLetters(ctx context.Context) (d []io.WebhookDelivery, error error) {
+	request := GetDeadLettersRequest{}
+	response, err := e.GetDeadLettersEndpoint(ctx, request)
+	if err != nil {
+		return d, err
+	}
+	return response.(GetDeadLettersResponse).D, response.(GetDeadLettersResponse).Error
+}
+
+// RetryDeliveryRequest collects the request parameters for the RetryDelivery method.
+type RetryDeliveryRequest struct {
+	Id string `json:"id"`
+}
+
+// RetryDeliveryResponse collects the response parameters for the RetryDelivery method.
+type RetryDeliveryResponse struct {
+	D     io.WebhookDelivery `json:"d"`
+	Error error              `json:"error"`
+}
+
+// MakeRetryDeliveryEndpoint returns an endpoint that invokes RetryDelivery on the service.
+func MakeRetryDeliveryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RetryDeliveryRequest)
+		d, error := s.RetryDelivery(ctx, req.Id)
+		return RetryDeliveryResponse{
+			D:     d,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r RetryDeliveryResponse) Failed() error {
+	return r.Error
+}
+
+// RetryDelivery implements Service. Primarily useful in a client.
+func (e Endpoints) RetryDelivery(ctx context.Context, id string) (d io.WebhookDelivery, error error) {
+	request := RetryDeliveryRequest{Id: id}
+	response, err := e.RetryDeliveryEndpoint(ctx, request)
+	if err != nil {
+		return d, err
+	}
+	return response.(RetryDeliveryResponse).D, response.(RetryDeliveryResponse).Error
+}
+
+// GetTreeRequest collects the request parameters for the GetTree method.
+type GetTreeRequest struct {
+	Id       string `json:"id"`
+	MaxDepth int    `json:"max_depth"`
+}
+
+// GetTreeResponse collects the response parameters for the GetTree method.
+type GetTreeResponse struct {
+	T     io.TodoNode `json:"t"`
+	Error error       `json:"error"`
+}
+
+// MakeGetTreeEndpoint returns an endpoint that invokes GetTree on the service.
+func MakeGetTreeEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetTreeRequest)
+		t, error := s.GetTree(ctx, req.Id, req.MaxDepth)
+		return GetTreeResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetTreeResponse) Failed() error {
+	return r.Error
+}
+
+// GetTree implements Service. Primarily useful in a client.
+func (e Endpoints) GetTree(ctx context.Context, id string, maxDepth int) (t io.TodoNode, error error) {
+	request := GetTreeRequest{
+		Id:       id,
+		MaxDepth: maxDepth,
+	}
+	response, err := e.GetTreeEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(GetTreeResponse).T, response.(GetTreeResponse).Error
+}
+
+// MoveRequest collects the request parameters for the Move method.
+type MoveRequest struct {
+	Id       string `json:"id"`
+	ParentId uint   `json:"parent_id"`
+}
+
+// MoveResponse collects the response parameters for the Move method.
+type MoveResponse struct {
+	T     io.Todo `json:"t"`
+	Error error   `json:"error"`
+}
+
+// MakeMoveEndpoint returns an endpoint that invokes Move on the service.
+func MakeMoveEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(MoveRequest)
+		t, error := s.Move(ctx, req.Id, req.ParentId)
+		return MoveResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r MoveResponse) Failed() error {
+	return r.Error
+}
+
+// Move implements Service. Primarily useful in a client.
+func (e Endpoints) Move(ctx context.Context, id string, parentId uint) (t io.Todo, error error) {
+	request := MoveRequest{
+		Id:       id,
+		ParentId: parentId,
+	}
+	response, err := e.MoveEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(MoveResponse).T, response.(MoveResponse).Error
+}
+
+// MoveCategoryRequest collects the request parameters for the MoveCategory method.
+type MoveCategoryRequest struct {
+	Id       string `json:"id"`
+	ParentId uint   `json:"parent_id"`
+}
+
+// MoveCategoryResponse collects the response parameters for the MoveCategory method.
+type MoveCategoryResponse struct {
+	C     io.TodoCategory `json:"c"`
+	Error error           `json:"error"`
+}
+
+// MakeMoveCategoryEndpoint returns an endpoint that invokes MoveCategory on the service.
+func MakeMoveCategoryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(MoveCategoryRequest)
+		c, error := s.MoveCategory(ctx, req.Id, req.ParentId)
+		return MoveCategoryResponse{
+			C:     c,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r MoveCategoryResponse) Failed() error {
+	return r.Error
+}
+
+// MoveCategory implements Service. Primarily useful in a client.
+func (e Endpoints) MoveCategory(ctx context.Context, id string, parentId uint) (c io.TodoCategory, error error) {
+	request := MoveCategoryRequest{
+		Id:       id,
+		ParentId: parentId,
+	}
+	response, err := e.MoveCategoryEndpoint(ctx, request)
+	if err != nil {
+		return c, err
+	}
+	return response.(MoveCategoryResponse).C, response.(MoveCategoryResponse).Error
+}
+
+// ListTrashRequest collects the request parameters for the ListTrash method.
+type ListTrashRequest struct{}
+
+// ListTrashResponse collects the response parameters for the ListTrash method.
+type ListTrashResponse struct {
+	T     io.Trash `json:"t"`
+	Error error    `json:"error"`
+}
+
+// MakeListTrashEndpoint returns an endpoint that invokes ListTrash on the service.
+func MakeListTrashEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		t, error := s.ListTrash(ctx)
+		return ListTrashResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r ListTrashResponse) Failed() error {
+	return r.Error
+}
+
+// ListTrash implements Service. Primarily useful in a client.
+func (e Endpoints) ListTrash(ctx context.Context) (t io.Trash, error error) {
+	request := ListTrashRequest{}
+	response, err := e.ListTrashEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(ListTrashResponse).T, response.(ListTrashResponse).Error
+}
+
+// RestoreRequest collects the request parameters for the Restore method.
+type RestoreRequest struct {
+	Kind    string `json:"kind"`
+	Id      string `json:"id"`
+	Subtree bool   `json:"subtree"`
+}
+
+// RestoreResponse collects the response parameters for the Restore method.
+type RestoreResponse struct {
+	Error error `json:"error"`
+}
+
+// MakeRestoreEndpoint returns an endpoint that invokes Restore on the service.
+func MakeRestoreEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RestoreRequest)
+		error := s.Restore(ctx, req.Kind, req.Id, req.Subtree)
+		return RestoreResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r RestoreResponse) Failed() error {
+	return r.Error
+}
+
+// Restore implements Service. Primarily useful in a client.
+func (e Endpoints) Restore(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	request := RestoreRequest{
+		Kind:    kind,
+		Id:      id,
+		Subtree: subtree,
+	}
+	response, err := e.RestoreEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(RestoreResponse).Error
+}
+
+// PurgeRequest collects the request parameters for the Purge method.
+type PurgeRequest struct {
+	Kind    string `json:"kind"`
+	Id      string `json:"id"`
+	Subtree bool   `json:"subtree"`
+}
+
+// PurgeResponse collects the response parameters for the Purge method.
+type PurgeResponse struct {
+	Error error `json:"error"`
+}
+
+// MakePurgeEndpoint returns an endpoint that invokes Purge on the service.
+func MakePurgeEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(PurgeRequest)
+		error := s.Purge(ctx, req.Kind, req.Id, req.Subtree)
+		return PurgeResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r PurgeResponse) Failed() error {
+	return r.Error
+}
+
+// Purge implements Service. Primarily useful in a client.
+func (e Endpoints) Purge(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	request := PurgeRequest{
+		Kind:    kind,
+		Id:      id,
+		Subtree: subtree,
+	}
+	response, err := e.PurgeEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(PurgeResponse).Error
+}
+
+// GetCategoryTreeRequest collects the request parameters for the GetCategoryTree method.
+type GetCategoryTreeRequest struct{}
+
+// GetCategoryTreeResponse collects the response parameters for the GetCategoryTree method.
+type GetCategoryTreeResponse struct {
+	C     []io.CategoryNode `json:"c"`
+	Error error             `json:"error"`
+}
+
+// MakeGetCategoryTreeEndpoint returns an endpoint that invokes GetCategoryTree on the service.
+func MakeGetCategoryTreeEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		c, error := s.GetCategoryTree(ctx)
+		return GetCategoryTreeResponse{
+			C:     c,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetCategoryTreeResponse) Failed() error {
+	return r.Error
+}
+
+// GetCategoryTree implements Service. Primarily useful in a client.
+func (e Endpoints) GetCategoryTree(ctx context.Context) (c []io.CategoryNode, error error) {
+	request := GetCategoryTreeRequest{}
+	response, err := e.GetCategoryTreeEndpoint(ctx, request)
+	if err != nil {
+		return c, err
+	}
+	return response.(GetCategoryTreeResponse).C, response.(GetCategoryTreeResponse).Error
+}
+
+// GetCategoryPathRequest collects the request parameters for the GetCategoryPath method.
+type GetCategoryPathRequest struct {
+	Id string `json:"id"`
+}
+
+// GetCategoryPathResponse collects the response parameters for the GetCategoryPath method.
+type GetCategoryPathResponse struct {
+	C     []io.TodoCategory `json:"c"`
+	Error error             `json:"error"`
+}
+
+// MakeGetCategoryPathEndpoint returns an endpoint that invokes GetCategoryPath on the service.
+func MakeGetCategoryPathEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetCategoryPathRequest)
+		c, error := s.GetCategoryPath(ctx, req.Id)
+		return GetCategoryPathResponse{
+			C:     c,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetCategoryPathResponse) Failed() error {
+	return r.Error
+}
+
+// GetCategoryPath implements Service. Primarily useful in a client.
+func (e Endpoints) GetCategoryPath(ctx context.Context, id string) (c []io.TodoCategory, error error) {
+	request := GetCategoryPathRequest{Id: id}
+	response, err := e.GetCategoryPathEndpoint(ctx, request)
+	if err != nil {
+		return c, err
+	}
+	return response.(GetCategoryPathResponse).C, response.(GetCategoryPathResponse).Error
+}
+
+// ReorderRequest collects the request parameters for the Reorder method.
+type ReorderRequest struct {
+	Id     string `json:"id"`
+	Before uint   `json:"before"`
+	After  uint   `json:"after"`
+}
+
+// ReorderResponse collects the response parameters for the Reorder method.
+type ReorderResponse struct {
+	T     io.Todo `json:"t"`
+	Error error   `json:"error"`
+}
+
+// MakeReorderEndpoint returns an endpoint that invokes Reorder on the service.
+func MakeReorderEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ReorderRequest)
+		t, error := s.Reorder(ctx, req.Id, req.Before, req.After)
+		return ReorderResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r ReorderResponse) Failed() error {
+	return r.Error
+}
+
+// Reorder implements Service. Primarily useful in a client.
+func (e Endpoints) Reorder(ctx context.Context, id string, before uint, after uint) (t io.Todo, error error) {
+	request := ReorderRequest{
+		Id:     id,
+		Before: before,
+		After:  after,
+	}
+	response, err := e.ReorderEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(ReorderResponse).T, response.(ReorderResponse).Error
+}
+
+// TransitionRequest collects the request parameters for the Transition method.
+type TransitionRequest struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// TransitionResponse collects the response parameters for the Transition method.
+type TransitionResponse struct {
+	T     io.Todo `json:"t"`
+	Error error   `json:"error"`
+}
+
+// MakeTransitionEndpoint returns an endpoint that invokes Transition on the service.
+func MakeTransitionEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(TransitionRequest)
+		t, error := s.Transition(ctx, req.Id, req.Status)
+		return TransitionResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r TransitionResponse) Failed() error {
+	return r.Error
+}
+
+// Transition implements Service. Primarily useful in a client.
+func (e Endpoints) Transition(ctx context.Context, id string, status string) (t io.Todo, error error) {
+	request := TransitionRequest{
+		Id:     id,
+		Status: status,
+	}
+	response, err := e.TransitionEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(TransitionResponse).T, response.(TransitionResponse).Error
+}
+
+// GetWorkflowRequest collects the request parameters for the GetWorkflow method.
+type GetWorkflowRequest struct {
+	Id string `json:"id"`
+}
+
+// GetWorkflowResponse collects the response parameters for the GetWorkflow method.
+type GetWorkflowResponse struct {
+	W     io.Workflow `json:"w"`
+	Error error       `json:"error"`
+}
+
+// MakeGetWorkflowEndpoint returns an endpoint that invokes GetWorkflow on the service.
+func MakeGetWorkflowEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetWorkflowRequest)
+		w, error := s.GetWorkflow(ctx, req.Id)
+		return GetWorkflowResponse{
+			W:     w,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetWorkflowResponse) Failed() error {
+	return r.Error
+}
+
+// GetWorkflow implements Service. Primarily useful in a client.
+func (e Endpoints) GetWorkflow(ctx context.Context, id string) (w io.Workflow, error error) {
+	request := GetWorkflowRequest{Id: id}
+	response, err := e.GetWorkflowEndpoint(ctx, request)
+	if err != nil {
+		return w, err
+	}
+	return response.(GetWorkflowResponse).W, response.(GetWorkflowResponse).Error
+}
+
+// SetWorkflowRequest collects the request parameters for the SetWorkflow method.
+type SetWorkflowRequest struct {
+	Id       string      `json:"id"`
+	Workflow io.Workflow `json:"workflow"`
+}
+
+// SetWorkflowResponse collects the response parameters for the SetWorkflow method.
+type SetWorkflowResponse struct {
+	W     io.Workflow `json:"w"`
+	Error error       `json:"error"`
+}
+
+// MakeSetWorkflowEndpoint returns an endpoint that invokes SetWorkflow on the service.
+func MakeSetWorkflowEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetWorkflowRequest)
+		w, error := s.SetWorkflow(ctx, req.Id, req.Workflow)
+		return SetWorkflowResponse{
+			W:     w,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r SetWorkflowResponse) Failed() error {
+	return r.Error
+}
+
+// SetWorkflow implements Service. Primarily useful in a client.
+func (e Endpoints) SetWorkflow(ctx context.Context, id string, workflow io.Workflow) (w io.Workflow, error error) {
+	request := SetWorkflowRequest{
+		Id:       id,
+		Workflow: workflow,
+	}
+	response, err := e.SetWorkflowEndpoint(ctx, request)
+	if err != nil {
+		return w, err
+	}
+	return response.(SetWorkflowResponse).W, response.(SetWorkflowResponse).Error
+}
+
+// GetBoardRequest collects the request parameters for the GetBoard method.
+type GetBoardRequest struct {
+	Id string `json:"id"`
+}
+
+// GetBoardResponse collects the response parameters for the GetBoard method.
+type GetBoardResponse struct {
+	B     io.Board `json:"b"`
+	Error error    `json:"error"`
+}
+
+// MakeGetBoardEndpoint returns an endpoint that invokes GetBoard on the service.
+func MakeGetBoardEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetBoardRequest)
+		b, error := s.GetBoard(ctx, req.Id)
+		return GetBoardResponse{
+			B:     b,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetBoardResponse) Failed() error {
+	return r.Error
+}
+
+// GetBoard implements Service. Primarily useful in a client.
+func (e Endpoints) GetBoard(ctx context.Context, id string) (b io.Board, error error) {
+	request := GetBoardRequest{Id: id}
+	response, err := e.GetBoardEndpoint(ctx, request)
+	if err != nil {
+		return b, err
+	}
+	return response.(GetBoardResponse).B, response.(GetBoardResponse).Error
+}
+
+// SetPriorityRequest collects the request parameters for the SetPriority method.
+type SetPriorityRequest struct {
+	Id       string `json:"id"`
+	Priority uint8  `json:"priority"`
+}
+
+// SetPriorityResponse collects the response parameters for the SetPriority method.
+type SetPriorityResponse struct {
+	Error error `json:"error"`
+}
+
+// MakeSetPriorityEndpoint returns an endpoint that invokes SetPriority on the service.
+func MakeSetPriorityEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetPriorityRequest)
+		error := s.SetPriority(ctx, req.Id, req.Priority)
+		return SetPriorityResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r SetPriorityResponse) Failed() error {
+	return r.Error
+}
+
+// SetPriority implements Service. Primarily useful in a client.
+func (e Endpoints) SetPriority(ctx context.Context, id string, priority uint8) (error error) {
+	request := SetPriorityRequest{
+		Id:       id,
+		Priority: priority,
+	}
+	response, err := e.SetPriorityEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(SetPriorityResponse).Error
+}
+
+// GetMatrixRequest collects the request parameters for the GetMatrix method.
+type GetMatrixRequest struct {
+	UrgentHours int `json:"urgent_hours"`
+}
+
+// GetMatrixResponse collects the response parameters for the GetMatrix method.
+type GetMatrixResponse struct {
+	M     io.Matrix `json:"m"`
+	Error error     `json:"error"`
+}
+
+// MakeGetMatrixEndpoint returns an endpoint that invokes GetMatrix on the service.
+func MakeGetMatrixEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetMatrixRequest)
+		m, error := s.GetMatrix(ctx, req.UrgentHours)
+		return GetMatrixResponse{
+			M:     m,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetMatrixResponse) Failed() error {
+	return r.Error
+}
+
+// GetMatrix implements Service. Primarily useful in a client.
+func (e Endpoints) GetMatrix(ctx context.Context, urgentHours int) (m io.Matrix, error error) {
+	request := GetMatrixRequest{UrgentHours: urgentHours}
+	response, err := e.GetMatrixEndpoint(ctx, request)
+	if err != nil {
+		return m, err
+	}
+	return response.(GetMatrixResponse).M, response.(GetMatrixResponse).Error
+}
+
+// AddDependencyRequest collects the request parameters for the AddDependency method.
+type AddDependencyRequest struct {
+	Id        string `json:"id"`
+	BlockerId uint   `json:"blocker_id"`
+}
+
+// AddDependencyResponse collects the response parameters for the AddDependency method.
+type AddDependencyResponse struct {
+	D     io.Dependency `json:"d"`
+	Error error         `json:"error"`
+}
+
+// MakeAddDependencyEndpoint returns an endpoint that invokes AddDependency on the service.
+func MakeAddDependencyEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AddDependencyRequest)
+		d, error := s.AddDependency(ctx, req.Id, req.BlockerId)
+		return AddDependencyResponse{
+			D:     d,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r AddDependencyResponse) Failed() error {
+	return r.Error
+}
+
+// AddDependency implements Service. Primarily useful in a client.
+func (e Endpoints) AddDependency(ctx context.Context, id string, blockerId uint) (d io.Dependency, error error) {
+	request := AddDependencyRequest{
+		Id:        id,
+		BlockerId: blockerId,
+	}
+	response, err := e.AddDependencyEndpoint(ctx, request)
+	if err != nil {
+		return d, err
+	}
+	return response.(AddDependencyResponse).D, response.(AddDependencyResponse).Error
+}
+
+// RemoveDependencyRequest collects the request parameters for the RemoveDependency method.
+type RemoveDependencyRequest struct {
+	Id        string `json:"id"`
+	BlockerId uint   `json:"blocker_id"`
+}
+
+// RemoveDependencyResponse collects the response parameters for the RemoveDependency method.
+type RemoveDependencyResponse struct {
+	Error error `json:"error"`
+}
+
+// MakeRemoveDependencyEndpoint returns an endpoint that invokes RemoveDependency on the service.
+func MakeRemoveDependencyEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RemoveDependencyRequest)
+		error := s.RemoveDependency(ctx, req.Id, req.BlockerId)
+		return RemoveDependencyResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r RemoveDependencyResponse) Failed() error {
+	return r.Error
+}
+
+// RemoveDependency implements Service. Primarily useful in a client.
+func (e Endpoints) RemoveDependency(ctx context.Context, id string, blockerId uint) (error error) {
+	request := RemoveDependencyRequest{
+		Id:        id,
+		BlockerId: blockerId,
+	}
+	response, err := e.RemoveDependencyEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(RemoveDependencyResponse).Error
+}
+
+// GetBlockedRequest collects the request parameters for the GetBlocked method.
+type GetBlockedRequest struct{}
+
+// GetBlockedResponse collects the response parameters for the GetBlocked method.
+type GetBlockedResponse struct {
+	T     []io.Todo `json:"t"`
+	Error error     `json:"error"`
+}
+
+// MakeGetBlockedEndpoint returns an endpoint that invokes GetBlocked on the service.
+func MakeGetBlockedEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		t, error := s.GetBlocked(ctx)
+		return GetBlockedResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetBlockedResponse) Failed() error {
+	return r.Error
+}
+
+// GetBlocked implements Service. Primarily useful in a client.
+func (e Endpoints) GetBlocked(ctx context.Context) (t []io.Todo, error error) {
+	request := GetBlockedRequest{}
+	response, err := e.GetBlockedEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(GetBlockedResponse).T, response.(GetBlockedResponse).Error
+}
+
+// GetReadyRequest collects the request parameters for the GetReady method.
+type GetReadyRequest struct{}
+
+// GetReadyResponse collects the response parameters for the GetReady method.
+type GetReadyResponse struct {
+	T     []io.Todo `json:"t"`
+	Error error     `json:"error"`
+}
+
+// MakeGetReadyEndpoint returns an endpoint that invokes GetReady on the service.
+func MakeGetReadyEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		t, error := s.GetReady(ctx)
+		return GetReadyResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetReadyResponse) Failed() error {
+	return r.Error
+}
+
+// GetReady implements Service. Primarily useful in a client.
+func (e Endpoints) GetReady(ctx context.Context) (t []io.Todo, error error) {
+	request := GetReadyRequest{}
+	response, err := e.GetReadyEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(GetReadyResponse).T, response.(GetReadyResponse).Error
+}
+
+// GetDependencyGraphRequest collects the request parameters for the GetDependencyGraph method.
+type GetDependencyGraphRequest struct{}
+
+// GetDependencyGraphResponse collects the response parameters for the GetDependencyGraph method.
+type GetDependencyGraphResponse struct {
+	G     io.DependencyGraph `json:"g"`
+	Error error              `json:"error"`
+}
+
+// MakeGetDependencyGraphEndpoint returns an endpoint that invokes GetDependencyGraph on the service.
+func MakeGetDependencyGraphEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		g, error := s.GetDependencyGraph(ctx)
+		return GetDependencyGraphResponse{
+			G:     g,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetDependencyGraphResponse) Failed() error {
+	return r.Error
+}
+
+// GetDependencyGraph implements Service. Primarily useful in a client.
+func (e Endpoints) GetDependencyGraph(ctx context.Context) (g io.DependencyGraph, error error) {
+	request := GetDependencyGraphRequest{}
+	response, err := e.GetDependencyGraphEndpoint(ctx, request)
+	if err != nil {
+		return g, err
+	}
+	return response.(GetDependencyGraphResponse).G, response.(GetDependencyGraphResponse).Error
+}
+
+// GetCategoryPlanRequest collects the request parameters for the GetCategoryPlan method.
+type GetCategoryPlanRequest struct {
+	Id string `json:"id"`
+}
+
+// GetCategoryPlanResponse collects the response parameters for the GetCategoryPlan method.
+type GetCategoryPlanResponse struct {
+	P     io.Plan `json:"p"`
+	Error error   `json:"error"`
+}
+
+// MakeGetCategoryPlanEndpoint returns an endpoint that invokes GetCategoryPlan on the service.
+func MakeGetCategoryPlanEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetCategoryPlanRequest)
+		p, error := s.GetCategoryPlan(ctx, req.Id)
+		return GetCategoryPlanResponse{
+			P:     p,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetCategoryPlanResponse) Failed() error {
+	return r.Error
+}
+
+// GetCategoryPlan implements Service. Primarily useful in a client.
+func (e Endpoints) GetCategoryPlan(ctx context.Context, id string) (p io.Plan, error error) {
+	request := GetCategoryPlanRequest{Id: id}
+	response, err := e.GetCategoryPlanEndpoint(ctx, request)
+	if err != nil {
+		return p, err
+	}
+	return response.(GetCategoryPlanResponse).P, response.(GetCategoryPlanResponse).Error
+}
+
+// GetTreePlanRequest collects the request parameters for the GetTreePlan method.
+type GetTreePlanRequest struct {
+	Id string `json:"id"`
+}
+
+// GetTreePlanResponse collects the response parameters for the GetTreePlan method.
+type GetTreePlanResponse struct {
+	P     io.Plan `json:"p"`
+	Error error   `json:"error"`
+}
+
+// MakeGetTreePlanEndpoint returns an endpoint that invokes GetTreePlan on the service.
+func MakeGetTreePlanEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetTreePlanRequest)
+		p, error := s.GetTreePlan(ctx, req.Id)
+		return GetTreePlanResponse{
+			P:     p,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetTreePlanResponse) Failed() error {
+	return r.Error
+}
+
+// GetTreePlan implements Service. Primarily useful in a client.
+func (e Endpoints) GetTreePlan(ctx context.Context, id string) (p io.Plan, error error) {
+	request := GetTreePlanRequest{Id: id}
+	response, err := e.GetTreePlanEndpoint(ctx, request)
+	if err != nil {
+		return p, err
+	}
+	return response.(GetTreePlanResponse).P, response.(GetTreePlanResponse).Error
+}
+
+// StartTimerRequest collects the request parameters for the StartTimer method.
+type StartTimerRequest struct {
+	Id string `json:"id"`
+}
+
+// StartTimerResponse collects the response parameters for the StartTimer method.
+type StartTimerResponse struct {
+	Te    io.TimeEntry `json:"te"`
+	Error error        `json:"error"`
+}
+
+// MakeStartTimerEndpoint returns an endpoint that invokes StartTimer on the service.
+func MakeStartTimerEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(StartTimerRequest)
+		te, error := s.StartTimer(ctx, req.Id)
+		return StartTimerResponse{
+			Te:    te,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r StartTimerResponse) Failed() error {
+	return r.Error
+}
+
+// StartTimer implements Service. Primarily useful in a client.
+func (e Endpoints) StartTimer(ctx context.Context, id string) (te io.TimeEntry, error error) {
+	request := StartTimerRequest{Id: id}
+	response, err := e.StartTimerEndpoint(ctx, request)
+	if err != nil {
+		return te, err
+	}
+	return response.(StartTimerResponse).Te, response.(StartTimerResponse).Error
+}
+
+// StopTimerRequest collects the request parameters for the StopTimer method.
+type StopTimerRequest struct{}
+
+// StopTimerResponse collects the response parameters for the StopTimer method.
+type StopTimerResponse struct {
+	Te    io.TimeEntry `json:"te"`
+	Error error        `json:"error"`
+}
+
+// MakeStopTimerEndpoint returns an endpoint that invokes StopTimer on the service.
+func MakeStopTimerEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		te, error := s.StopTimer(ctx)
+		return StopTimerResponse{
+			Te:    te,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r StopTimerResponse) Failed() error {
+	return r.Error
+}
+
+// StopTimer implements Service. Primarily useful in a client.
+func (e Endpoints) StopTimer(ctx context.Context) (te io.TimeEntry, error error) {
+	request := StopTimerRequest{}
+	response, err := e.StopTimerEndpoint(ctx, request)
+	if err != nil {
+		return te, err
+	}
+	return response.(StopTimerResponse).Te, response.(StopTimerResponse).Error
+}
+
+// AddTimeEntryRequest collects the request parameters for the AddTimeEntry method.
+type AddTimeEntryRequest struct {
+	Id    string       `json:"id"`
+	Entry io.TimeEntry `json:"entry"`
+}
+
+// AddTimeEntryResponse collects the response parameters for the AddTimeEntry method.
+type AddTimeEntryResponse struct {
+	Te    io.TimeEntry `json:"te"`
+	Error error        `json:"error"`
+}
+
+// MakeAddTimeEntryEndpoint returns an endpoint that invokes AddTimeEntry on the service.
+func MakeAddTimeEntryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AddTimeEntryRequest)
+		te, error := s.AddTimeEntry(ctx, req.Id, req.Entry)
+		return AddTimeEntryResponse{
+			Te:    te,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r AddTimeEntryResponse) Failed() error {
+	return r.Error
+}
+
+// AddTimeEntry implements Service. Primarily useful in a client.
+func (e Endpoints) AddTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	request := AddTimeEntryRequest{
+		Id:    id,
+		Entry: entry,
+	}
+	response, err := e.AddTimeEntryEndpoint(ctx, request)
+	if err != nil {
+		return te, err
+	}
+	return response.(AddTimeEntryResponse).Te, response.(AddTimeEntryResponse).Error
+}
+
+// GetTimeEntriesRequest collects the request parameters for the GetTimeEntries method.
+type GetTimeEntriesRequest struct {
+	Id string `json:"id"`
+}
+
+// GetTimeEntriesResponse collects the response parameters for the GetTimeEntries method.
+type GetTimeEntriesResponse struct {
+	Te    []io.TimeEntry `json:"te"`
+	Error error          `json:"error"`
+}
+
+// MakeGetTimeEntriesEndpoint returns an endpoint that invokes GetTimeEntries on the service.
+func MakeGetTimeEntriesEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetTimeEntriesRequest)
+		te, error := s.GetTimeEntries(ctx, req.Id)
+		return GetTimeEntriesResponse{
+			Te:    te,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetTimeEntriesResponse) Failed() error {
+	return r.Error
+}
+
+// GetTimeEntries implements Service. Primarily useful in a client.
+func (e Endpoints) GetTimeEntries(ctx context.Context, id string) (te []io.TimeEntry, error error) {
+	request := GetTimeEntriesRequest{Id: id}
+	response, err := e.GetTimeEntriesEndpoint(ctx, request)
+	if err != nil {
+		return te, err
+	}
+	return response.(GetTimeEntriesResponse).Te, response.(GetTimeEntriesResponse).Error
+}
+
+// UpdateTimeEntryRequest collects the request parameters for the UpdateTimeEntry method.
+type UpdateTimeEntryRequest struct {
+	Id    string       `json:"id"`
+	Entry io.TimeEntry `json:"entry"`
+}
+
+// UpdateTimeEntryResponse collects the response parameters for the UpdateTimeEntry method.
+type UpdateTimeEntryResponse struct {
+	Te    io.TimeEntry `json:"te"`
+	Error error        `json:"error"`
+}
+
+// MakeUpdateTimeEntryEndpoint returns an endpoint that invokes UpdateTimeEntry on the service.
+func MakeUpdateTimeEntryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(UpdateTimeEntryRequest)
+		te, error := s.UpdateTimeEntry(ctx, req.Id, req.Entry)
+		return UpdateTimeEntryResponse{
+			Te:    te,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r UpdateTimeEntryResponse) Failed() error {
+	return r.Error
+}
+
+// UpdateTimeEntry implements Service. Primarily useful in a client.
+func (e Endpoints) UpdateTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	request := UpdateTimeEntryRequest{
+		Id:    id,
+		Entry: entry,
+	}
+	response, err := e.UpdateTimeEntryEndpoint(ctx, request)
+	if err != nil {
+		return te, err
+	}
+	return response.(UpdateTimeEntryResponse).Te, response.(UpdateTimeEntryResponse).Error
+}
+
+// DeleteTimeEntryRequest collects the request parameters for the DeleteTimeEntry method.
+type DeleteTimeEntryRequest struct {
+	Id string `json:"id"`
+}
+
+// DeleteTimeEntryResponse collects the response parameters for the DeleteTimeEntry method.
+type DeleteTimeEntryResponse struct {
+	Error error `json:"error"`
+}
+
+// MakeDeleteTimeEntryEndpoint returns an endpoint that invokes DeleteTimeEntry on the service.
+func MakeDeleteTimeEntryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(DeleteTimeEntryRequest)
+		error := s.DeleteTimeEntry(ctx, req.Id)
+		return DeleteTimeEntryResponse{Error: error}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r DeleteTimeEntryResponse) Failed() error {
+	return r.Error
+}
+
+// DeleteTimeEntry implements Service. Primarily useful in a client.
+func (e Endpoints) DeleteTimeEntry(ctx context.Context, id string) (error error) {
+	request := DeleteTimeEntryRequest{Id: id}
+	response, err := e.DeleteTimeEntryEndpoint(ctx, request)
+	if err != nil {
+		return err
+	}
+	return response.(DeleteTimeEntryResponse).Error
+}
+
+// GetTimeReportRequest collects the request parameters for the GetTimeReport method.
+type GetTimeReportRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	User string `json:"user"`
+}
+
+// GetTimeReportResponse collects the response parameters for the GetTimeReport method.
+type GetTimeReportResponse struct {
+	R     io.TimeReport `json:"r"`
+	Error error         `json:"error"`
+}
+
+// MakeGetTimeReportEndpoint returns an endpoint that invokes GetTimeReport on the service.
+func MakeGetTimeReportEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetTimeReportRequest)
+		r, error := s.GetTimeReport(ctx, req.From, req.To, req.User)
+		return GetTimeReportResponse{
+			R:     r,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetTimeReportResponse) Failed() error {
+	return r.Error
+}
+
+// GetTimeReport implements Service. Primarily useful in a client.
+func (e Endpoints) GetTimeReport(ctx context.Context, from string, to string, user string) (r io.TimeReport, error error) {
+	request := GetTimeReportRequest{
+		From: from,
+		To:   to,
+		User: user,
+	}
+	response, err := e.GetTimeReportEndpoint(ctx, request)
+	if err != nil {
+		return r, err
+	}
+	return response.(GetTimeReportResponse).R, response.(GetTimeReportResponse).Error
+}
+
+// GetBurndownRequest collects the request parameters for the GetBurndown method.
+type GetBurndownRequest struct {
+	Id   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	Unit string `json:"unit"`
+}
+
+// GetBurndownResponse collects the response parameters for the GetBurndown method.
+type GetBurndownResponse struct {
+	Bd    io.Burndown `json:"bd"`
+	Error error       `json:"error"`
+}
+
+// MakeGetBurndownEndpoint returns an endpoint that invokes GetBurndown on the service.
+func MakeGetBurndownEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetBurndownRequest)
+		bd, error := s.GetBurndown(ctx, req.Id, req.From, req.To, req.Unit)
+		return GetBurndownResponse{
+			Bd:    bd,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetBurndownResponse) Failed() error {
+	return r.Error
+}
+
+// GetBurndown implements Service. Primarily useful in a client.
+func (e Endpoints) GetBurndown(ctx context.Context, id string, from string, to string, unit string) (bd io.Burndown, error error) {
+	request := GetBurndownRequest{
+		Id:   id,
+		From: from,
+		To:   to,
+		Unit: unit,
+	}
+	response, err := e.GetBurndownEndpoint(ctx, request)
+	if err != nil {
+		return bd, err
+	}
+	return response.(GetBurndownResponse).Bd, response.(GetBurndownResponse).Error
+}
+
+// GetTodoHistoryRequest collects the request parameters for the GetTodoHistory method.
+type GetTodoHistoryRequest struct {
+	Id string `json:"id"`
+}
+
+// GetTodoHistoryResponse collects the response parameters for the GetTodoHistory method.
+type GetTodoHistoryResponse struct {
+	H     []io.Change `json:"h"`
+	Error error       `json:"error"`
+}
+
+// MakeGetTodoHistoryEndpoint returns an endpoint that invokes GetTodoHistory on the service.
+func MakeGetTodoHistoryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetTodoHistoryRequest)
+		h, error := s.GetTodoHistory(ctx, req.Id)
+		return GetTodoHistoryResponse{
+			H:     h,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetTodoHistoryResponse) Failed() error {
+	return r.Error
+}
+
+// GetTodoHistory implements Service. Primarily useful in a client.
+func (e Endpoints) GetTodoHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	request := GetTodoHistoryRequest{Id: id}
+	response, err := e.GetTodoHistoryEndpoint(ctx, request)
+	if err != nil {
+		return h, err
+	}
+	return response.(GetTodoHistoryResponse).H, response.(GetTodoHistoryResponse).Error
+}
+
+// GetCategoryHistoryRequest collects the request parameters for the GetCategoryHistory method.
+type GetCategoryHistoryRequest struct {
+	Id string `json:"id"`
+}
+
+// GetCategoryHistoryResponse collects the response parameters for the GetCategoryHistory method.
+type GetCategoryHistoryResponse struct {
+	H     []io.Change `json:"h"`
+	Error error       `json:"error"`
+}
+
+// MakeGetCategoryHistoryEndpoint returns an endpoint that invokes GetCategoryHistory on the service.
+func MakeGetCategoryHistoryEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetCategoryHistoryRequest)
+		h, error := s.GetCategoryHistory(ctx, req.Id)
+		return GetCategoryHistoryResponse{
+			H:     h,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r GetCategoryHistoryResponse) Failed() error {
+	return r.Error
+}
+
+// GetCategoryHistory implements Service. Primarily useful in a client.
+func (e Endpoints) GetCategoryHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	request := GetCategoryHistoryRequest{Id: id}
+	response, err := e.GetCategoryHistoryEndpoint(ctx, request)
+	if err != nil {
+		return h, err
+	}
+	return response.(GetCategoryHistoryResponse).H, response.(GetCategoryHistoryResponse).Error
+}
+
+// UndoRequest collects the request parameters for the Undo method.
+type UndoRequest struct {
+	Steps int `json:"steps"`
+}
+
+// UndoResponse collects the response parameters for the Undo method.
+type UndoResponse struct {
+	H     []io.Change `json:"h"`
+	Error error       `json:"error"`
+}
+
+// MakeUndoEndpoint returns an endpoint that invokes Undo on the service.
+func MakeUndoEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(UndoRequest)
+		h, error := s.Undo(ctx, req.Steps)
+		return UndoResponse{
+			H:     h,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r UndoResponse) Failed() error {
+	return r.Error
+}
+
+// Undo implements Service. Primarily useful in a client.
+func (e Endpoints) Undo(ctx context.Context, steps int) (h []io.Change, error error) {
+	request := UndoRequest{Steps: steps}
+	response, err := e.UndoEndpoint(ctx, request)
+	if err != nil {
+		return h, err
+	}
+	return response.(UndoResponse).H, response.(UndoResponse).Error
+}
+
+// RedoRequest collects the request parameters for the Redo method.
+type RedoRequest struct {
+	Steps int `json:"steps"`
+}
+
+// RedoResponse collects the response parameters for the Redo method.
+type RedoResponse struct {
+	H     []io.Change `json:"h"`
+	Error error       `json:"error"`
+}
+
+// MakeRedoEndpoint returns an endpoint that invokes Redo on the service.
+func MakeRedoEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(RedoRequest)
+		h, error := s.Redo(ctx, req.Steps)
+		return RedoResponse{
+			H:     h,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r RedoResponse) Failed() error {
+	return r.Error
+}
+
+// Redo implements Service. Primarily useful in a client.
+func (e Endpoints) Redo(ctx context.Context, steps int) (h []io.Change, error error) {
+	request := RedoRequest{Steps: steps}
+	response, err := e.RedoEndpoint(ctx, request)
+	if err != nil {
+		return h, err
+	}
+	return response.(RedoResponse).H, response.(RedoResponse).Error
+}
+
+// FindTodosRequest collects the request parameters for the FindTodos method.
+type FindTodosRequest struct {
+	By  string `json:"by"`
+	Ids []uint `json:"ids"`
+}
+
+// FindTodosResponse collects the response parameters for the FindTodos method.
+type FindTodosResponse struct {
+	T     []io.Todo `json:"t"`
+	Error error     `json:"error"`
+}
+
+// MakeFindTodosEndpoint returns an endpoint that invokes FindTodos on the service.
+func MakeFindTodosEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(FindTodosRequest)
+		t, error := s.FindTodos(ctx, req.By, req.Ids)
+		return FindTodosResponse{
+			T:     t,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r FindTodosResponse) Failed() error {
+	return r.Error
+}
+
+// FindTodos implements Service. Primarily useful in a client.
+func (e Endpoints) FindTodos(ctx context.Context, by string, ids []uint) (t []io.Todo, error error) {
+	request := FindTodosRequest{
+		By:  by,
+		Ids: ids,
+	}
+	response, err := e.FindTodosEndpoint(ctx, request)
+	if err != nil {
+		return t, err
+	}
+	return response.(FindTodosResponse).T, response.(FindTodosResponse).Error
+}
+
+// FindCategoriesRequest collects the request parameters for the FindCategories method.
+type FindCategoriesRequest struct {
+	By  string `json:"by"`
+	Ids []uint `json:"ids"`
+}
+
+// FindCategoriesResponse collects the response parameters for the FindCategories method.
+type FindCategoriesResponse struct {
+	C     []io.TodoCategory `json:"c"`
+	Error error             `json:"error"`
+}
+
+// MakeFindCategoriesEndpoint returns an endpoint that invokes FindCategories on the service.
+func MakeFindCategoriesEndpoint(s service.TodoService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(FindCategoriesRequest)
+		c, error := s.FindCategories(ctx, req.By, req.Ids)
+		return FindCategoriesResponse{
+			C:     c,
+			Error: error,
+		}, nil
+	}
+}
+
+// Failed implements Failer.
+func (r FindCategoriesResponse) Failed() error {
+	return r.Error
+}
+
+// FindCategories implements Service. Primarily useful in a client.
+func (e Endpoints) FindCategories(ctx context.Context, by string, ids []uint) (c []io.TodoCategory, error error) {
+	request := FindCategoriesRequest{
+		By:  by,
+		Ids: ids,
+	}
+	response, err := e.FindCategoriesEndpoint(ctx, request)
+	if err != nil {
+		return c, err
+	}
+	return response.(FindCategoriesResponse).C, response.(FindCategoriesResponse).Error
+}
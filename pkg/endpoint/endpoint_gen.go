@@ -10,40 +10,124 @@ import (
 // meant to be used as a helper struct, to collect all of the endpoints into a
 // single parameter.
 type Endpoints struct {
-	GetEndpoint            endpoint.Endpoint
-	AddEndpoint            endpoint.Endpoint
-	SetCompleteEndpoint    endpoint.Endpoint
-	RemoveCompleteEndpoint endpoint.Endpoint
-	DeleteEndpoint         endpoint.Endpoint
-	UpdateEndpoint         endpoint.Endpoint
-	SetStarEndpoint        endpoint.Endpoint
-	ReplyToEndpoint        endpoint.Endpoint
-	GetChildesEndpoint     endpoint.Endpoint
-	GetCategoryEndpoint    endpoint.Endpoint
-	AddCategoryEndpoint    endpoint.Endpoint
-	UpdateCategoryEndpoint endpoint.Endpoint
-	DeleteCategoryEndpoint endpoint.Endpoint
-	GetCatChildesEndpoint  endpoint.Endpoint
+	GetEndpoint                  endpoint.Endpoint
+	AddEndpoint                  endpoint.Endpoint
+	SetCompleteEndpoint          endpoint.Endpoint
+	RemoveCompleteEndpoint       endpoint.Endpoint
+	DeleteEndpoint               endpoint.Endpoint
+	UpdateEndpoint               endpoint.Endpoint
+	SetStarEndpoint              endpoint.Endpoint
+	ReplyToEndpoint              endpoint.Endpoint
+	GetChildesEndpoint           endpoint.Endpoint
+	GetCategoryEndpoint          endpoint.Endpoint
+	AddCategoryEndpoint          endpoint.Endpoint
+	UpdateCategoryEndpoint       endpoint.Endpoint
+	DeleteCategoryEndpoint       endpoint.Endpoint
+	GetCatChildesEndpoint        endpoint.Endpoint
+	AddWebhookEndpoint           endpoint.Endpoint
+	GetWebhooksEndpoint          endpoint.Endpoint
+	DeleteWebhookEndpoint        endpoint.Endpoint
+	GetWebhookDeliveriesEndpoint endpoint.Endpoint
+	GetDeadLettersEndpoint       endpoint.Endpoint
+	RetryDeliveryEndpoint        endpoint.Endpoint
+	GetTreeEndpoint              endpoint.Endpoint
+	MoveEndpoint                 endpoint.Endpoint
+	MoveCategoryEndpoint         endpoint.Endpoint
+	ListTrashEndpoint            endpoint.Endpoint
+	RestoreEndpoint              endpoint.Endpoint
+	PurgeEndpoint                endpoint.Endpoint
+	GetCategoryTreeEndpoint      endpoint.Endpoint
+	GetCategoryPathEndpoint      endpoint.Endpoint
+	ReorderEndpoint              endpoint.Endpoint
+	TransitionEndpoint           endpoint.Endpoint
+	GetWorkflowEndpoint          endpoint.Endpoint
+	SetWorkflowEndpoint          endpoint.Endpoint
+	GetBoardEndpoint             endpoint.Endpoint
+	SetPriorityEndpoint          endpoint.Endpoint
+	GetMatrixEndpoint            endpoint.Endpoint
+	AddDependencyEndpoint        endpoint.Endpoint
+	RemoveDependencyEndpoint     endpoint.Endpoint
+	GetBlockedEndpoint           endpoint.Endpoint
+	GetReadyEndpoint             endpoint.Endpoint
+	GetDependencyGraphEndpoint   endpoint.Endpoint
+	GetCategoryPlanEndpoint      endpoint.Endpoint
+	GetTreePlanEndpoint          endpoint.Endpoint
+	StartTimerEndpoint           endpoint.Endpoint
+	StopTimerEndpoint            endpoint.Endpoint
+	AddTimeEntryEndpoint         endpoint.Endpoint
+	GetTimeEntriesEndpoint       endpoint.Endpoint
+	UpdateTimeEntryEndpoint      endpoint.Endpoint
+	DeleteTimeEntryEndpoint      endpoint.Endpoint
+	GetTimeReportEndpoint        endpoint.Endpoint
+	GetBurndownEndpoint          endpoint.Endpoint
+	GetTodoHistoryEndpoint       endpoint.Endpoint
+	GetCategoryHistoryEndpoint   endpoint.Endpoint
+	UndoEndpoint                 endpoint.Endpoint
+	RedoEndpoint                 endpoint.Endpoint
+	FindTodosEndpoint            endpoint.Endpoint
+	FindCategoriesEndpoint       endpoint.Endpoint
 }
 
 // New returns a Endpoints struct that wraps the provided service, and wires in all of the
 // expected endpoint middlewares
 func New(s service.TodoService, mdw map[string][]endpoint.Middleware) Endpoints {
 	eps := Endpoints{
-		AddCategoryEndpoint:    MakeAddCategoryEndpoint(s),
-		AddEndpoint:            MakeAddEndpoint(s),
-		DeleteCategoryEndpoint: MakeDeleteCategoryEndpoint(s),
-		DeleteEndpoint:         MakeDeleteEndpoint(s),
-		GetCatChildesEndpoint:  MakeGetCatChildesEndpoint(s),
-		GetCategoryEndpoint:    MakeGetCategoryEndpoint(s),
-		GetChildesEndpoint:     MakeGetChildesEndpoint(s),
-		GetEndpoint:            MakeGetEndpoint(s),
-		RemoveCompleteEndpoint: MakeRemoveCompleteEndpoint(s),
-		ReplyToEndpoint:        MakeReplyToEndpoint(s),
-		SetCompleteEndpoint:    MakeSetCompleteEndpoint(s),
-		SetStarEndpoint:        MakeSetStarEndpoint(s),
-		UpdateCategoryEndpoint: MakeUpdateCategoryEndpoint(s),
-		UpdateEndpoint:         MakeUpdateEndpoint(s),
+		AddCategoryEndpoint:          MakeAddCategoryEndpoint(s),
+		AddEndpoint:                  MakeAddEndpoint(s),
+		DeleteCategoryEndpoint:       MakeDeleteCategoryEndpoint(s),
+		DeleteEndpoint:               MakeDeleteEndpoint(s),
+		GetCatChildesEndpoint:        MakeGetCatChildesEndpoint(s),
+		GetCategoryEndpoint:          MakeGetCategoryEndpoint(s),
+		GetChildesEndpoint:           MakeGetChildesEndpoint(s),
+		GetEndpoint:                  MakeGetEndpoint(s),
+		RemoveCompleteEndpoint:       MakeRemoveCompleteEndpoint(s),
+		ReplyToEndpoint:              MakeReplyToEndpoint(s),
+		SetCompleteEndpoint:          MakeSetCompleteEndpoint(s),
+		SetStarEndpoint:              MakeSetStarEndpoint(s),
+		UpdateCategoryEndpoint:       MakeUpdateCategoryEndpoint(s),
+		UpdateEndpoint:               MakeUpdateEndpoint(s),
+		AddWebhookEndpoint:           MakeAddWebhookEndpoint(s),
+		GetWebhooksEndpoint:          MakeGetWebhooksEndpoint(s),
+		DeleteWebhookEndpoint:        MakeDeleteWebhookEndpoint(s),
+		GetWebhookDeliveriesEndpoint: MakeGetWebhookDeliveriesEndpoint(s),
+		GetDeadLettersEndpoint:       MakeGetDeadLettersEndpoint(s),
+		RetryDeliveryEndpoint:        MakeRetryDeliveryEndpoint(s),
+		GetTreeEndpoint:              MakeGetTreeEndpoint(s),
+		MoveEndpoint:                 MakeMoveEndpoint(s),
+		MoveCategoryEndpoint:         MakeMoveCategoryEndpoint(s),
+		ListTrashEndpoint:            MakeListTrashEndpoint(s),
+		RestoreEndpoint:              MakeRestoreEndpoint(s),
+		PurgeEndpoint:                MakePurgeEndpoint(s),
+		GetCategoryTreeEndpoint:      MakeGetCategoryTreeEndpoint(s),
+		GetCategoryPathEndpoint:      MakeGetCategoryPathEndpoint(s),
+		ReorderEndpoint:              MakeReorderEndpoint(s),
+		TransitionEndpoint:           MakeTransitionEndpoint(s),
+		GetWorkflowEndpoint:          MakeGetWorkflowEndpoint(s),
+		SetWorkflowEndpoint:          MakeSetWorkflowEndpoint(s),
+		GetBoardEndpoint:             MakeGetBoardEndpoint(s),
+		SetPriorityEndpoint:          MakeSetPriorityEndpoint(s),
+		GetMatrixEndpoint:            MakeGetMatrixEndpoint(s),
+		AddDependencyEndpoint:        MakeAddDependencyEndpoint(s),
+		RemoveDependencyEndpoint:     MakeRemoveDependencyEndpoint(s),
+		GetBlockedEndpoint:           MakeGetBlockedEndpoint(s),
+		GetReadyEndpoint:             MakeGetReadyEndpoint(s),
+		GetDependencyGraphEndpoint:   MakeGetDependencyGraphEndpoint(s),
+		GetCategoryPlanEndpoint:      MakeGetCategoryPlanEndpoint(s),
+		GetTreePlanEndpoint:          MakeGetTreePlanEndpoint(s),
+		StartTimerEndpoint:           MakeStartTimerEndpoint(s),
+		StopTimerEndpoint:            MakeStopTimerEndpoint(s),
+		AddTimeEntryEndpoint:         MakeAddTimeEntryEndpoint(s),
+		GetTimeEntriesEndpoint:       MakeGetTimeEntriesEndpoint(s),
+		UpdateTimeEntryEndpoint:      MakeUpdateTimeEntryEndpoint(s),
+		DeleteTimeEntryEndpoint:      MakeDeleteTimeEntryEndpoint(s),
+		GetTimeReportEndpoint:        MakeGetTimeReportEndpoint(s),
+		GetBurndownEndpoint:          MakeGetBurndownEndpoint(s),
+		GetTodoHistoryEndpoint:       MakeGetTodoHistoryEndpoint(s),
+		GetCategoryHistoryEndpoint:   MakeGetCategoryHistoryEndpoint(s),
+		UndoEndpoint:                 MakeUndoEndpoint(s),
+		RedoEndpoint:                 MakeRedoEndpoint(s),
+		FindTodosEndpoint:            MakeFindTodosEndpoint(s),
+		FindCategoriesEndpoint:       MakeFindCategoriesEndpoint(s),
 	}
 	for _, m := range mdw["Get"] {
 		eps.GetEndpoint = m(eps.GetEndpoint)
@@ -87,5 +171,131 @@ func New(s service.TodoService, mdw map[string][]endpoint.Middleware) Endpoints
 	for _, m := range mdw["GetCatChildes"] {
 		eps.GetCatChildesEndpoint = m(eps.GetCatChildesEndpoint)
 	}
+	for _, m := range mdw["AddWebhook"] {
+		eps.AddWebhookEndpoint = m(eps.AddWebhookEndpoint)
+	}
+	for _, m := range mdw["GetWebhooks"] {
+		eps.GetWebhooksEndpoint = m(eps.GetWebhooksEndpoint)
+	}
+	for _, m := range mdw["DeleteWebhook"] {
+		eps.DeleteWebhookEndpoint = m(eps.DeleteWebhookEndpoint)
+	}
+	for _, m := range mdw["GetWebhookDeliveries"] {
+		eps.GetWebhookDeliveriesEndpoint = m(eps.GetWebhookDeliveriesEndpoint)
+	}
+	for _, m := range mdw["GetDeadLetters"] {
+		eps.GetDeadLettersEndpoint = m(eps.GetDeadLettersEndpoint)
+	}
+	for _, m := range mdw["RetryDelivery"] {
+		eps.RetryDeliveryEndpoint = m(eps.RetryDeliveryEndpoint)
+	}
+	for _, m := range mdw["GetTree"] {
+		eps.GetTreeEndpoint = m(eps.GetTreeEndpoint)
+	}
+	for _, m := range mdw["Move"] {
+		eps.MoveEndpoint = m(eps.MoveEndpoint)
+	}
+	for _, m := range mdw["MoveCategory"] {
+		eps.MoveCategoryEndpoint = m(eps.MoveCategoryEndpoint)
+	}
+	for _, m := range mdw["ListTrash"] {
+		eps.ListTrashEndpoint = m(eps.ListTrashEndpoint)
+	}
+	for _, m := range mdw["Restore"] {
+		eps.RestoreEndpoint = m(eps.RestoreEndpoint)
+	}
+	for _, m := range mdw["Purge"] {
+		eps.PurgeEndpoint = m(eps.PurgeEndpoint)
+	}
+	for _, m := range mdw["GetCategoryTree"] {
+		eps.GetCategoryTreeEndpoint = m(eps.GetCategoryTreeEndpoint)
+	}
+	for _, m := range mdw["GetCategoryPath"] {
+		eps.GetCategoryPathEndpoint = m(eps.GetCategoryPathEndpoint)
+	}
+	for _, m := range mdw["Reorder"] {
+		eps.ReorderEndpoint = m(eps.ReorderEndpoint)
+	}
+	for _, m := range mdw["Transition"] {
+		eps.TransitionEndpoint = m(eps.TransitionEndpoint)
+	}
+	for _, m := range mdw["GetWorkflow"] {
+		eps.GetWorkflowEndpoint = m(eps.GetWorkflowEndpoint)
+	}
+	for _, m := range mdw["SetWorkflow"] {
+		eps.SetWorkflowEndpoint = m(eps.SetWorkflowEndpoint)
+	}
+	for _, m := range mdw["GetBoard"] {
+		eps.GetBoardEndpoint = m(eps.GetBoardEndpoint)
+	}
+	for _, m := range mdw["SetPriority"] {
+		eps.SetPriorityEndpoint = m(eps.SetPriorityEndpoint)
+	}
+	for _, m := range mdw["GetMatrix"] {
+		eps.GetMatrixEndpoint = m(eps.GetMatrixEndpoint)
+	}
+	for _, m := range mdw["AddDependency"] {
+		eps.AddDependencyEndpoint = m(eps.AddDependencyEndpoint)
+	}
+	for _, m := range mdw["RemoveDependency"] {
+		eps.RemoveDependencyEndpoint = m(eps.RemoveDependencyEndpoint)
+	}
+	for _, m := range mdw["GetBlocked"] {
+		eps.GetBlockedEndpoint = m(eps.GetBlockedEndpoint)
+	}
+	for _, m := range mdw["GetReady"] {
+		eps.GetReadyEndpoint = m(eps.GetReadyEndpoint)
+	}
+	for _, m := range mdw["GetDependencyGraph"] {
+		eps.GetDependencyGraphEndpoint = m(eps.GetDependencyGraphEndpoint)
+	}
+	for _, m := range mdw["GetCategoryPlan"] {
+		eps.GetCategoryPlanEndpoint = m(eps.GetCategoryPlanEndpoint)
+	}
+	for _, m := range mdw["GetTreePlan"] {
+		eps.GetTreePlanEndpoint = m(eps.GetTreePlanEndpoint)
+	}
+	for _, m := range mdw["StartTimer"] {
+		eps.StartTimerEndpoint = m(eps.StartTimerEndpoint)
+	}
+	for _, m := range mdw["StopTimer"] {
+		eps.StopTimerEndpoint = m(eps.StopTimerEndpoint)
+	}
+	for _, m := range mdw["AddTimeEntry"] {
+		eps.AddTimeEntryEndpoint = m(eps.AddTimeEntryEndpoint)
+	}
+	for _, m := range mdw["GetTimeEntries"] {
+		eps.GetTimeEntriesEndpoint = m(eps.GetTimeEntriesEndpoint)
+	}
+	for _, m := range mdw["UpdateTimeEntry"] {
+		eps.UpdateTimeEntryEndpoint = m(eps.UpdateTimeEntryEndpoint)
+	}
+	for _, m := range mdw["DeleteTimeEntry"] {
+		eps.DeleteTimeEntryEndpoint = m(eps.DeleteTimeEntryEndpoint)
+	}
+	for _, m := range mdw["GetTimeReport"] {
+		eps.GetTimeReportEndpoint = m(eps.GetTimeReportEndpoint)
+	}
+	for _, m := range mdw["GetBurndown"] {
+		eps.GetBurndownEndpoint = m(eps.GetBurndownEndpoint)
+	}
+	for _, m := range mdw["GetTodoHistory"] {
+		eps.GetTodoHistoryEndpoint = m(eps.GetTodoHistoryEndpoint)
+	}
+	for _, m := range mdw["GetCategoryHistory"] {
+		eps.GetCategoryHistoryEndpoint = m(eps.GetCategoryHistoryEndpoint)
+	}
+	for _, m := range mdw["Undo"] {
+		eps.UndoEndpoint = m(eps.UndoEndpoint)
+	}
+	for _, m := range mdw["Redo"] {
+		eps.RedoEndpoint = m(eps.RedoEndpoint)
+	}
+	for _, m := range mdw["FindTodos"] {
+		eps.FindTodosEndpoint = m(eps.FindTodosEndpoint)
+	}
+	for _, m := range mdw["FindCategories"] {
+		eps.FindCategoriesEndpoint = m(eps.FindCategoriesEndpoint)
+	}
 	return eps
 }
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"sync"
+	"time"
+	io "todo/pkg/io"
+)
+
+// Broker fans out change events to subscribers and keeps the most recent
+// ones so a reconnecting subscriber can resume where it left off.
+type Broker struct {
+	mtx     sync.Mutex
+	lastID  uint64
+	history []io.Event
+	size    int
+	// dropped is the id of the newest event that fell out of history.
+	dropped uint64
+	subs    map[chan io.Event]struct{}
+}
+
+// NewBroker returns a Broker that remembers the last size events.
+func NewBroker(size int) *Broker {
+	return &Broker{
+		size: size,
+		subs: map[chan io.Event]struct{}{},
+	}
+}
+
+// Publish sends event to the subscribers. Events without an id are given
+// the next one, others keep theirs. Subscribers that can't keep up are
+// unsubscribed, their channel closed, rather than block writes; they can
+// subscribe again from the last event they got.
+func (b *Broker) Publish(event io.Event) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if event.ID == 0 {
+		event.ID = b.lastID + 1
+	}
+	if event.ID > b.lastID {
+		b.lastID = event.ID
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	b.history = append(b.history, event)
+	if len(b.history) > b.size {
+		b.dropped = b.history[len(b.history)-b.size-1].ID
+		b.history = b.history[len(b.history)-b.size:]
+	}
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the remembered events newer than lastID and a channel
+// receiving every later event, which is closed if the subscriber falls
+// behind. missed is true when some events newer than lastID are no longer
+// remembered. cancel must be called once the subscriber is done.
+func (b *Broker) Subscribe(lastID uint64) (replay []io.Event, missed bool, events <-chan io.Event, cancel func()) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for _, e := range b.history {
+		if e.ID > lastID {
+			replay = append(replay, e)
+		}
+	}
+	ch := make(chan io.Event, 64)
+	b.subs[ch] = struct{}{}
+	return replay, lastID != 0 && lastID < b.dropped, ch, func() {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		delete(b.subs, ch)
+	}
+}
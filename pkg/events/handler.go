@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	http1 "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	io "todo/pkg/io"
+)
+
+// Visibility reports the categories the caller of r may see. all is true
+// when the caller may see every category.
+type Visibility func(r *http1.Request) (categories map[uint]bool, all bool)
+
+// ActorVisibility is the Visibility of the actor named by the X-Actor
+// header, which RequestInfo trusts as well: the categories grants lists
+// for it, or for "*", meaning every actor, with every category below them
+// in the tree. A grant of "*" lets the actor see every category. Every
+// actor sees the todos without a category, and a request without an
+// actor, or whose tree can not be read, sees nothing.
+func ActorVisibility(grants map[string][]string, tree func(ctx context.Context) ([]io.CategoryNode, error)) Visibility {
+	return func(r *http1.Request) (map[uint]bool, bool) {
+		actor := r.Header.Get("X-Actor")
+		if actor == "" {
+			return nil, false
+		}
+		granted := map[uint]bool{}
+		for _, g := range [][]string{grants["*"], grants[actor]} {
+			for _, v := range g {
+				if v == "*" {
+					return nil, true
+				}
+				if id, err := strconv.ParseUint(v, 10, 0); err == nil {
+					granted[uint(id)] = true
+				}
+			}
+		}
+		nodes, err := tree(r.Context())
+		if err != nil {
+			return nil, false
+		}
+		categories := map[uint]bool{0: true}
+		var walk func(nodes []io.CategoryNode, visible bool)
+		walk = func(nodes []io.CategoryNode, visible bool) {
+			for _, n := range nodes {
+				v := visible || granted[n.ID]
+				if v {
+					categories[n.ID] = true
+				}
+				walk(n.Children, v)
+			}
+		}
+		walk(nodes, false)
+		return categories, false
+	}
+}
+
+// CheckOrigin accepts the requests without an Origin header, which do not
+// come from browsers, and the ones from the server's own origin or one of
+// origins, "*" accepting all of them.
+func CheckOrigin(origins []string) func(r *http1.Request) bool {
+	allowed := map[string]bool{}
+	for _, v := range origins {
+		allowed[strings.TrimSuffix(v, "/")] = true
+	}
+	return func(r *http1.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowed["*"] || allowed[origin] {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+}
+
+// NewSSEHandler returns a handler streaming the events of b as
+// Server-Sent Events. Clients resume with the Last-Event-ID header (or the
+// lastEventId query parameter) and may narrow the stream with one or more
+// category query parameters; events outside the categories visible says
+// the caller may see are never sent. A client that falls behind is
+// disconnected, to resume from its last event; when the events since are
+// no longer all remembered, it first gets a reset event telling it to
+// reload its state. Browsers may only subscribe from the origins
+// CheckOrigin accepts.
+func NewSSEHandler(b *Broker, visible Visibility, origins []string) http1.Handler {
+	checkOrigin := CheckOrigin(origins)
+	return http1.HandlerFunc(func(w http1.ResponseWriter, r *http1.Request) {
+		if !checkOrigin(r) {
+			http1.Error(w, "origin not allowed", http1.StatusForbidden)
+			return
+		}
+		flusher, ok := w.(http1.Flusher)
+		if !ok {
+			http1.Error(w, "streaming unsupported", http1.StatusInternalServerError)
+			return
+		}
+		filter, err := newFilter(r, visible)
+		if err != nil {
+			http1.Error(w, err.Error(), http1.StatusBadRequest)
+			return
+		}
+		lastID := r.Header.Get("Last-Event-ID")
+		if lastID == "" {
+			lastID = r.URL.Query().Get("lastEventId")
+		}
+		var since uint64
+		if lastID != "" {
+			if since, err = strconv.ParseUint(lastID, 10, 64); err != nil {
+				http1.Error(w, "not a valid Last-Event-ID", http1.StatusBadRequest)
+				return
+			}
+		}
+
+		replay, missed, events, cancel := b.Subscribe(since)
+		defer cancel()
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		if origin := r.Header.Get("Origin"); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+		w.WriteHeader(http1.StatusOK)
+		if missed {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		for _, e := range replay {
+			if filter(e) {
+				writeEvent(w, e)
+			}
+		}
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(15 * time.Second)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				if !filter(e) {
+					continue
+				}
+				writeEvent(w, e)
+			}
+			flusher.Flush()
+		}
+	})
+}
+
+func newFilter(r *http1.Request, visible Visibility) (func(io.Event) bool, error) {
+	categories, all := visible(r)
+	requested := map[uint]bool{}
+	for _, v := range r.URL.Query()["category"] {
+		id, err := strconv.ParseUint(v, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("not a valid category: %q", v)
+		}
+		requested[uint(id)] = true
+	}
+	return func(e io.Event) bool {
+		if !all && !categories[e.CategoryID] {
+			return false
+		}
+		return len(requested) == 0 || requested[e.CategoryID]
+	}, nil
+}
+
+func writeEvent(w http1.ResponseWriter, e io.Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, b)
+}
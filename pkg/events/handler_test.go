@@ -0,0 +1,68 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	io "todo/pkg/io"
+)
+
+func TestActorVisibility(t *testing.T) {
+	node := func(id uint, children ...io.CategoryNode) io.CategoryNode {
+		n := io.CategoryNode{Children: children}
+		n.ID = id
+		return n
+	}
+	tree := []io.CategoryNode{node(1, node(2, node(3))), node(4), node(5)}
+	grants := map[string][]string{"*": {"5"}, "alice": {"2"}, "bob": {"4", "x"}, "ops": {"*"}}
+	tests := []struct {
+		actor string
+		err   error
+		want  map[uint]bool
+		all   bool
+	}{
+		{"", nil, nil, false},
+		{"alice", nil, map[uint]bool{0: true, 2: true, 3: true, 5: true}, false},
+		{"bob", nil, map[uint]bool{0: true, 4: true, 5: true}, false},
+		{"carol", nil, map[uint]bool{0: true, 5: true}, false},
+		{"ops", nil, nil, true},
+		{"alice", errors.New("down"), nil, false},
+	}
+	for _, tt := range tests {
+		visible := ActorVisibility(grants, func(ctx context.Context) ([]io.CategoryNode, error) { return tree, tt.err })
+		r := httptest.NewRequest("GET", "/events", nil)
+		if tt.actor != "" {
+			r.Header.Set("X-Actor", tt.actor)
+		}
+		got, all := visible(r)
+		if all != tt.all || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q (%v) sees %v, %v, want %v, %v", tt.actor, tt.err, got, all, tt.want, tt.all)
+		}
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		origins []string
+		origin  string
+		want    bool
+	}{
+		{nil, "", true},
+		{nil, "http://todo.example", true},
+		{nil, "http://evil.example", false},
+		{[]string{"http://app.example/"}, "http://app.example", true},
+		{[]string{"http://app.example"}, "http://evil.example", false},
+		{[]string{"*"}, "http://evil.example", true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://todo.example/events", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := CheckOrigin(tt.origins)(r); got != tt.want {
+			t.Errorf("CheckOrigin(%v) of %q = %v, want %v", tt.origins, tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package graphql
+
+import (
+	http1 "net/http"
+	service "todo/pkg/service"
+
+	handlers "github.com/gorilla/handlers"
+	graphql "github.com/graph-gophers/graphql-go"
+	relay "github.com/graph-gophers/graphql-go/relay"
+)
+
+// NewHandler returns a handler serving GraphQL queries and mutations over
+// svc. Each request gets its own loader, so reads are batched per request
+// and never served stale across requests.
+func NewHandler(svc service.TodoService) http1.Handler {
+	h := &relay.Handler{Schema: graphql.MustParseSchema(schema, &resolver{})}
+	return handlers.CORS(
+		handlers.AllowedOrigins([]string{"*"}),
+		handlers.AllowedHeaders([]string{"Content-Type", "Content-Length"}),
+		handlers.AllowedMethods([]string{"POST"}),
+	)(http1.HandlerFunc(func(w http1.ResponseWriter, r *http1.Request) {
+		if r.Method != http1.MethodPost {
+			w.Header().Set("Allow", http1.MethodPost)
+			http1.Error(w, "method not allowed", http1.StatusMethodNotAllowed)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(withLoader(r.Context(), svc)))
+	}))
+}
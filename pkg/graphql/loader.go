@@ -0,0 +1,251 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+	io "todo/pkg/io"
+	service "todo/pkg/service"
+)
+
+// batchWait is how long a batch waits for the keys asked for by the
+// sibling fields graphql-go resolves concurrently.
+const batchWait = time.Millisecond
+
+type loaderKey struct{}
+
+// loader batches the reads of a single GraphQL request. Each relation, the
+// todos or the categories by id, by parent or by category, has its own
+// keyLoader: the keys one resolve pass asks for are fetched together, with
+// a single `WHERE column IN (...)` query, and remembered for the rest of
+// the request. Mutations call invalidate so the fields selected on their
+// result see the change.
+type loader struct {
+	svc service.TodoService
+
+	mtx sync.Mutex
+	rel *relations
+}
+
+type relations struct {
+	todoByID           *keyLoader
+	todosByParent      *keyLoader
+	todosByCategory    *keyLoader
+	categoryByID       *keyLoader
+	categoriesByParent *keyLoader
+}
+
+func withLoader(ctx context.Context, svc service.TodoService) context.Context {
+	l := &loader{svc: svc}
+	l.invalidate()
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+func (l *loader) invalidate() {
+	r := &relations{}
+	r.todoByID = newKeyLoader(l.findTodos(r, "id", false))
+	r.todosByParent = newKeyLoader(l.findTodos(r, "parent_id", true))
+	r.todosByCategory = newKeyLoader(l.findTodos(r, "category_id", true))
+	r.categoryByID = newKeyLoader(l.findCategories(r, "id", false))
+	r.categoriesByParent = newKeyLoader(l.findCategories(r, "parent_id", true))
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.rel = r
+}
+
+func (l *loader) relations() *relations {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.rel
+}
+
+// findTodos fetches the todos whose column by is one of the keys, keyed by
+// that column: one *io.Todo per key, or a []*io.Todo when many is set. The
+// todos found are remembered by id as well.
+func (l *loader) findTodos(r *relations, by string, many bool) fetchFunc {
+	return func(ctx context.Context, keys []uint) (map[uint]interface{}, error) {
+		t, err := l.svc.FindTodos(ctx, by, keys)
+		if err != nil {
+			return nil, err
+		}
+		values := map[uint]interface{}{}
+		if many {
+			for _, k := range keys {
+				values[k] = []*io.Todo{}
+			}
+		}
+		for i := range t {
+			v := &t[i]
+			r.todoByID.prime(v.ID, v)
+			k := v.ID
+			switch by {
+			case "parent_id":
+				k = v.ParentID
+			case "category_id":
+				k = v.CategoryID
+			}
+			if many {
+				values[k] = append(values[k].([]*io.Todo), v)
+			} else {
+				values[k] = v
+			}
+		}
+		return values, nil
+	}
+}
+
+// findCategories is findTodos for the categories.
+func (l *loader) findCategories(r *relations, by string, many bool) fetchFunc {
+	return func(ctx context.Context, keys []uint) (map[uint]interface{}, error) {
+		c, err := l.svc.FindCategories(ctx, by, keys)
+		if err != nil {
+			return nil, err
+		}
+		values := map[uint]interface{}{}
+		if many {
+			for _, k := range keys {
+				values[k] = []*io.TodoCategory{}
+			}
+		}
+		for i := range c {
+			v := &c[i]
+			r.categoryByID.prime(v.ID, v)
+			k := v.ID
+			if by == "parent_id" {
+				k = v.ParentID
+			}
+			if many {
+				values[k] = append(values[k].([]*io.TodoCategory), v)
+			} else {
+				values[k] = v
+			}
+		}
+		return values, nil
+	}
+}
+
+func (l *loader) todo(ctx context.Context, id uint) (*io.Todo, error) {
+	v, err := l.relations().todoByID.load(ctx, id)
+	if v == nil || err != nil {
+		return nil, err
+	}
+	return v.(*io.Todo), nil
+}
+
+func (l *loader) children(ctx context.Context, id uint) ([]*io.Todo, error) {
+	v, err := l.relations().todosByParent.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*io.Todo), nil
+}
+
+func (l *loader) categoryTodos(ctx context.Context, id uint) ([]*io.Todo, error) {
+	v, err := l.relations().todosByCategory.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*io.Todo), nil
+}
+
+func (l *loader) category(ctx context.Context, id uint) (*io.TodoCategory, error) {
+	v, err := l.relations().categoryByID.load(ctx, id)
+	if v == nil || err != nil {
+		return nil, err
+	}
+	return v.(*io.TodoCategory), nil
+}
+
+func (l *loader) subCategories(ctx context.Context, id uint) ([]*io.TodoCategory, error) {
+	v, err := l.relations().categoriesByParent.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*io.TodoCategory), nil
+}
+
+// prime remembers todos and categories read in full, by id.
+func (l *loader) prime(t []*io.Todo, c []*io.TodoCategory) {
+	r := l.relations()
+	for _, v := range t {
+		r.todoByID.prime(v.ID, v)
+	}
+	for _, v := range c {
+		r.categoryByID.prime(v.ID, v)
+	}
+}
+
+// fetchFunc loads the values of keys with one query. A key without a value
+// is missing from the map.
+type fetchFunc func(ctx context.Context, keys []uint) (map[uint]interface{}, error)
+
+// keyLoader is the loader of one relation. The first key asked for that is
+// not known yet opens a batch, which every key asked for within batchWait
+// joins, and the batch is then fetched at once.
+type keyLoader struct {
+	fetch fetchFunc
+
+	mtx     sync.Mutex
+	results map[uint]*result
+	pending []uint
+}
+
+type result struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func newKeyLoader(fetch fetchFunc) *keyLoader {
+	return &keyLoader{fetch: fetch, results: map[uint]*result{}}
+}
+
+// load returns the value of key, nil if there is none, waiting for the
+// batch fetching it.
+func (k *keyLoader) load(ctx context.Context, key uint) (interface{}, error) {
+	k.mtx.Lock()
+	r, ok := k.results[key]
+	if !ok {
+		r = &result{done: make(chan struct{})}
+		k.results[key] = r
+		if len(k.pending) == 0 {
+			time.AfterFunc(batchWait, func() { k.run(ctx) })
+		}
+		k.pending = append(k.pending, key)
+	}
+	k.mtx.Unlock()
+	<-r.done
+	return r.value, r.err
+}
+
+// prime remembers the value of key, unless it is already known or being
+// fetched.
+func (k *keyLoader) prime(key uint, value interface{}) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	if _, ok := k.results[key]; ok {
+		return
+	}
+	r := &result{done: make(chan struct{}), value: value}
+	close(r.done)
+	k.results[key] = r
+}
+
+func (k *keyLoader) run(ctx context.Context) {
+	k.mtx.Lock()
+	keys := k.pending
+	k.pending = nil
+	k.mtx.Unlock()
+	values, err := k.fetch(ctx, keys)
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	for _, key := range keys {
+		r := k.results[key]
+		r.value, r.err = values[key], err
+		close(r.done)
+	}
+}
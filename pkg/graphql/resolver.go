@@ -0,0 +1,517 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	io "todo/pkg/io"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// resolver is the root resolver of both queries and mutations. Every read
+// goes through the request loader, every write through TodoService.
+type resolver struct{}
+
+type todoInput struct {
+	Title       string
+	Description *string
+	CategoryID  *graphql.ID
+	Star        *int32
+	Priority    *int32
+	Due         *graphql.Time
+	Estimate    *float64
+	Points      *float64
+}
+
+func (in todoInput) todo() io.Todo {
+	t := io.Todo{Title: in.Title}
+	if in.Description != nil {
+		t.Description = *in.Description
+	}
+	if in.CategoryID != nil {
+		t.CategoryID = parseID(*in.CategoryID)
+	}
+	if in.Star != nil {
+		t.Star = uint8(*in.Star)
+	}
+	if in.Priority != nil {
+		t.Priority = uint8(*in.Priority)
+	}
+	if in.Due != nil {
+		t.Due = &in.Due.Time
+	}
+	if in.Estimate != nil {
+		t.Estimate = *in.Estimate
+	}
+	if in.Points != nil {
+		t.Points = *in.Points
+	}
+	return t
+}
+
+type todoPatch struct {
+	Title       *string
+	Description *string
+	CategoryID  *graphql.ID
+	ParentID    *graphql.ID
+}
+
+type categoryInput struct {
+	Name     string
+	ParentID *graphql.ID
+}
+
+type transitionInput struct {
+	From string
+	To   []string
+}
+
+type workflowInput struct {
+	Statuses    []string
+	Transitions *[]transitionInput
+}
+
+func (in workflowInput) workflow() io.Workflow {
+	w := io.Workflow{Statuses: in.Statuses}
+	if in.Transitions != nil {
+		w.Transitions = io.Transitions{}
+		for _, v := range *in.Transitions {
+			w.Transitions[v.From] = v.To
+		}
+	}
+	return w
+}
+
+type timeEntryInput struct {
+	TodoID    *graphql.ID
+	Note      *string
+	StartedAt graphql.Time
+	EndedAt   *graphql.Time
+}
+
+func (in timeEntryInput) entry() io.TimeEntry {
+	te := io.TimeEntry{StartedAt: in.StartedAt.Time}
+	if in.TodoID != nil {
+		te.TodoID = parseID(*in.TodoID)
+	}
+	if in.Note != nil {
+		te.Note = *in.Note
+	}
+	if in.EndedAt != nil {
+		te.EndedAt = &in.EndedAt.Time
+	}
+	return te
+}
+
+// trashArgs names the item restore and purge are about.
+type trashArgs struct {
+	Kind    string
+	ID      graphql.ID
+	Subtree *bool
+}
+
+func (r *resolver) Todo(ctx context.Context, args struct{ ID graphql.ID }) (*todoResolver, error) {
+	t, err := loaderFrom(ctx).todo(ctx, parseID(args.ID))
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &todoResolver{t}, nil
+}
+
+// Todos narrows the todos it reads down to the filter's category or
+// parent, when it has one, and only reads them all otherwise.
+func (r *resolver) Todos(ctx context.Context, args struct{ Filter *todoFilter }) ([]*todoResolver, error) {
+	l := loaderFrom(ctx)
+	var t []*io.Todo
+	var err error
+	switch f := args.Filter; {
+	case f != nil && f.CategoryID != nil:
+		t, err = l.categoryTodos(ctx, parseID(*f.CategoryID))
+	case f != nil && f.ParentID != nil:
+		t, err = l.children(ctx, parseID(*f.ParentID))
+	default:
+		var all []io.Todo
+		all, err = l.svc.Get(ctx)
+		for i := range all {
+			t = append(t, &all[i])
+		}
+		l.prime(t, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return filterTodos(t, args.Filter), nil
+}
+
+func (r *resolver) Category(ctx context.Context, args struct{ ID graphql.ID }) (*categoryResolver, error) {
+	c, err := loaderFrom(ctx).category(ctx, parseID(args.ID))
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return &categoryResolver{c}, nil
+}
+
+func (r *resolver) Categories(ctx context.Context, args struct{ ParentID *graphql.ID }) ([]*categoryResolver, error) {
+	l := loaderFrom(ctx)
+	var c []*io.TodoCategory
+	var err error
+	if args.ParentID != nil {
+		c, err = l.subCategories(ctx, parseID(*args.ParentID))
+	} else {
+		var all []io.TodoCategory
+		all, err = l.svc.GetCategory(ctx)
+		for i := range all {
+			c = append(c, &all[i])
+		}
+		l.prime(nil, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	categories := []*categoryResolver{}
+	for _, v := range c {
+		categories = append(categories, &categoryResolver{v})
+	}
+	return categories, nil
+}
+
+func (r *resolver) AddTodo(ctx context.Context, args struct{ Todo todoInput }) (*todoResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	t, err := l.svc.Add(ctx, args.Todo.todo())
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) UpdateTodo(ctx context.Context, args struct {
+	ID    graphql.ID
+	Patch todoPatch
+}) (*todoResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	existing, err := l.todo(ctx, parseID(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("todo not found")
+	}
+	t := *existing
+	if args.Patch.Title != nil {
+		t.Title = *args.Patch.Title
+	}
+	if args.Patch.Description != nil {
+		t.Description = *args.Patch.Description
+	}
+	if args.Patch.CategoryID != nil {
+		t.CategoryID = parseID(*args.Patch.CategoryID)
+	}
+	if args.Patch.ParentID != nil {
+		t.ParentID = parseID(*args.Patch.ParentID)
+	}
+	t, err = l.svc.Update(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) SetComplete(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.SetComplete(ctx, string(args.ID))
+}
+
+func (r *resolver) RemoveComplete(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.RemoveComplete(ctx, string(args.ID))
+}
+
+func (r *resolver) SetStar(ctx context.Context, args struct {
+	ID   graphql.ID
+	Star int32
+}) (bool, error) {
+	if args.Star < 0 || args.Star > 5 {
+		return false, errors.New("star value out of range. valid range is 0 to 5")
+	}
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.SetStar(ctx, string(args.ID), uint8(args.Star))
+}
+
+func (r *resolver) SetPriority(ctx context.Context, args struct {
+	ID       graphql.ID
+	Priority int32
+}) (bool, error) {
+	if args.Priority < 0 || args.Priority > 4 {
+		return false, errors.New("priority value out of range. valid range is 1 to 4, 0 for none")
+	}
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.SetPriority(ctx, string(args.ID), uint8(args.Priority))
+}
+
+func (r *resolver) DeleteTodo(ctx context.Context, args struct {
+	ID     graphql.ID
+	Policy *string
+}) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.Delete(ctx, string(args.ID), deletePolicy(args.Policy))
+}
+
+func (r *resolver) ReplyTo(ctx context.Context, args struct {
+	ParentID graphql.ID
+	Todo     todoInput
+}) (*todoResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	t, err := l.svc.ReplyTo(ctx, parseID(args.ParentID), args.Todo.todo())
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) AddCategory(ctx context.Context, args struct{ Category categoryInput }) (*categoryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	c := io.TodoCategory{Name: args.Category.Name}
+	if args.Category.ParentID != nil {
+		c.ParentID = parseID(*args.Category.ParentID)
+	}
+	c, err := l.svc.AddCategory(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return &categoryResolver{&c}, nil
+}
+
+func (r *resolver) UpdateCategory(ctx context.Context, args struct {
+	ID       graphql.ID
+	Category categoryInput
+}) (*categoryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	existing, err := l.category(ctx, parseID(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, errors.New("category not found")
+	}
+	c := *existing
+	c.Name = args.Category.Name
+	if args.Category.ParentID != nil {
+		c.ParentID = parseID(*args.Category.ParentID)
+	}
+	c, err = l.svc.UpdateCategory(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return &categoryResolver{&c}, nil
+}
+
+func (r *resolver) DeleteCategory(ctx context.Context, args struct {
+	ID     graphql.ID
+	Policy *string
+}) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.DeleteCategory(ctx, string(args.ID), deletePolicy(args.Policy))
+}
+
+// deletePolicy maps a DeletePolicy value to the service's, refuse when
+// omitted.
+func deletePolicy(policy *string) string {
+	if policy == nil {
+		return io.DeleteRefuse
+	}
+	return strings.ToLower(*policy)
+}
+
+func (r *resolver) Move(ctx context.Context, args struct {
+	ID       graphql.ID
+	ParentID graphql.ID
+}) (*todoResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	t, err := l.svc.Move(ctx, string(args.ID), parseID(args.ParentID))
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) MoveCategory(ctx context.Context, args struct {
+	ID       graphql.ID
+	ParentID graphql.ID
+}) (*categoryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	c, err := l.svc.MoveCategory(ctx, string(args.ID), parseID(args.ParentID))
+	if err != nil {
+		return nil, err
+	}
+	return &categoryResolver{&c}, nil
+}
+
+func (r *resolver) Reorder(ctx context.Context, args struct {
+	ID     graphql.ID
+	Before *graphql.ID
+	After  *graphql.ID
+}) (*todoResolver, error) {
+	var before, after uint
+	if args.Before != nil {
+		before = parseID(*args.Before)
+	}
+	if args.After != nil {
+		after = parseID(*args.After)
+	}
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	t, err := l.svc.Reorder(ctx, string(args.ID), before, after)
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) Transition(ctx context.Context, args struct {
+	ID     graphql.ID
+	Status string
+}) (*todoResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	t, err := l.svc.Transition(ctx, string(args.ID), args.Status)
+	if err != nil {
+		return nil, err
+	}
+	return &todoResolver{&t}, nil
+}
+
+func (r *resolver) SetWorkflow(ctx context.Context, args struct {
+	CategoryID graphql.ID
+	Workflow   workflowInput
+}) (*workflowResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	w, err := l.svc.SetWorkflow(ctx, string(args.CategoryID), args.Workflow.workflow())
+	if err != nil {
+		return nil, err
+	}
+	return &workflowResolver{&w}, nil
+}
+
+func (r *resolver) AddDependency(ctx context.Context, args struct {
+	ID        graphql.ID
+	BlockerID graphql.ID
+}) (*dependencyResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	d, err := l.svc.AddDependency(ctx, string(args.ID), parseID(args.BlockerID))
+	if err != nil {
+		return nil, err
+	}
+	return &dependencyResolver{&d}, nil
+}
+
+func (r *resolver) RemoveDependency(ctx context.Context, args struct {
+	ID        graphql.ID
+	BlockerID graphql.ID
+}) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.RemoveDependency(ctx, string(args.ID), parseID(args.BlockerID))
+}
+
+func (r *resolver) StartTimer(ctx context.Context, args struct{ ID graphql.ID }) (*timeEntryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	te, err := l.svc.StartTimer(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntryResolver{&te}, nil
+}
+
+func (r *resolver) StopTimer(ctx context.Context) (*timeEntryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	te, err := l.svc.StopTimer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntryResolver{&te}, nil
+}
+
+func (r *resolver) AddTimeEntry(ctx context.Context, args struct {
+	TodoID graphql.ID
+	Entry  timeEntryInput
+}) (*timeEntryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	te, err := l.svc.AddTimeEntry(ctx, string(args.TodoID), args.Entry.entry())
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntryResolver{&te}, nil
+}
+
+func (r *resolver) UpdateTimeEntry(ctx context.Context, args struct {
+	ID    graphql.ID
+	Entry timeEntryInput
+}) (*timeEntryResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	te, err := l.svc.UpdateTimeEntry(ctx, string(args.ID), args.Entry.entry())
+	if err != nil {
+		return nil, err
+	}
+	return &timeEntryResolver{&te}, nil
+}
+
+func (r *resolver) DeleteTimeEntry(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.DeleteTimeEntry(ctx, string(args.ID))
+}
+
+func (r *resolver) Restore(ctx context.Context, args trashArgs) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.Restore(ctx, strings.ToLower(args.Kind), string(args.ID), args.Subtree != nil && *args.Subtree)
+}
+
+func (r *resolver) Purge(ctx context.Context, args trashArgs) (bool, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	return true, l.svc.Purge(ctx, strings.ToLower(args.Kind), string(args.ID), args.Subtree != nil && *args.Subtree)
+}
+
+func (r *resolver) Undo(ctx context.Context, args struct{ Steps *int32 }) ([]*changeResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	h, err := l.svc.Undo(ctx, steps(args.Steps))
+	return changes(h), err
+}
+
+func (r *resolver) Redo(ctx context.Context, args struct{ Steps *int32 }) ([]*changeResolver, error) {
+	l := loaderFrom(ctx)
+	defer l.invalidate()
+	h, err := l.svc.Redo(ctx, steps(args.Steps))
+	return changes(h), err
+}
+
+// steps maps the steps of undo and redo to the service's, 0 for one when
+// omitted.
+func steps(steps *int32) int {
+	if steps == nil {
+		return 0
+	}
+	return int(*steps)
+}
@@ -0,0 +1,181 @@
+package graphql
+
+// schema describes the GraphQL view of TodoService. Ids are the numeric
+// database ids; a parentId or categoryId of "0" means "none".
+const schema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+# What happens to the subtasks of a deleted todo, or to the sub categories
+# and todos of a deleted category.
+enum DeletePolicy {
+	REFUSE
+	CASCADE
+	REPARENT
+}
+
+# Whether restore or purge is about a todo or a category.
+enum TrashKind {
+	TODO
+	CATEGORY
+}
+
+type Query {
+	todo(id: ID!): Todo
+	todos(filter: TodoFilter): [Todo!]!
+	category(id: ID!): TodoCategory
+	categories(parentId: ID): [TodoCategory!]!
+}
+
+input TodoFilter {
+	categoryId: ID
+	parentId: ID
+	complete: Boolean
+	minStar: Int
+	search: String
+}
+
+type Todo {
+	id: ID!
+	title: String!
+	description: String!
+	star: Int!
+	complete: Boolean!
+	status: String!
+	priority: Int!
+	due: Time
+	estimate: Float!
+	points: Float!
+	progress: Int!
+	position: Float!
+	createdAt: Time!
+	updatedAt: Time!
+	parent: Todo
+	children: [Todo!]!
+	category: TodoCategory
+}
+
+type TodoCategory {
+	id: ID!
+	name: String!
+	createdAt: Time!
+	updatedAt: Time!
+	parent: TodoCategory
+	ancestors: [TodoCategory!]!
+	path: String!
+	children: [TodoCategory!]!
+	todos(filter: TodoFilter): [Todo!]!
+}
+
+input TodoInput {
+	title: String!
+	description: String
+	categoryId: ID
+	star: Int
+	priority: Int
+	due: Time
+	estimate: Float
+	points: Float
+}
+
+input TodoPatch {
+	title: String
+	description: String
+	categoryId: ID
+	parentId: ID
+}
+
+input CategoryInput {
+	name: String!
+	parentId: ID
+}
+
+type Dependency {
+	id: ID!
+	todo: Todo
+	blocker: Todo
+	createdAt: Time!
+}
+
+# A status and the ones a todo may move to from it.
+type Transition {
+	from: String!
+	to: [String!]!
+}
+
+type Workflow {
+	categoryId: ID!
+	statuses: [String!]!
+	transitions: [Transition!]!
+}
+
+input TransitionInput {
+	from: String!
+	to: [String!]!
+}
+
+input WorkflowInput {
+	statuses: [String!]!
+	transitions: [TransitionInput!]
+}
+
+type TimeEntry {
+	id: ID!
+	todo: Todo
+	user: String!
+	note: String!
+	startedAt: Time!
+	endedAt: Time
+	hours: Float!
+}
+
+input TimeEntryInput {
+	todoId: ID
+	note: String
+	startedAt: Time!
+	endedAt: Time
+}
+
+type Change {
+	id: ID!
+	kind: String!
+	itemId: ID!
+	operation: String!
+	actor: String!
+	createdAt: Time!
+}
+
+type Mutation {
+	addTodo(todo: TodoInput!): Todo!
+	updateTodo(id: ID!, patch: TodoPatch!): Todo!
+	setComplete(id: ID!): Boolean!
+	removeComplete(id: ID!): Boolean!
+	setStar(id: ID!, star: Int!): Boolean!
+	setPriority(id: ID!, priority: Int!): Boolean!
+	deleteTodo(id: ID!, policy: DeletePolicy): Boolean!
+	replyTo(parentId: ID!, todo: TodoInput!): Todo!
+	addCategory(category: CategoryInput!): TodoCategory!
+	updateCategory(id: ID!, category: CategoryInput!): TodoCategory!
+	deleteCategory(id: ID!, policy: DeletePolicy): Boolean!
+	move(id: ID!, parentId: ID!): Todo!
+	moveCategory(id: ID!, parentId: ID!): TodoCategory!
+	reorder(id: ID!, before: ID, after: ID): Todo!
+	transition(id: ID!, status: String!): Todo!
+	setWorkflow(categoryId: ID!, workflow: WorkflowInput!): Workflow!
+	addDependency(id: ID!, blockerId: ID!): Dependency!
+	removeDependency(id: ID!, blockerId: ID!): Boolean!
+	startTimer(id: ID!): TimeEntry!
+	stopTimer: TimeEntry!
+	addTimeEntry(todoId: ID!, entry: TimeEntryInput!): TimeEntry!
+	updateTimeEntry(id: ID!, entry: TimeEntryInput!): TimeEntry!
+	deleteTimeEntry(id: ID!): Boolean!
+	restore(kind: TrashKind!, id: ID!, subtree: Boolean): Boolean!
+	purge(kind: TrashKind!, id: ID!, subtree: Boolean): Boolean!
+	undo(steps: Int): [Change!]!
+	redo(steps: Int): [Change!]!
+}
+`
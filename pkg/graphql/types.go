@@ -0,0 +1,378 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	io "todo/pkg/io"
+	service "todo/pkg/service"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type todoFilter struct {
+	CategoryID *graphql.ID
+	ParentID   *graphql.ID
+	Complete   *bool
+	MinStar    *int32
+	Search     *string
+}
+
+func (f *todoFilter) match(t *io.Todo) bool {
+	if f == nil {
+		return true
+	}
+	if f.CategoryID != nil && t.CategoryID != parseID(*f.CategoryID) {
+		return false
+	}
+	if f.ParentID != nil && t.ParentID != parseID(*f.ParentID) {
+		return false
+	}
+	if f.Complete != nil && t.Complete != *f.Complete {
+		return false
+	}
+	if f.MinStar != nil && int32(t.Star) < *f.MinStar {
+		return false
+	}
+	if f.Search != nil {
+		s := strings.ToLower(*f.Search)
+		if !strings.Contains(strings.ToLower(t.Title), s) && !strings.Contains(strings.ToLower(t.Description), s) {
+			return false
+		}
+	}
+	return true
+}
+
+func filterTodos(t []*io.Todo, f *todoFilter) []*todoResolver {
+	r := []*todoResolver{}
+	for _, v := range t {
+		if f.match(v) {
+			r = append(r, &todoResolver{v})
+		}
+	}
+	return r
+}
+
+func parseID(id graphql.ID) uint {
+	v, _ := strconv.ParseUint(string(id), 10, 0)
+	return uint(v)
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+type todoResolver struct {
+	t *io.Todo
+}
+
+func (r *todoResolver) ID() graphql.ID {
+	return formatID(r.t.ID)
+}
+
+func (r *todoResolver) Title() string {
+	return r.t.Title
+}
+
+func (r *todoResolver) Description() string {
+	return r.t.Description
+}
+
+func (r *todoResolver) Star() int32 {
+	return int32(r.t.Star)
+}
+
+func (r *todoResolver) Complete() bool {
+	return r.t.Complete
+}
+
+func (r *todoResolver) Priority() int32 {
+	return int32(r.t.Priority)
+}
+
+func (r *todoResolver) Due() *graphql.Time {
+	if r.t.Due == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.t.Due}
+}
+
+func (r *todoResolver) Estimate() float64 {
+	return r.t.Estimate
+}
+
+func (r *todoResolver) Points() float64 {
+	return r.t.Points
+}
+
+func (r *todoResolver) Status() string {
+	return r.t.Status
+}
+
+func (r *todoResolver) Progress() int32 {
+	return int32(r.t.Progress)
+}
+
+func (r *todoResolver) Position() float64 {
+	return r.t.Position
+}
+
+func (r *todoResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.t.CreatedAt}
+}
+
+func (r *todoResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.t.UpdatedAt}
+}
+
+func (r *todoResolver) Parent(ctx context.Context) (*todoResolver, error) {
+	if r.t.ParentID == 0 {
+		return nil, nil
+	}
+	t, err := loaderFrom(ctx).todo(ctx, r.t.ParentID)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &todoResolver{t}, nil
+}
+
+func (r *todoResolver) Children(ctx context.Context) ([]*todoResolver, error) {
+	t, err := loaderFrom(ctx).children(ctx, r.t.ID)
+	if err != nil {
+		return nil, err
+	}
+	return filterTodos(t, nil), nil
+}
+
+func (r *todoResolver) Category(ctx context.Context) (*categoryResolver, error) {
+	if r.t.CategoryID == 0 {
+		return nil, nil
+	}
+	c, err := loaderFrom(ctx).category(ctx, r.t.CategoryID)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return &categoryResolver{c}, nil
+}
+
+type categoryResolver struct {
+	c *io.TodoCategory
+}
+
+func (r *categoryResolver) ID() graphql.ID {
+	return formatID(r.c.ID)
+}
+
+func (r *categoryResolver) Name() string {
+	return r.c.Name
+}
+
+func (r *categoryResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.c.CreatedAt}
+}
+
+func (r *categoryResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.c.UpdatedAt}
+}
+
+func (r *categoryResolver) Parent(ctx context.Context) (*categoryResolver, error) {
+	if r.c.ParentID == 0 {
+		return nil, nil
+	}
+	c, err := loaderFrom(ctx).category(ctx, r.c.ParentID)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	return &categoryResolver{c}, nil
+}
+
+// Ancestors lists the parents of the category, root first. Each level is
+// one batch, shared with the other categories of the pass.
+func (r *categoryResolver) Ancestors(ctx context.Context) ([]*categoryResolver, error) {
+	l := loaderFrom(ctx)
+	ancestors := []*categoryResolver{}
+	seen := map[uint]bool{r.c.ID: true}
+	for id := r.c.ParentID; id != 0 && !seen[id]; {
+		c, err := l.category(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			break
+		}
+		seen[id] = true
+		ancestors = append([]*categoryResolver{{c}}, ancestors...)
+		id = c.ParentID
+	}
+	return ancestors, nil
+}
+
+// Path names the category after its ancestors, "Work / Backend / API".
+func (r *categoryResolver) Path(ctx context.Context) (string, error) {
+	ancestors, err := r.Ancestors(ctx)
+	if err != nil {
+		return "", err
+	}
+	names := []string{}
+	for _, v := range ancestors {
+		names = append(names, v.c.Name)
+	}
+	return strings.Join(append(names, r.c.Name), service.PathSeparator), nil
+}
+
+func (r *categoryResolver) Children(ctx context.Context) ([]*categoryResolver, error) {
+	c, err := loaderFrom(ctx).subCategories(ctx, r.c.ID)
+	if err != nil {
+		return nil, err
+	}
+	children := []*categoryResolver{}
+	for _, c := range c {
+		children = append(children, &categoryResolver{c})
+	}
+	return children, nil
+}
+
+func (r *categoryResolver) Todos(ctx context.Context, args struct{ Filter *todoFilter }) ([]*todoResolver, error) {
+	t, err := loaderFrom(ctx).categoryTodos(ctx, r.c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return filterTodos(t, args.Filter), nil
+}
+
+type dependencyResolver struct {
+	d *io.Dependency
+}
+
+func (r *dependencyResolver) ID() graphql.ID {
+	return formatID(r.d.ID)
+}
+
+func (r *dependencyResolver) Todo(ctx context.Context) (*todoResolver, error) {
+	return todoByID(ctx, r.d.TodoID)
+}
+
+func (r *dependencyResolver) Blocker(ctx context.Context) (*todoResolver, error) {
+	return todoByID(ctx, r.d.BlockerID)
+}
+
+func (r *dependencyResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.d.CreatedAt}
+}
+
+type workflowResolver struct {
+	w *io.Workflow
+}
+
+func (r *workflowResolver) CategoryID() graphql.ID {
+	return formatID(r.w.CategoryID)
+}
+
+func (r *workflowResolver) Statuses() []string {
+	return append([]string{}, r.w.Statuses...)
+}
+
+// Transitions lists the allowed moves in the order of the statuses they
+// start from.
+func (r *workflowResolver) Transitions() []*transitionResolver {
+	t := []*transitionResolver{}
+	for _, v := range r.w.Statuses {
+		if to, ok := r.w.Transitions[v]; ok {
+			t = append(t, &transitionResolver{v, to})
+		}
+	}
+	return t
+}
+
+type transitionResolver struct {
+	from string
+	to   []string
+}
+
+func (r *transitionResolver) From() string {
+	return r.from
+}
+
+func (r *transitionResolver) To() []string {
+	return append([]string{}, r.to...)
+}
+
+type timeEntryResolver struct {
+	te *io.TimeEntry
+}
+
+func (r *timeEntryResolver) ID() graphql.ID {
+	return formatID(r.te.ID)
+}
+
+func (r *timeEntryResolver) Todo(ctx context.Context) (*todoResolver, error) {
+	return todoByID(ctx, r.te.TodoID)
+}
+
+func (r *timeEntryResolver) User() string {
+	return r.te.User
+}
+
+func (r *timeEntryResolver) Note() string {
+	return r.te.Note
+}
+
+func (r *timeEntryResolver) StartedAt() graphql.Time {
+	return graphql.Time{Time: r.te.StartedAt}
+}
+
+func (r *timeEntryResolver) EndedAt() *graphql.Time {
+	if r.te.EndedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.te.EndedAt}
+}
+
+func (r *timeEntryResolver) Hours() float64 {
+	return r.te.Hours
+}
+
+type changeResolver struct {
+	c *io.Change
+}
+
+func changes(h []io.Change) []*changeResolver {
+	c := []*changeResolver{}
+	for i := range h {
+		c = append(c, &changeResolver{&h[i]})
+	}
+	return c
+}
+
+func (r *changeResolver) ID() graphql.ID {
+	return formatID(r.c.ID)
+}
+
+func (r *changeResolver) Kind() string {
+	return r.c.Kind
+}
+
+func (r *changeResolver) ItemID() graphql.ID {
+	return formatID(r.c.ItemID)
+}
+
+func (r *changeResolver) Operation() string {
+	return r.c.Operation
+}
+
+func (r *changeResolver) Actor() string {
+	return r.c.Actor
+}
+
+func (r *changeResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.c.CreatedAt}
+}
+
+// todoByID resolves a todo referenced by id, nil once it is gone.
+func todoByID(ctx context.Context, id uint) (*todoResolver, error) {
+	t, err := loaderFrom(ctx).todo(ctx, id)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return &todoResolver{t}, nil
+}
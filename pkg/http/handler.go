@@ -6,11 +6,15 @@ import (
 	"errors"
 	"fmt"
 	http1 "net/http"
+	"strconv"
+	"time"
 	endpoint "todo/pkg/endpoint"
+	service "todo/pkg/service"
 
 	http "github.com/go-kit/kit/transport/http"
 	handlers "github.com/gorilla/handlers"
 	mux "github.com/gorilla/mux"
+	gorm "github.com/jinzhu/gorm"
 )
 
 // makeGetHandler creates the handler logic
@@ -168,6 +172,10 @@ func encodeRemoveCompleteResponse(ctx context.Context, w http1.ResponseWriter, r
 //	m.Methods("POST").Path("/delete").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.DeleteEndpoint, decodeDeleteRequest, encodeDeleteResponse, options...)))
 //}
 
+// Without a policy query /delete/{id} refuses, with 409 Conflict, to delete
+// a todo that has subtasks. Before delete policies it deleted the todo
+// alone and left its subtasks behind; callers relying on that now pass
+// policy=reparent or policy=cascade.
 func makeDeleteHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
 	m.Methods("DELETE", "OPTIONS").Path("/delete/{id}").Handler(
 		handlers.CORS(
@@ -192,7 +200,8 @@ func decodeDeleteRequest(_ context.Context, r *http1.Request) (interface{}, erro
 		return nil, errors.New("not a valid ID")
 	}
 	req := endpoint.DeleteRequest{
-		Id: id,
+		Id:     id,
+		Policy: r.URL.Query().Get("policy"),
 	}
 	return req, nil
 }
@@ -223,6 +232,17 @@ func ErrorDecoder(r *http1.Response) error {
 // This is used to set the http status, see an example here :
 // https://github.com/go-kit/kit/blob/master/examples/addsvc/pkg/addtransport/http.go#L133
 func err2code(err error) int {
+	switch {
+	case errors.Is(err, service.ErrBeforeHistory):
+		return http1.StatusBadRequest
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http1.StatusNotFound
+	case errors.Is(err, service.ErrHasChildren), errors.Is(err, service.ErrCycle), errors.Is(err, service.ErrParentTrashed),
+		errors.Is(err, service.ErrParentNotFound), errors.Is(err, service.ErrBlocked), errors.Is(err, service.ErrDependencyCycle),
+		errors.Is(err, service.ErrTransition), errors.Is(err, service.ErrTimerRunning), errors.Is(err, service.ErrUndoConflict),
+		errors.Is(err, service.ErrNotUndoable), errors.Is(err, service.ErrNothingToUndo):
+		return http1.StatusConflict
+	}
 	return http1.StatusInternalServerError
 }
 
@@ -230,6 +250,42 @@ type errorWrapper struct {
 	Error string `json:"error"`
 }
 
+// RequestInfo hands the actor named by the X-Actor header and the request
+// id, from X-Request-ID or a new one, down to the service for the change
+// history. X-Actor is trusted as is: an authenticating proxy in front of
+// the service must set it. The request id is sent back in X-Request-ID. The as_of query
+// parameter, a day or an RFC 3339 time, asks the listing and tree methods
+// for the state at that time.
+func RequestInfo(next http1.Handler) http1.Handler {
+	return http1.HandlerFunc(func(w http1.ResponseWriter, r *http1.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			id = service.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		ctx := service.WithRequestID(r.Context(), id)
+		ctx = service.WithActor(ctx, r.Header.Get("X-Actor"))
+		if v := r.URL.Query().Get("as_of"); v != "" {
+			at, err := parseAsOf(v)
+			if err != nil {
+				w.WriteHeader(http1.StatusBadRequest)
+				json.NewEncoder(w).Encode(errorWrapper{Error: "as_of: " + err.Error()})
+				return
+			}
+			ctx = service.WithAsOf(ctx, at)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// parseAsOf reads a day, midnight local time, or an RFC 3339 time.
+func parseAsOf(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
 func makeUpdateHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
 	m.Methods("PUT", "OPTIONS").Path("/update").Handler(
 		handlers.CORS(
@@ -417,7 +473,9 @@ func encodeUpdateCategoryResponse(ctx context.Context, w http1.ResponseWriter, r
 	return
 }
 
-// makeDeleteCategoryHandler creates the handler logic
+// makeDeleteCategoryHandler creates the handler logic. Without a policy
+// /delete-category refuses, with 409 Conflict, to delete a category that
+// has sub categories or todos, which it used to leave behind.
 func makeDeleteCategoryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
 	m.Methods("DELETE").Path("/delete-category").Handler(handlers.CORS(handlers.AllowedMethods([]string{"DELETE"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.DeleteCategoryEndpoint, decodeDeleteCategoryRequest, encodeDeleteCategoryResponse, options...)))
 }
@@ -466,3 +524,1272 @@ func encodeGetCatChildesResponse(ctx context.Context, w http1.ResponseWriter, re
 	err = json.NewEncoder(w).Encode(response)
 	return
 }
+
+// makeAddWebhookHandler creates the handler logic
+func makeAddWebhookHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/webhooks").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.AddWebhookEndpoint, decodeAddWebhookRequest, encodeAddWebhookResponse, options...)))
+}
+
+// decodeAddWebhookRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeAddWebhookRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.AddWebhookRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeAddWebhookResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeAddWebhookResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetWebhooksHandler creates the handler logic
+func makeGetWebhooksHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/webhooks").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetWebhooksEndpoint, decodeGetWebhooksRequest, encodeGetWebhooksResponse, options...)))
+}
+
+// decodeGetWebhooksRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetWebhooksRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetWebhooksRequest{}
+	return req, nil
+}
+
+// encodeGetWebhooksResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetWebhooksResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeDeleteWebhookHandler creates the handler logic
+func makeDeleteWebhookHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("DELETE").Path("/webhooks/{id}").Handler(handlers.CORS(handlers.AllowedMethods([]string{"DELETE"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.DeleteWebhookEndpoint, decodeDeleteWebhookRequest, encodeDeleteWebhookResponse, options...)))
+}
+
+// decodeDeleteWebhookRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeDeleteWebhookRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.DeleteWebhookRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeDeleteWebhookResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeDeleteWebhookResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetWebhookDeliveriesHandler creates the handler logic
+func makeGetWebhookDeliveriesHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/webhooks/{id}/deliveries").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetWebhookDeliveriesEndpoint, decodeGetWebhookDeliveriesRequest, encodeGetWebhookDeliveriesResponse, options...)))
+}
+
+// decodeGetWebhookDeliveriesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetWebhookDeliveriesRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetWebhookDeliveriesRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetWebhookDeliveriesResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetWebhookDeliveriesResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetDeadLettersHandler creates the handler logic
+func makeGetDeadLettersHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/webhooks/dead-letters").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetDeadLettersEndpoint, decodeGetDeadLettersRequest, encodeGetDeadLettersResponse, options...)))
+}
+
+// decodeGetDeadLettersRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetDeadLettersRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetDeadLettersRequest{}
+	return req, nil
+}
+
+// encodeGetDeadLettersResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetDeadLettersResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeRetryDeliveryHandler creates the handler logic
+func makeRetryDeliveryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/webhooks/deliveries/{id}/retry").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.RetryDeliveryEndpoint, decodeRetryDeliveryRequest, encodeRetryDeliveryResponse, options...)))
+}
+
+// decodeRetryDeliveryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeRetryDeliveryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.RetryDeliveryRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeRetryDeliveryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeRetryDeliveryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetTreeHandler creates the handler logic
+func makeGetTreeHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/{id}/tree").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetTreeEndpoint, decodeGetTreeRequest, encodeGetTreeResponse, options...)))
+}
+
+// decodeGetTreeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetTreeRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetTreeRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	q := r.URL.Query()
+	if v := q.Get("max_depth"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("not a valid max_depth")
+		}
+		req.MaxDepth = x
+	}
+	return req, nil
+}
+
+// encodeGetTreeResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetTreeResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeMoveHandler creates the handler logic
+func makeMoveHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/todos/{id}/move").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.MoveEndpoint, decodeMoveRequest, encodeMoveResponse, options...)))
+}
+
+// decodeMoveRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeMoveRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.MoveRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeMoveResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeMoveResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeMoveCategoryHandler creates the handler logic
+func makeMoveCategoryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/categories/{id}/move").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.MoveCategoryEndpoint, decodeMoveCategoryRequest, encodeMoveCategoryResponse, options...)))
+}
+
+// decodeMoveCategoryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeMoveCategoryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.MoveCategoryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeMoveCategoryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeMoveCategoryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeListTrashHandler creates the handler logic
+func makeListTrashHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/trash").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.ListTrashEndpoint, decodeListTrashRequest, encodeListTrashResponse, options...)))
+}
+
+// decodeListTrashRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeListTrashRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.ListTrashRequest{}
+	return req, nil
+}
+
+// encodeListTrashResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeListTrashResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeRestoreHandler creates the handler logic
+func makeRestoreHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/trash/{kind}/{id}/restore").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.RestoreEndpoint, decodeRestoreRequest, encodeRestoreResponse, options...)))
+}
+
+// decodeRestoreRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeRestoreRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.RestoreRequest{}
+	vars := mux.Vars(r)
+	kind, ok := vars["kind"]
+	if !ok {
+		return nil, errors.New("not a valid kind")
+	}
+	req.Kind = kind
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	q := r.URL.Query()
+	if v := q.Get("subtree"); v != "" {
+		x, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("not a valid subtree")
+		}
+		req.Subtree = x
+	}
+	return req, nil
+}
+
+// encodeRestoreResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeRestoreResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makePurgeHandler creates the handler logic
+func makePurgeHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("DELETE").Path("/trash/{kind}/{id}").Handler(handlers.CORS(handlers.AllowedMethods([]string{"DELETE"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.PurgeEndpoint, decodePurgeRequest, encodePurgeResponse, options...)))
+}
+
+// decodePurgeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodePurgeRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.PurgeRequest{}
+	vars := mux.Vars(r)
+	kind, ok := vars["kind"]
+	if !ok {
+		return nil, errors.New("not a valid kind")
+	}
+	req.Kind = kind
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	q := r.URL.Query()
+	if v := q.Get("subtree"); v != "" {
+		x, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.New("not a valid subtree")
+		}
+		req.Subtree = x
+	}
+	return req, nil
+}
+
+// encodePurgeResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodePurgeResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetCategoryTreeHandler creates the handler logic
+func makeGetCategoryTreeHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/tree").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetCategoryTreeEndpoint, decodeGetCategoryTreeRequest, encodeGetCategoryTreeResponse, options...)))
+}
+
+// decodeGetCategoryTreeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetCategoryTreeRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetCategoryTreeRequest{}
+	return req, nil
+}
+
+// encodeGetCategoryTreeResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetCategoryTreeResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetCategoryPathHandler creates the handler logic
+func makeGetCategoryPathHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/path").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetCategoryPathEndpoint, decodeGetCategoryPathRequest, encodeGetCategoryPathResponse, options...)))
+}
+
+// decodeGetCategoryPathRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetCategoryPathRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetCategoryPathRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetCategoryPathResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetCategoryPathResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeReorderHandler creates the handler logic
+func makeReorderHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/todos/{id}/reorder").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.ReorderEndpoint, decodeReorderRequest, encodeReorderResponse, options...)))
+}
+
+// decodeReorderRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeReorderRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.ReorderRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeReorderResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeReorderResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeTransitionHandler creates the handler logic
+func makeTransitionHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/todos/{id}/transition").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.TransitionEndpoint, decodeTransitionRequest, encodeTransitionResponse, options...)))
+}
+
+// decodeTransitionRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeTransitionRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.TransitionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeTransitionResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeTransitionResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetWorkflowHandler creates the handler logic
+func makeGetWorkflowHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/workflow").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetWorkflowEndpoint, decodeGetWorkflowRequest, encodeGetWorkflowResponse, options...)))
+}
+
+// decodeGetWorkflowRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetWorkflowRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetWorkflowRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetWorkflowResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetWorkflowResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeSetWorkflowHandler creates the handler logic
+func makeSetWorkflowHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/categories/{id}/workflow").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.SetWorkflowEndpoint, decodeSetWorkflowRequest, encodeSetWorkflowResponse, options...)))
+}
+
+// decodeSetWorkflowRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeSetWorkflowRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.SetWorkflowRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeSetWorkflowResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeSetWorkflowResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetBoardHandler creates the handler logic
+func makeGetBoardHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/board").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetBoardEndpoint, decodeGetBoardRequest, encodeGetBoardResponse, options...)))
+}
+
+// decodeGetBoardRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetBoardRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetBoardRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetBoardResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetBoardResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeSetPriorityHandler creates the handler logic
+func makeSetPriorityHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/todos/{id}/priority").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.SetPriorityEndpoint, decodeSetPriorityRequest, encodeSetPriorityResponse, options...)))
+}
+
+// decodeSetPriorityRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeSetPriorityRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.SetPriorityRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeSetPriorityResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeSetPriorityResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetMatrixHandler creates the handler logic
+func makeGetMatrixHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/matrix").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetMatrixEndpoint, decodeGetMatrixRequest, encodeGetMatrixResponse, options...)))
+}
+
+// decodeGetMatrixRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetMatrixRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetMatrixRequest{}
+	q := r.URL.Query()
+	if v := q.Get("urgent_hours"); v != "" {
+		x, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("not a valid urgent_hours")
+		}
+		req.UrgentHours = x
+	}
+	return req, nil
+}
+
+// encodeGetMatrixResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetMatrixResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeAddDependencyHandler creates the handler logic
+func makeAddDependencyHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/todos/{id}/dependencies").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.AddDependencyEndpoint, decodeAddDependencyRequest, encodeAddDependencyResponse, options...)))
+}
+
+// decodeAddDependencyRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeAddDependencyRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.AddDependencyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeAddDependencyResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeAddDependencyResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeRemoveDependencyHandler creates the handler logic
+func makeRemoveDependencyHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("DELETE").Path("/todos/{id}/dependencies/{blocker_id}").Handler(handlers.CORS(handlers.AllowedMethods([]string{"DELETE"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.RemoveDependencyEndpoint, decodeRemoveDependencyRequest, encodeRemoveDependencyResponse, options...)))
+}
+
+// decodeRemoveDependencyRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeRemoveDependencyRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.RemoveDependencyRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	if v := vars["blocker_id"]; v != "" {
+		x, err := strconv.ParseUint(v, 10, 0)
+		if err != nil {
+			return nil, errors.New("not a valid blocker_id")
+		}
+		req.BlockerId = uint(x)
+	}
+	return req, nil
+}
+
+// encodeRemoveDependencyResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeRemoveDependencyResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetBlockedHandler creates the handler logic
+func makeGetBlockedHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/blocked").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetBlockedEndpoint, decodeGetBlockedRequest, encodeGetBlockedResponse, options...)))
+}
+
+// decodeGetBlockedRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetBlockedRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetBlockedRequest{}
+	return req, nil
+}
+
+// encodeGetBlockedResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetBlockedResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetReadyHandler creates the handler logic
+func makeGetReadyHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/ready").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetReadyEndpoint, decodeGetReadyRequest, encodeGetReadyResponse, options...)))
+}
+
+// decodeGetReadyRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetReadyRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetReadyRequest{}
+	return req, nil
+}
+
+// encodeGetReadyResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetReadyResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetDependencyGraphHandler creates the handler logic
+func makeGetDependencyGraphHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/dependencies/graph").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetDependencyGraphEndpoint, decodeGetDependencyGraphRequest, encodeGetDependencyGraphResponse, options...)))
+}
+
+// decodeGetDependencyGraphRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetDependencyGraphRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetDependencyGraphRequest{}
+	return req, nil
+}
+
+// encodeGetDependencyGraphResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetDependencyGraphResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetCategoryPlanHandler creates the handler logic
+func makeGetCategoryPlanHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/plan").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetCategoryPlanEndpoint, decodeGetCategoryPlanRequest, encodeGetCategoryPlanResponse, options...)))
+}
+
+// decodeGetCategoryPlanRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetCategoryPlanRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetCategoryPlanRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetCategoryPlanResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetCategoryPlanResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetTreePlanHandler creates the handler logic
+func makeGetTreePlanHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/{id}/plan").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetTreePlanEndpoint, decodeGetTreePlanRequest, encodeGetTreePlanResponse, options...)))
+}
+
+// decodeGetTreePlanRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetTreePlanRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetTreePlanRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetTreePlanResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetTreePlanResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeStartTimerHandler creates the handler logic
+func makeStartTimerHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/todos/{id}/timer").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.StartTimerEndpoint, decodeStartTimerRequest, encodeStartTimerResponse, options...)))
+}
+
+// decodeStartTimerRequest is a transport/http.DecodeRequestFunc that decodes a
+// request from the HTTP request path.
+func decodeStartTimerRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.StartTimerRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeStartTimerResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeStartTimerResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeStopTimerHandler creates the handler logic
+func makeStopTimerHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/timers/stop").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.StopTimerEndpoint, decodeStopTimerRequest, encodeStopTimerResponse, options...)))
+}
+
+// decodeStopTimerRequest is a transport/http.DecodeRequestFunc that decodes a
+// request without parameters.
+func decodeStopTimerRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.StopTimerRequest{}
+	return req, nil
+}
+
+// encodeStopTimerResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeStopTimerResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeAddTimeEntryHandler creates the handler logic
+func makeAddTimeEntryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/todos/{id}/time").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.AddTimeEntryEndpoint, decodeAddTimeEntryRequest, encodeAddTimeEntryResponse, options...)))
+}
+
+// decodeAddTimeEntryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeAddTimeEntryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.AddTimeEntryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeAddTimeEntryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeAddTimeEntryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetTimeEntriesHandler creates the handler logic
+func makeGetTimeEntriesHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/{id}/time").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetTimeEntriesEndpoint, decodeGetTimeEntriesRequest, encodeGetTimeEntriesResponse, options...)))
+}
+
+// decodeGetTimeEntriesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetTimeEntriesRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetTimeEntriesRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetTimeEntriesResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetTimeEntriesResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeUpdateTimeEntryHandler creates the handler logic
+func makeUpdateTimeEntryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("PUT").Path("/time/{id}").Handler(handlers.CORS(handlers.AllowedMethods([]string{"PUT"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.UpdateTimeEntryEndpoint, decodeUpdateTimeEntryRequest, encodeUpdateTimeEntryResponse, options...)))
+}
+
+// decodeUpdateTimeEntryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUpdateTimeEntryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.UpdateTimeEntryRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeUpdateTimeEntryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeUpdateTimeEntryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeDeleteTimeEntryHandler creates the handler logic
+func makeDeleteTimeEntryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("DELETE").Path("/time/{id}").Handler(handlers.CORS(handlers.AllowedMethods([]string{"DELETE"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.DeleteTimeEntryEndpoint, decodeDeleteTimeEntryRequest, encodeDeleteTimeEntryResponse, options...)))
+}
+
+// decodeDeleteTimeEntryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeDeleteTimeEntryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.DeleteTimeEntryRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeDeleteTimeEntryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeDeleteTimeEntryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetTimeReportHandler creates the handler logic
+func makeGetTimeReportHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/time/report").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetTimeReportEndpoint, decodeGetTimeReportRequest, encodeGetTimeReportResponse, options...)))
+}
+
+// decodeGetTimeReportRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetTimeReportRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetTimeReportRequest{}
+	q := r.URL.Query()
+	req.From = q.Get("from")
+	req.To = q.Get("to")
+	req.User = q.Get("user")
+	return req, nil
+}
+
+// encodeGetTimeReportResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetTimeReportResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetBurndownHandler creates the handler logic
+func makeGetBurndownHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/burndown").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetBurndownEndpoint, decodeGetBurndownRequest, encodeGetBurndownResponse, options...)))
+}
+
+// decodeGetBurndownRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetBurndownRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetBurndownRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	q := r.URL.Query()
+	req.From = q.Get("from")
+	req.To = q.Get("to")
+	req.Unit = q.Get("unit")
+	return req, nil
+}
+
+// encodeGetBurndownResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetBurndownResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetTodoHistoryHandler creates the handler logic
+func makeGetTodoHistoryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/todos/{id}/history").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetTodoHistoryEndpoint, decodeGetTodoHistoryRequest, encodeGetTodoHistoryResponse, options...)))
+}
+
+// decodeGetTodoHistoryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetTodoHistoryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetTodoHistoryRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetTodoHistoryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetTodoHistoryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeGetCategoryHistoryHandler creates the handler logic
+func makeGetCategoryHistoryHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("GET").Path("/categories/{id}/history").Handler(handlers.CORS(handlers.AllowedMethods([]string{"GET"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.GetCategoryHistoryEndpoint, decodeGetCategoryHistoryRequest, encodeGetCategoryHistoryResponse, options...)))
+}
+
+// decodeGetCategoryHistoryRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeGetCategoryHistoryRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.GetCategoryHistoryRequest{}
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, errors.New("not a valid ID")
+	}
+	req.Id = id
+	return req, nil
+}
+
+// encodeGetCategoryHistoryResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeGetCategoryHistoryResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeUndoHandler creates the handler logic
+func makeUndoHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/undo").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.UndoEndpoint, decodeUndoRequest, encodeUndoResponse, options...)))
+}
+
+// decodeUndoRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeUndoRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.UndoRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeUndoResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeUndoResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeRedoHandler creates the handler logic
+func makeRedoHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/redo").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.RedoEndpoint, decodeRedoRequest, encodeRedoResponse, options...)))
+}
+
+// decodeRedoRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeRedoRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.RedoRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeRedoResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeRedoResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeFindTodosHandler creates the handler logic
+func makeFindTodosHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/todos/find").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.FindTodosEndpoint, decodeFindTodosRequest, encodeFindTodosResponse, options...)))
+}
+
+// decodeFindTodosRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeFindTodosRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.FindTodosRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeFindTodosResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeFindTodosResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
+
+// makeFindCategoriesHandler creates the handler logic
+func makeFindCategoriesHandler(m *mux.Router, endpoints endpoint.Endpoints, options []http.ServerOption) {
+	m.Methods("POST").Path("/categories/find").Handler(handlers.CORS(handlers.AllowedMethods([]string{"POST"}), handlers.AllowedOrigins([]string{"*"}))(http.NewServer(endpoints.FindCategoriesEndpoint, decodeFindCategoriesRequest, encodeFindCategoriesResponse, options...)))
+}
+
+// decodeFindCategoriesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeFindCategoriesRequest(_ context.Context, r *http1.Request) (interface{}, error) {
+	req := endpoint.FindCategoriesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// encodeFindCategoriesResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer
+func encodeFindCategoriesResponse(ctx context.Context, w http1.ResponseWriter, response interface{}) (err error) {
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		ErrorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err = json.NewEncoder(w).Encode(response)
+	return
+}
@@ -26,5 +26,47 @@ func NewHTTPHandler(endpoints endpoint.Endpoints, options map[string][]http.Serv
 	makeUpdateCategoryHandler(m, endpoints, options["UpdateCategory"])
 	makeDeleteCategoryHandler(m, endpoints, options["DeleteCategory"])
 	makeGetCatChildesHandler(m, endpoints, options["GetCatChildes"])
+	makeAddWebhookHandler(m, endpoints, options["AddWebhook"])
+	makeGetWebhooksHandler(m, endpoints, options["GetWebhooks"])
+	makeDeleteWebhookHandler(m, endpoints, options["DeleteWebhook"])
+	makeGetWebhookDeliveriesHandler(m, endpoints, options["GetWebhookDeliveries"])
+	makeGetDeadLettersHandler(m, endpoints, options["GetDeadLetters"])
+	makeRetryDeliveryHandler(m, endpoints, options["RetryDelivery"])
+	makeGetTreeHandler(m, endpoints, options["GetTree"])
+	makeMoveHandler(m, endpoints, options["Move"])
+	makeMoveCategoryHandler(m, endpoints, options["MoveCategory"])
+	makeListTrashHandler(m, endpoints, options["ListTrash"])
+	makeRestoreHandler(m, endpoints, options["Restore"])
+	makePurgeHandler(m, endpoints, options["Purge"])
+	makeGetCategoryTreeHandler(m, endpoints, options["GetCategoryTree"])
+	makeGetCategoryPathHandler(m, endpoints, options["GetCategoryPath"])
+	makeReorderHandler(m, endpoints, options["Reorder"])
+	makeTransitionHandler(m, endpoints, options["Transition"])
+	makeGetWorkflowHandler(m, endpoints, options["GetWorkflow"])
+	makeSetWorkflowHandler(m, endpoints, options["SetWorkflow"])
+	makeGetBoardHandler(m, endpoints, options["GetBoard"])
+	makeSetPriorityHandler(m, endpoints, options["SetPriority"])
+	makeGetMatrixHandler(m, endpoints, options["GetMatrix"])
+	makeAddDependencyHandler(m, endpoints, options["AddDependency"])
+	makeRemoveDependencyHandler(m, endpoints, options["RemoveDependency"])
+	makeGetBlockedHandler(m, endpoints, options["GetBlocked"])
+	makeGetReadyHandler(m, endpoints, options["GetReady"])
+	makeGetDependencyGraphHandler(m, endpoints, options["GetDependencyGraph"])
+	makeGetCategoryPlanHandler(m, endpoints, options["GetCategoryPlan"])
+	makeGetTreePlanHandler(m, endpoints, options["GetTreePlan"])
+	makeStartTimerHandler(m, endpoints, options["StartTimer"])
+	makeStopTimerHandler(m, endpoints, options["StopTimer"])
+	makeAddTimeEntryHandler(m, endpoints, options["AddTimeEntry"])
+	makeGetTimeEntriesHandler(m, endpoints, options["GetTimeEntries"])
+	makeUpdateTimeEntryHandler(m, endpoints, options["UpdateTimeEntry"])
+	makeDeleteTimeEntryHandler(m, endpoints, options["DeleteTimeEntry"])
+	makeGetTimeReportHandler(m, endpoints, options["GetTimeReport"])
+	makeGetBurndownHandler(m, endpoints, options["GetBurndown"])
+	makeGetTodoHistoryHandler(m, endpoints, options["GetTodoHistory"])
+	makeGetCategoryHistoryHandler(m, endpoints, options["GetCategoryHistory"])
+	makeUndoHandler(m, endpoints, options["Undo"])
+	makeRedoHandler(m, endpoints, options["Redo"])
+	makeFindTodosHandler(m, endpoints, options["FindTodos"])
+	makeFindCategoriesHandler(m, endpoints, options["FindCategories"])
 	return m
 }
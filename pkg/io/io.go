@@ -1,8 +1,12 @@
 package io
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+	"time"
 )
 
 type Todo struct {
@@ -10,8 +14,24 @@ type Todo struct {
 	Description string `json:"description"`
 	CategoryID  uint   `json:"category_id"`
 	Star        uint8  `json:"star"`
-	Complete    bool   `json:"complete"`
-	ParentID    uint   `json:"parent_id"`
+	// Priority goes from 1, the highest, to 4, 0 meaning none.
+	Priority uint8      `json:"priority"`
+	Due      *time.Time `json:"due,omitempty"`
+	// Estimate is the work left on the todo, in hours.
+	Estimate float64 `json:"estimate"`
+	// Points is the size of the todo, in story points.
+	Points   float64 `json:"points"`
+	Complete bool    `json:"complete"`
+	// Status is the todo's step in the workflow of its category. Complete
+	// is set in the last one. Transition changes it.
+	Status   string `json:"status" gorm:"index"`
+	ParentID uint   `json:"parent_id"`
+	// Position orders the todo among the ones sharing its parent and
+	// category. A todo joining a list goes last, Reorder moves it within.
+	Position float64 `json:"position"`
+	// Progress is the percentage of the subtasks that are complete, or of
+	// the todo itself without any. It is computed, never stored.
+	Progress int `json:"progress" gorm:"-"`
 	gorm.Model
 }
 
@@ -28,3 +48,478 @@ func (t Todo) String() string {
 	}
 	return string(b)
 }
+
+// Delete policies, deciding what happens to the children of a deleted todo
+// or category: its subtasks, sub categories and the todos it holds.
+const (
+	// DeleteRefuse fails the delete when there are children. It is the
+	// default.
+	DeleteRefuse = "refuse"
+	// DeleteCascade deletes the children and everything below them.
+	DeleteCascade = "cascade"
+	// DeleteReparent hands the children over to the parent of the deleted
+	// item.
+	DeleteReparent = "reparent"
+)
+
+// Event types published for every write on TodoService.
+const (
+	TodoCreated      = "todo.created"
+	TodoUpdated      = "todo.updated"
+	TodoCompleted    = "todo.completed"
+	TodoReopened     = "todo.reopened"
+	TodoStarred      = "todo.starred"
+	TodoPrioritized  = "todo.prioritized"
+	TodoDeleted      = "todo.deleted"
+	TodoMoved        = "todo.moved"
+	TodoRestored     = "todo.restored"
+	TodoTransitioned = "todo.transitioned"
+	TodoBlocked      = "todo.blocked"
+	TodoUnblocked    = "todo.unblocked"
+	CategoryCreated  = "category.created"
+	CategoryUpdated  = "category.updated"
+	CategoryDeleted  = "category.deleted"
+	CategoryMoved    = "category.moved"
+	CategoryRestored = "category.restored"
+)
+
+// EventTypes lists every event type, in the order above.
+var EventTypes = []string{
+	TodoCreated, TodoUpdated, TodoCompleted, TodoReopened, TodoStarred, TodoPrioritized, TodoDeleted, TodoMoved, TodoRestored, TodoTransitioned,
+	TodoBlocked, TodoUnblocked,
+	CategoryCreated, CategoryUpdated, CategoryDeleted, CategoryMoved, CategoryRestored,
+}
+
+// Event describes a change to a todo or a category. CategoryID is the
+// category the change is visible in: the todo's category, or the id of
+// the category itself.
+type Event struct {
+	ID         uint64        `json:"id"`
+	Type       string        `json:"type"`
+	Time       time.Time     `json:"time"`
+	CategoryID uint          `json:"category_id"`
+	Todo       *Todo         `json:"todo,omitempty"`
+	Category   *TodoCategory `json:"category,omitempty"`
+}
+
+// Webhook subscribes URL to change events. Every delivery is a POST of the
+// event as JSON, signed with Secret. No Events means every event type.
+type Webhook struct {
+	URL    string         `json:"url"`
+	Secret string         `json:"secret,omitempty"`
+	Events pq.StringArray `json:"events" gorm:"type:text[]"`
+	gorm.Model
+}
+
+// Wants reports whether the webhook subscribes to events of type typ.
+func (w Webhook) Wants(typ string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, v := range w.Events {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery statuses of a WebhookDelivery.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookDelivery is one event to be sent to a webhook, and the outcome of
+// the last attempt. Pending deliveries are retried at NextAttempt until
+// they succeed or run out of attempts and become dead letters.
+type WebhookDelivery struct {
+	WebhookID   uint      `json:"webhook_id" gorm:"unique_index:idx_delivery_event"`
+	EventID     uint64    `json:"event_id" gorm:"unique_index:idx_delivery_event"`
+	EventType   string    `json:"event_type"`
+	Payload     string    `json:"payload"`
+	Status      string    `json:"status"`
+	Attempts    int       `json:"attempts"`
+	StatusCode  int       `json:"status_code"`
+	LastError   string    `json:"last_error"`
+	NextAttempt time.Time `json:"next_attempt"`
+	gorm.Model
+}
+
+// OutboxEvent is an Event waiting to be published, written in the same
+// transaction as the change it describes. Its ID becomes the event ID.
+type OutboxEvent struct {
+	ID          uint64 `gorm:"primary_key"`
+	Type        string
+	Payload     string `gorm:"type:text"`
+	CreatedAt   time.Time
+	PublishedAt *time.Time `gorm:"index"`
+}
+
+// CategoryNode is a category with its sub categories. Path names the
+// category after its ancestors, "Work / Backend / API", and Open and
+// Completed count the todos filed directly under it.
+type CategoryNode struct {
+	TodoCategory
+	Path      string         `json:"path"`
+	Open      int            `json:"open"`
+	Completed int            `json:"completed"`
+	Children  []CategoryNode `json:"children,omitempty"`
+}
+
+// Workflow lists the statuses the todos of a category go through, the
+// first one for new or reopened todos and the last one for completed
+// todos. Transitions maps a status to the ones a todo may move to from
+// it; without any, every move is allowed.
+type Workflow struct {
+	CategoryID  uint           `json:"category_id" gorm:"unique_index"`
+	Statuses    pq.StringArray `json:"statuses" gorm:"type:text[]"`
+	Transitions Transitions    `json:"transitions,omitempty" gorm:"type:text"`
+	gorm.Model
+}
+
+// Initial returns the status of new todos.
+func (w Workflow) Initial() string {
+	return w.Statuses[0]
+}
+
+// Terminal returns the status of completed todos.
+func (w Workflow) Terminal() string {
+	return w.Statuses[len(w.Statuses)-1]
+}
+
+// Has reports whether status is one of the workflow's.
+func (w Workflow) Has(status string) bool {
+	for _, v := range w.Statuses {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether a todo may move from status from to status to.
+func (w Workflow) Allows(from, to string) bool {
+	if !w.Has(to) {
+		return false
+	}
+	if len(w.Transitions) == 0 || from == to {
+		return true
+	}
+	for _, v := range w.Transitions[from] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusOf returns the status of todo in the workflow, falling back on
+// the initial or terminal status, after Complete, for todos from before
+// workflows or from another one.
+func (w Workflow) StatusOf(todo Todo) string {
+	if w.Has(todo.Status) {
+		return todo.Status
+	}
+	if todo.Complete {
+		return w.Terminal()
+	}
+	return w.Initial()
+}
+
+// Transitions is stored as a JSON object.
+type Transitions map[string][]string
+
+// Value implements driver.Valuer.
+func (t Transitions) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(t)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner.
+func (t *Transitions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	case []byte:
+		return json.Unmarshal(v, t)
+	}
+	return errors.New("unsupported transitions value")
+}
+
+// BoardColumn holds the todos in one status of a workflow.
+type BoardColumn struct {
+	Status string `json:"status"`
+	Todos  []Todo `json:"todos"`
+}
+
+// Board is the kanban view of a category: a column per status of its
+// workflow, in order.
+type Board struct {
+	Workflow Workflow      `json:"workflow"`
+	Columns  []BoardColumn `json:"columns"`
+}
+
+// Matrix sorts the open todos into the quadrants of the Eisenhower matrix.
+// Important todos have priority 1 or 2, urgent ones are due soon or
+// overdue.
+type Matrix struct {
+	DoFirst   []Todo `json:"do_first"`  // urgent and important
+	Schedule  []Todo `json:"schedule"`  // important, not urgent
+	Delegate  []Todo `json:"delegate"`  // urgent, not important
+	Eliminate []Todo `json:"eliminate"` // neither
+}
+
+// Dependency records that the todo TodoID can not be completed before the
+// todo BlockerID is.
+type Dependency struct {
+	ID        uint      `json:"id" gorm:"primary_key"`
+	TodoID    uint      `json:"todo_id" gorm:"unique_index:idx_dependency"`
+	BlockerID uint      `json:"blocker_id" gorm:"unique_index:idx_dependency;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DependencyGraph holds the todos with dependencies, as nodes, and the
+// dependencies between them, as edges from blocker to blocked todo.
+type DependencyGraph struct {
+	Nodes []Todo       `json:"nodes"`
+	Edges []Dependency `json:"edges"`
+}
+
+// PlannedTodo is a todo scheduled by the critical path method. Times are
+// hours from the start of the plan, with every todo worked on as soon as
+// its blockers are complete.
+type PlannedTodo struct {
+	Todo
+	EarliestStart  float64 `json:"earliest_start"`
+	EarliestFinish float64 `json:"earliest_finish"`
+	LatestStart    float64 `json:"latest_start"`
+	LatestFinish   float64 `json:"latest_finish"`
+	// Slack is how long the todo can slip without delaying the plan.
+	Slack    float64 `json:"slack"`
+	Critical bool    `json:"critical"`
+	// Infeasible is set when the todo is due before its earliest finish.
+	Infeasible bool `json:"infeasible"`
+	// BlockedBy lists the open todos outside the plan the todo waits for.
+	// It starts once they could be done, their estimate after the start.
+	BlockedBy []uint `json:"blocked_by,omitempty"`
+}
+
+// Plan schedules the open todos of a category or a subtree. Duration is
+// the length of the critical path, in hours, which lists the ids of its
+// todos in order.
+type Plan struct {
+	Start        time.Time     `json:"start"`
+	Duration     float64       `json:"duration"`
+	Todos        []PlannedTodo `json:"todos"`
+	CriticalPath []uint        `json:"critical_path"`
+}
+
+// TimeEntry is time User spent on the todo TodoID. A running timer is an
+// entry without EndedAt; a user has at most one.
+type TimeEntry struct {
+	ID        uint       `json:"id" gorm:"primary_key"`
+	TodoID    uint       `json:"todo_id" gorm:"index"`
+	User      string     `json:"user" gorm:"column:user_name;index"`
+	Note      string     `json:"note"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	// Hours is the length of the entry, up to now for a running timer.
+	// Only set in responses.
+	Hours     float64   `json:"hours" gorm:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Duration returns the length of the entry, up to now when it is running.
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt != nil {
+		now = *e.EndedAt
+	}
+	return now.Sub(e.StartedAt)
+}
+
+// TodoTime is the time logged on a todo.
+type TodoTime struct {
+	TodoID uint    `json:"todo_id"`
+	Title  string  `json:"title"`
+	Hours  float64 `json:"hours"`
+}
+
+// CategoryTime is the time logged on the todos of a category and of its
+// sub categories. Category 0 holds the todos without one.
+type CategoryTime struct {
+	CategoryID uint    `json:"category_id"`
+	Path       string  `json:"path"`
+	Hours      float64 `json:"hours"`
+}
+
+// TimeReport sums the time logged between From and To, unbounded when
+// unset, by User or by everyone. Entries crossing a bound only count
+// inside the range.
+type TimeReport struct {
+	From       *time.Time     `json:"from,omitempty"`
+	To         *time.Time     `json:"to,omitempty"`
+	User       string         `json:"user,omitempty"`
+	Hours      float64        `json:"hours"`
+	Todos      []TodoTime     `json:"todos"`
+	Categories []CategoryTime `json:"categories"`
+}
+
+// Completion records that the todo TodoID was completed, or reopened, at
+// At. Burndowns are computed from it.
+type Completion struct {
+	ID       uint      `json:"id" gorm:"primary_key"`
+	TodoID   uint      `json:"todo_id" gorm:"index"`
+	Complete bool      `json:"complete"`
+	At       time.Time `json:"at"`
+}
+
+// Units a Burndown is measured in.
+const (
+	UnitPoints = "points"
+	UnitHours  = "hours"
+	UnitTodos  = "todos"
+)
+
+// BurnPoint is the state of a category at Time: the size of the todos it
+// held, how much of it was complete and how much was left.
+type BurnPoint struct {
+	Time      time.Time `json:"time"`
+	Scope     float64   `json:"scope"`
+	Completed float64   `json:"completed"`
+	Remaining float64   `json:"remaining"`
+	// Ideal falls evenly from the first Remaining to none at the end.
+	Ideal float64 `json:"ideal"`
+}
+
+// Burndown holds the burndown, Remaining, and burnup, Completed, series
+// of a category and its sub categories, a point per day.
+type Burndown struct {
+	CategoryID uint        `json:"category_id"`
+	Unit       string      `json:"unit"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Series     []BurnPoint `json:"series"`
+}
+
+// Kinds of items in the trash, and in the history.
+const (
+	TrashTodo     = "todo"
+	TrashCategory = "category"
+)
+
+// Kinds of the other items in the history.
+const (
+	HistoryDependency = "dependency"
+	HistoryTimeEntry  = "time_entry"
+	HistoryWorkflow   = "workflow"
+)
+
+// Operations of the changes to items without events of their own.
+const (
+	TimeEntryCreated = "time_entry.created"
+	TimeEntryUpdated = "time_entry.updated"
+	TimeEntryDeleted = "time_entry.deleted"
+	WorkflowSet      = "workflow.set"
+	WorkflowDropped  = "workflow.dropped"
+	TodoPurged       = "todo.purged"
+	CategoryPurged   = "category.purged"
+)
+
+// Change is an entry of the append-only history of the item ItemID, Kind
+// telling whether it is a todo, a category, a dependency, a time entry or
+// a workflow: its state Before and After Operation, an event type, made
+// by Actor while serving the request RequestID. Before
+// is null for a new item, After for a deleted one. Undo or Redo is the
+// request id of the operation the change undoes or redoes, if any.
+type Change struct {
+	ID        uint          `json:"id" gorm:"primary_key"`
+	Kind      string        `json:"kind" gorm:"index:idx_change_item"`
+	ItemID    uint          `json:"item_id" gorm:"index:idx_change_item"`
+	Operation string        `json:"operation"`
+	Actor     string        `json:"actor" gorm:"index"`
+	RequestID string        `json:"request_id" gorm:"index"`
+	Undo      string        `json:"undo,omitempty"`
+	Redo      string        `json:"redo,omitempty"`
+	Before    Snapshot      `json:"before" gorm:"type:text"`
+	After     Snapshot      `json:"after" gorm:"type:text"`
+	Diff      []FieldChange `json:"diff" gorm:"-"`
+	CreatedAt time.Time     `json:"created_at" gorm:"index"`
+}
+
+// FieldChange is a field whose value differs from Before to After.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Snapshot is the JSON state of a todo or a category, stored as text.
+type Snapshot json.RawMessage
+
+// MarshalJSON implements json.Marshaler.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Snapshot) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*s = nil
+		return nil
+	}
+	*s = append((*s)[:0], b...)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s Snapshot) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return string(s), nil
+}
+
+// Scan implements sql.Scanner.
+func (s *Snapshot) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		*s = Snapshot(v)
+		return nil
+	case []byte:
+		*s = append(Snapshot(nil), v...)
+		return nil
+	}
+	return errors.New("unsupported snapshot value")
+}
+
+// Trash holds the soft deleted todos and categories, the most recently
+// deleted first.
+type Trash struct {
+	Todos      []Todo         `json:"todos"`
+	Categories []TodoCategory `json:"categories"`
+}
+
+// TodoNode is a todo with its subtasks. Total and Completed count all of
+// its descendants, also the ones below a depth limit that left Children
+// out.
+type TodoNode struct {
+	Todo
+	Children  []TodoNode `json:"children,omitempty"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+}
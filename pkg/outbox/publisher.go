@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+	events "todo/pkg/events"
+	io "todo/pkg/io"
+
+	nats "github.com/nats-io/nats.go"
+)
+
+// Publishers sends every event to each of its publishers in turn. A
+// failure is retried on all of them, so the ones before the failing
+// publisher see the event again.
+type Publishers []EventPublisher
+
+// Publish implements EventPublisher.
+func (p Publishers) Publish(event io.Event) error {
+	for _, v := range p {
+		if err := v.Publish(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BrokerPublisher publishes to the in-process broker feeding the /events
+// and /ws subscribers. The broker never fails, but its subscribers may
+// fall behind: it is for live updates, and webhooks get their events from
+// their own publisher.
+type BrokerPublisher struct {
+	Broker *events.Broker
+}
+
+// Publish implements EventPublisher.
+func (p BrokerPublisher) Publish(event io.Event) error {
+	p.Broker.Publish(event)
+	return nil
+}
+
+// FilePublisher appends events to a file, one JSON object per line.
+type FilePublisher struct {
+	mtx  sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens path for appending, creating it if needed.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FilePublisher{file: f}, nil
+}
+
+// Publish implements EventPublisher. The line is synced to disk before
+// Publish returns.
+func (p *FilePublisher) Publish(event io.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if _, err := p.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+// Close closes the file.
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// NATSPublisher publishes events to a NATS server, on the subject
+// <prefix>.<event type>, e.g. todo.todo.created.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSPublisher connects to the NATS server at url.
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("todo outbox"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATSPublisher{conn: conn, prefix: prefix}, nil
+}
+
+// Publish implements EventPublisher. It waits for the server to have
+// received the event.
+func (p *NATSPublisher) Publish(event io.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := p.conn.Publish(p.prefix+"."+event.Type, b); err != nil {
+		return err
+	}
+	return p.conn.FlushTimeout(5 * time.Second)
+}
+
+// Close drains and closes the connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+	"todo/pkg/db"
+	io "todo/pkg/io"
+
+	log "github.com/go-kit/kit/log"
+	"github.com/jinzhu/gorm"
+)
+
+const batchSize = 100
+
+// EventPublisher is where the relay sends the events of the outbox. An
+// event is marked published only once Publish returns nil, so publishers
+// must tolerate seeing an event again after a crash or an error.
+type EventPublisher interface {
+	Publish(event io.Event) error
+}
+
+// Relay publishes outbox events in id order, giving at-least-once delivery:
+// an event that fails to publish stops the batch and is retried, with the
+// ones after it, on the next pass.
+type Relay struct {
+	publisher EventPublisher
+	logger    log.Logger
+	interval  time.Duration
+	retention time.Duration
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewRelay returns a Relay checking the outbox every interval. Published
+// events are deleted once older than retention.
+func NewRelay(publisher EventPublisher, logger log.Logger, interval, retention time.Duration) *Relay {
+	return &Relay{
+		publisher: publisher,
+		logger:    logger,
+		interval:  interval,
+		retention: retention,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Run relays events until Stop is called.
+func (r *Relay) Run() error {
+	session := db.ConnectPGDB()
+	defer session.Close()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	var lastPurge time.Time
+	for {
+		select {
+		case <-r.stop:
+			return nil
+		case <-ticker.C:
+		}
+		for {
+			n, err := r.relay(session)
+			if err != nil {
+				r.logger.Log("during", "relay", "err", err)
+			}
+			if err != nil || n < batchSize {
+				break
+			}
+		}
+		if time.Since(lastPurge) > time.Hour {
+			err := session.Where("published_at < ?", time.Now().Add(-r.retention)).Delete(io.OutboxEvent{}).Error
+			if err != nil {
+				r.logger.Log("during", "purge", "err", err)
+			}
+			lastPurge = time.Now()
+		}
+	}
+}
+
+// Stop makes Run return after the current pass.
+func (r *Relay) Stop() {
+	r.once.Do(func() { close(r.stop) })
+}
+
+// relay publishes one batch of pending events and returns how many were
+// read.
+func (r *Relay) relay(session *gorm.DB) (int, error) {
+	var pending []io.OutboxEvent
+	err := session.Where("published_at IS NULL").Order("id").Limit(batchSize).Find(&pending).Error
+	if err != nil {
+		return 0, err
+	}
+	for _, o := range pending {
+		var event io.Event
+		if err := json.Unmarshal([]byte(o.Payload), &event); err != nil {
+			return 0, err
+		}
+		event.ID = o.ID
+		if err := r.publisher.Publish(event); err != nil {
+			return 0, err
+		}
+		if err := session.Model(&o).Update("published_at", time.Now()).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
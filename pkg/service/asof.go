@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// WithAsOf returns a copy of ctx asking the listing and tree methods for
+// the todos and categories as they were at t, rebuilt from the history.
+func WithAsOf(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, asOfKey, t)
+}
+
+func asOf(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(asOfKey).(time.Time)
+	return t, ok
+}
+
+// withoutAsOf returns a copy of ctx asking for the current state.
+func withoutAsOf(ctx context.Context) context.Context {
+	return context.WithValue(ctx, asOfKey, nil)
+}
+
+// ErrBeforeHistory is returned when asking for the state of items at a
+// time before their history starts.
+var ErrBeforeHistory = errors.New("the history does not go back that far")
+
+// latestChanges matches the last change to each item of a kind made by a
+// time.
+const latestChanges = "id IN (SELECT max(id) FROM changes WHERE kind = ? AND created_at <= ? GROUP BY item_id)"
+
+// statesAsOf returns the state of every item of kind that existed at t.
+// The history of the items from before it starts with a snapshot of them
+// taken later: their state at t is unknown, and ErrBeforeHistory returned,
+// if they already existed then.
+func statesAsOf(session *gorm.DB, kind string, at time.Time) ([]io.Snapshot, error) {
+	var changes, snapshots []io.Change
+	if err := session.Where(latestChanges, kind, at).Order("item_id").Find(&changes).Error; err != nil {
+		return nil, err
+	}
+	err := session.Where("kind = ? AND operation = ? AND created_at > ?", kind, snapshotOf(kind), at).Order("item_id").Find(&snapshots).Error
+	if err != nil {
+		return nil, err
+	}
+	states := []io.Snapshot{}
+	for _, v := range changes {
+		if v.After != nil {
+			states = append(states, v.After)
+		}
+	}
+	for _, v := range snapshots {
+		var m gorm.Model
+		if err := json.Unmarshal(v.After, &m); err != nil {
+			return nil, err
+		}
+		if !m.CreatedAt.After(at) && (m.DeletedAt == nil || m.DeletedAt.After(at)) {
+			return nil, fmt.Errorf("%w: the history of %s %d starts at %s", ErrBeforeHistory, kind, v.ItemID, v.CreatedAt.Format(time.RFC3339))
+		}
+	}
+	return states, nil
+}
+
+// todosAsOf returns the todos as they were at t, by position.
+func todosAsOf(session *gorm.DB, at time.Time) ([]io.Todo, error) {
+	states, err := statesAsOf(session, io.TrashTodo, at)
+	if err != nil {
+		return nil, err
+	}
+	t := make([]io.Todo, len(states))
+	for i, v := range states {
+		if err := json.Unmarshal(v, &t[i]); err != nil {
+			return nil, err
+		}
+	}
+	byPosition(t)
+	return t, nil
+}
+
+// categoriesAsOf returns the categories as they were at t, by name.
+func categoriesAsOf(session *gorm.DB, at time.Time) ([]io.TodoCategory, error) {
+	states, err := statesAsOf(session, io.TrashCategory, at)
+	if err != nil {
+		return nil, err
+	}
+	c := make([]io.TodoCategory, len(states))
+	for i, v := range states {
+		if err := json.Unmarshal(v, &c[i]); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(c, func(i, j int) bool { return c[i].Name < c[j].Name })
+	return c, nil
+}
+
+// findTodo returns the todo id among todos, or gorm.ErrRecordNotFound.
+func findTodo(todos []io.Todo, id string) (io.Todo, error) {
+	for _, v := range todos {
+		if strconv.FormatUint(uint64(v.ID), 10) == id {
+			return v, nil
+		}
+	}
+	return io.Todo{}, gorm.ErrRecordNotFound
+}
+
+// childrenOf returns the todos whose parent is id.
+func childrenOf(todos []io.Todo, id string) []io.Todo {
+	t := []io.Todo{}
+	for _, v := range todos {
+		if strconv.FormatUint(uint64(v.ParentID), 10) == id {
+			t = append(t, v)
+		}
+	}
+	return t
+}
+
+// subCategoriesOf returns the categories whose parent is id.
+func subCategoriesOf(categories []io.TodoCategory, id string) []io.TodoCategory {
+	c := []io.TodoCategory{}
+	for _, v := range categories {
+		if strconv.FormatUint(uint64(v.ParentID), 10) == id {
+			c = append(c, v)
+		}
+	}
+	return c
+}
+
+// countTodos returns the number of open and completed todos per category,
+// like todoCounts does.
+func countTodos(todos []io.Todo) map[uint][2]int {
+	counts := map[uint][2]int{}
+	for _, v := range todos {
+		c := counts[v.CategoryID]
+		if v.Complete {
+			c[1]++
+		} else {
+			c[0]++
+		}
+		counts[v.CategoryID] = c
+	}
+	return counts
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// defaultBurndownDays is how far back GetBurndown goes without from, and
+// maxBurndownDays the most days it returns.
+const (
+	defaultBurndownDays = 14
+	maxBurndownDays     = 400
+)
+
+// burndownQuery sums, for each of the days, the size of the todos of the
+// categories that existed that day, and of those that were complete then
+// according to their completion history. Days without any todo have no
+// row.
+const burndownQuery = `SELECT d.day, SUM(%[1]s) AS scope,
+	COALESCE(SUM(%[1]s) FILTER (WHERE (SELECT c.complete FROM completions c
+		WHERE c.todo_id = t.id AND c.at <= d.day ORDER BY c.at DESC, c.id DESC LIMIT 1)), 0) AS completed
+FROM unnest(?::timestamptz[]) AS d(day)
+JOIN todos t ON t.category_id IN (?) AND t.created_at <= d.day AND (t.deleted_at IS NULL OR t.deleted_at > d.day)
+GROUP BY d.day`
+
+// GetBurndown returns the burndown and burnup series of the category id
+// and its sub categories, in unit, points by default, with a point per day
+// from from to to, the last two weeks by default and 400 days at most.
+// Todos count in their current category, from their creation to their
+// deletion if any.
+func (b *basicTodoService) GetBurndown(ctx context.Context, id string, from string, to string, unit string) (bd io.Burndown, error error) {
+	if unit == "" {
+		unit = io.UnitPoints
+	}
+	size, err := sizeIn(unit)
+	if err != nil {
+		return bd, err
+	}
+	end, err := parseBound(to)
+	if err != nil {
+		return bd, fmt.Errorf("to: %v", err)
+	}
+	if end == nil {
+		now := time.Now()
+		end = &now
+	}
+	start, err := parseBound(from)
+	if err != nil {
+		return bd, fmt.Errorf("from: %v", err)
+	}
+	if start == nil {
+		s := end.AddDate(0, 0, -defaultBurndownDays)
+		start = &s
+	}
+	if end.Before(*start) {
+		return bd, errors.New("to must not be before from")
+	}
+	days := []time.Time{}
+	for t := *start; !t.After(*end); t = t.AddDate(0, 0, 1) {
+		if len(days) == maxBurndownDays {
+			return bd, fmt.Errorf("the range spans more than %d days", maxBurndownDays)
+		}
+		// Postgres keeps microseconds.
+		days = append(days, t.Truncate(time.Microsecond))
+	}
+	session := connect(ctx)
+	defer session.Close()
+	category, err := categoryID(session, id)
+	if err != nil {
+		return bd, err
+	}
+	ids := []uint{category}
+	if category != 0 {
+		below, err := categoryDescendants(session, category)
+		if err != nil {
+			return bd, err
+		}
+		for _, v := range below {
+			ids = append(ids, v.ID)
+		}
+	}
+	stamps := []string{}
+	for _, v := range days {
+		stamps = append(stamps, v.Format(time.RFC3339Nano))
+	}
+	var rows []struct {
+		Day       time.Time
+		Scope     float64
+		Completed float64
+	}
+	err = session.Raw(fmt.Sprintf(burndownQuery, size), pq.Array(stamps), ids).Scan(&rows).Error
+	if err != nil {
+		return bd, err
+	}
+	byDay := map[int64]io.BurnPoint{}
+	for _, v := range rows {
+		byDay[v.Day.UnixNano()] = io.BurnPoint{Scope: v.Scope, Completed: v.Completed}
+	}
+	bd = io.Burndown{CategoryID: category, Unit: unit, From: *start, To: *end, Series: []io.BurnPoint{}}
+	for _, t := range days {
+		p := byDay[t.UnixNano()]
+		p.Time = t
+		p.Remaining = p.Scope - p.Completed
+		bd.Series = append(bd.Series, p)
+	}
+	if n := len(bd.Series); n > 1 {
+		for i := range bd.Series {
+			bd.Series[i].Ideal = bd.Series[0].Remaining * float64(n-1-i) / float64(n-1)
+		}
+	}
+	return bd, nil
+}
+
+// sizeIn returns the SQL expression of how big a todo t is in unit.
+func sizeIn(unit string) (string, error) {
+	switch unit {
+	case io.UnitPoints:
+		return "t.points", nil
+	case io.UnitHours:
+		return "t.estimate", nil
+	case io.UnitTodos:
+		return "1", nil
+	}
+	return "", fmt.Errorf("unknown unit %q, want points, hours or todos", unit)
+}
+
+// BackfillCompletions starts the completion history of the todos completed
+// before it: they count as completed when last updated.
+func BackfillCompletions(session *gorm.DB) error {
+	return session.Exec("INSERT INTO completions (todo_id, complete, at) SELECT id, true, updated_at FROM todos WHERE complete AND id NOT IN (SELECT todo_id FROM completions)").Error
+}
+
+// logCompletion appends to the completion history of todo when it was
+// just completed or reopened, or created complete.
+func logCompletion(tx *gorm.DB, todo io.Todo) error {
+	last := io.Completion{}
+	err := tx.Where("todo_id = ?", todo.ID).Order("id desc").First(&last).Error
+	switch {
+	case gorm.IsRecordNotFoundError(err):
+		if !todo.Complete {
+			return nil
+		}
+	case err != nil:
+		return err
+	case last.Complete == todo.Complete:
+		return nil
+	}
+	return tx.Create(&io.Completion{TodoID: todo.ID, Complete: todo.Complete, At: time.Now()}).Error
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// PathSeparator joins the names of a category's ancestors in its path.
+const PathSeparator = " / "
+
+// ancestorsQuery walks up from a category to the root in one statement,
+// the depth keeping the order. The depth limit guards against cycles.
+const ancestorsQuery = `WITH RECURSIVE path AS (
+	SELECT todo_categories.*, 0 AS depth FROM todo_categories WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT todo_categories.*, path.depth + 1 FROM todo_categories JOIN path ON todo_categories.id = path.parent_id
+	WHERE todo_categories.deleted_at IS NULL AND path.depth < 1000
+) SELECT * FROM path ORDER BY depth DESC`
+
+// GetCategoryTree returns every category, nested under its parent, with
+// its path and todo counts. It takes two queries whatever the size of the
+// tree.
+func (b *basicTodoService) GetCategoryTree(ctx context.Context) (c []io.CategoryNode, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	var categories []io.TodoCategory
+	var counts map[uint][2]int
+	if at, ok := asOf(ctx); ok {
+		var todos []io.Todo
+		categories, error = categoriesAsOf(session, at)
+		if error == nil {
+			todos, error = todosAsOf(session, at)
+		}
+		counts = countTodos(todos)
+	} else if error = session.Order("name").Find(&categories).Error; error == nil {
+		counts, error = todoCounts(session)
+	}
+	if error != nil {
+		return c, error
+	}
+	children := map[uint][]io.TodoCategory{}
+	known := map[uint]bool{}
+	for _, v := range categories {
+		children[v.ParentID] = append(children[v.ParentID], v)
+		known[v.ID] = true
+	}
+	seen := map[uint]bool{}
+	c = nestCategories(children, counts, 0, "", seen)
+	// Categories whose parent is gone, then the ones stuck in a cycle, would
+	// be left out: list them at the top level instead.
+	for _, orphans := range []bool{true, false} {
+		for _, v := range categories {
+			if !seen[v.ID] && known[v.ParentID] != orphans {
+				c = append(c, categoryNode(children, counts, v, "", seen))
+			}
+		}
+	}
+	return c, nil
+}
+
+// GetCategoryPath returns the breadcrumb of the category id: its
+// ancestors, root first, and the category itself last.
+func (b *basicTodoService) GetCategoryPath(ctx context.Context, id string) (c []io.TodoCategory, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	category := io.TodoCategory{}
+	if err := session.Where("id = ?", id).First(&category).Error; err != nil {
+		return c, err
+	}
+	if session.Dialect().GetName() == "postgres" {
+		error = session.Raw(ancestorsQuery, category.ID).Scan(&c).Error
+		return c, error
+	}
+	c = []io.TodoCategory{category}
+	seen := map[uint]bool{category.ID: true}
+	for p := category.ParentID; p != 0 && !seen[p]; {
+		seen[p] = true
+		parent := io.TodoCategory{}
+		err := session.Where("id = ?", p).First(&parent).Error
+		if gorm.IsRecordNotFoundError(err) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		c = append([]io.TodoCategory{parent}, c...)
+		p = parent.ParentID
+	}
+	return c, nil
+}
+
+// todoCounts returns the number of open and completed todos per category.
+func todoCounts(session *gorm.DB) (map[uint][2]int, error) {
+	rows, err := session.Model(&io.Todo{}).Select("category_id, complete, count(*)").Group("category_id, complete").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := map[uint][2]int{}
+	for rows.Next() {
+		var id uint
+		var complete bool
+		var n int
+		if err := rows.Scan(&id, &complete, &n); err != nil {
+			return nil, err
+		}
+		c := counts[id]
+		if complete {
+			c[1] += n
+		} else {
+			c[0] += n
+		}
+		counts[id] = c
+	}
+	return counts, rows.Err()
+}
+
+func nestCategories(children map[uint][]io.TodoCategory, counts map[uint][2]int, parent uint, path string, seen map[uint]bool) (nodes []io.CategoryNode) {
+	for _, v := range children[parent] {
+		if !seen[v.ID] {
+			nodes = append(nodes, categoryNode(children, counts, v, path, seen))
+		}
+	}
+	return nodes
+}
+
+// categoryNode builds the node of category, below the ancestors in path.
+func categoryNode(children map[uint][]io.TodoCategory, counts map[uint][2]int, category io.TodoCategory, path string, seen map[uint]bool) io.CategoryNode {
+	seen[category.ID] = true
+	n := io.CategoryNode{TodoCategory: category, Path: category.Name}
+	if path != "" {
+		n.Path = path + PathSeparator + category.Name
+	}
+	n.Open, n.Completed = counts[category.ID][0], counts[category.ID][1]
+	n.Children = nestCategories(children, counts, category.ID, n.Path, seen)
+	return n
+}
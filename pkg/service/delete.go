@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrHasChildren is returned when the refuse delete policy meets a todo
+// with subtasks or a category that isn't empty.
+var ErrHasChildren = errors.New("cannot delete an item that has children")
+
+// deleteTodo deletes todo in tx, applying policy to its subtasks.
+func deleteTodo(tx *gorm.DB, todo io.Todo, policy string) error {
+	var children, below []io.Todo
+	err := tx.Where("parent_id = ?", todo.ID).Find(&children).Error
+	if err == nil && policy == io.DeleteCascade {
+		below, err = descendants(tx, todo.ID)
+	}
+	if err != nil {
+		return err
+	}
+	moved, removed, err := todoDeletion(todo, children, below, policy)
+	if err != nil {
+		return err
+	}
+	for _, v := range moved {
+		if err := saveTodo(tx, io.TodoMoved, v); err != nil {
+			return err
+		}
+	}
+	for _, v := range append(removed, todo) {
+		if err := removeTodo(tx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// todoDeletion applies policy to the subtasks of todo about to be deleted:
+// its children, and every todo below it for cascade. It returns the todos
+// to move, with their new parent, and the ones to delete along with todo.
+func todoDeletion(todo io.Todo, children, below []io.Todo, policy string) (moved, removed []io.Todo, err error) {
+	switch policy {
+	case "", io.DeleteRefuse:
+		if len(children) > 0 {
+			return nil, nil, ErrHasChildren
+		}
+	case io.DeleteReparent:
+		for _, v := range children {
+			v.ParentID = todo.ParentID
+			moved = append(moved, v)
+		}
+	case io.DeleteCascade:
+		removed = below
+	default:
+		return nil, nil, fmt.Errorf("unknown delete policy %q", policy)
+	}
+	return moved, removed, nil
+}
+
+// deleteCategory deletes category in tx, applying policy to its sub
+// categories and to its todos.
+func deleteCategory(tx *gorm.DB, category io.TodoCategory, policy string) error {
+	var children []io.TodoCategory
+	if err := tx.Where("parent_id = ?", category.ID).Find(&children).Error; err != nil {
+		return err
+	}
+	var todos []io.Todo
+	if err := tx.Where("category_id = ?", category.ID).Find(&todos).Error; err != nil {
+		return err
+	}
+	categories, moved, err := categoryDeletion(category, children, todos, policy)
+	if err != nil {
+		return err
+	}
+	for _, v := range categories {
+		if err := tx.Save(&v).Error; err != nil {
+			return err
+		}
+		if err := recordCategory(tx, io.CategoryMoved, v); err != nil {
+			return err
+		}
+	}
+	for _, v := range moved {
+		if err := saveTodo(tx, io.TodoMoved, v); err != nil {
+			return err
+		}
+	}
+	if policy == io.DeleteCascade {
+		if err := cascadeCategory(tx, category); err != nil {
+			return err
+		}
+	}
+	if err := tx.Delete(&category).Error; err != nil {
+		return err
+	}
+	return recordCategory(tx, io.CategoryDeleted, category)
+}
+
+// categoryDeletion applies policy to the sub categories and the todos of
+// category about to be deleted. It returns the ones to move, with their
+// new parent or category; cascadeCategory deletes them for cascade.
+func categoryDeletion(category io.TodoCategory, children []io.TodoCategory, todos []io.Todo, policy string) (categories []io.TodoCategory, moved []io.Todo, err error) {
+	switch policy {
+	case "", io.DeleteRefuse:
+		if len(children) > 0 || len(todos) > 0 {
+			return nil, nil, ErrHasChildren
+		}
+	case io.DeleteReparent:
+		for _, v := range children {
+			v.ParentID = category.ParentID
+			categories = append(categories, v)
+		}
+		for _, v := range todos {
+			v.CategoryID = category.ParentID
+			moved = append(moved, v)
+		}
+	case io.DeleteCascade:
+	default:
+		return nil, nil, fmt.Errorf("unknown delete policy %q", policy)
+	}
+	return categories, moved, nil
+}
+
+// cascadeCategory deletes the sub categories of category and the todos of
+// all of them, with the subtasks of those todos whatever their category.
+func cascadeCategory(tx *gorm.DB, category io.TodoCategory) error {
+	all, err := categoryDescendants(tx, category.ID)
+	if err != nil {
+		return err
+	}
+	ids := []uint{category.ID}
+	for _, v := range all {
+		ids = append(ids, v.ID)
+	}
+	var todos []io.Todo
+	if err := tx.Where("category_id in (?)", ids).Find(&todos).Error; err != nil {
+		return err
+	}
+	deleted := map[uint]bool{}
+	for _, t := range todos {
+		below, err := descendants(tx, t.ID)
+		if err != nil {
+			return err
+		}
+		for _, v := range append(below, t) {
+			if deleted[v.ID] {
+				continue
+			}
+			deleted[v.ID] = true
+			if err := removeTodo(tx, v); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range all {
+		if err := tx.Delete(&v).Error; err != nil {
+			return err
+		}
+		if err := recordCategory(tx, io.CategoryDeleted, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveTodo(tx *gorm.DB, typ string, todo io.Todo) error {
+	if err := tx.Save(&todo).Error; err != nil {
+		return err
+	}
+	return recordTodo(tx, typ, todo)
+}
+
+func removeTodo(tx *gorm.DB, todo io.Todo) error {
+	if err := tx.Delete(&todo).Error; err != nil {
+		return err
+	}
+	return recordTodo(tx, io.TodoDeleted, todo)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"todo/pkg/io"
+)
+
+func TestTodoDeletion(t *testing.T) {
+	todo := func(id, parent uint) io.Todo {
+		v := io.Todo{ParentID: parent}
+		v.ID = id
+		return v
+	}
+	deleted := todo(2, 1)
+	children := []io.Todo{todo(3, 2), todo(4, 2)}
+	below := append([]io.Todo{todo(5, 3)}, children...)
+	tests := []struct {
+		name     string
+		children []io.Todo
+		below    []io.Todo
+		policy   string
+		moved    []io.Todo
+		removed  []io.Todo
+		wantErr  error
+		fails    bool
+	}{
+		{name: "refuse without subtasks", policy: io.DeleteRefuse},
+		{name: "default without subtasks", policy: ""},
+		{name: "refuse with subtasks", children: children, policy: io.DeleteRefuse, wantErr: ErrHasChildren, fails: true},
+		{name: "default with subtasks", children: children, policy: "", wantErr: ErrHasChildren, fails: true},
+		{name: "reparent", children: children, policy: io.DeleteReparent, moved: []io.Todo{todo(3, 1), todo(4, 1)}},
+		{name: "reparent without subtasks", policy: io.DeleteReparent},
+		{name: "cascade", children: children, below: below, policy: io.DeleteCascade, removed: below},
+		{name: "unknown policy", children: children, policy: "shred", fails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved, removed, err := todoDeletion(deleted, tt.children, tt.below, tt.policy)
+			if (err != nil) != tt.fails || (tt.wantErr != nil && err != tt.wantErr) {
+				t.Fatalf("todoDeletion error = %v", err)
+			}
+			if !reflect.DeepEqual(moved, tt.moved) {
+				t.Errorf("moved = %+v, want %+v", moved, tt.moved)
+			}
+			if !reflect.DeepEqual(removed, tt.removed) {
+				t.Errorf("removed = %+v, want %+v", removed, tt.removed)
+			}
+		})
+	}
+	if children[0].ParentID != 2 {
+		t.Error("todoDeletion changed the children it was given")
+	}
+}
+
+func TestCategoryDeletion(t *testing.T) {
+	category := func(id, parent uint) io.TodoCategory {
+		v := io.TodoCategory{ParentID: parent}
+		v.ID = id
+		return v
+	}
+	todo := func(id, category uint) io.Todo {
+		v := io.Todo{CategoryID: category}
+		v.ID = id
+		return v
+	}
+	deleted := category(2, 1)
+	tests := []struct {
+		name       string
+		children   []io.TodoCategory
+		todos      []io.Todo
+		policy     string
+		categories []io.TodoCategory
+		moved      []io.Todo
+		wantErr    error
+		fails      bool
+	}{
+		{name: "refuse when empty", policy: io.DeleteRefuse},
+		{name: "refuse with sub categories", children: []io.TodoCategory{category(3, 2)}, policy: io.DeleteRefuse, wantErr: ErrHasChildren, fails: true},
+		{name: "refuse with todos", todos: []io.Todo{todo(7, 2)}, policy: "", wantErr: ErrHasChildren, fails: true},
+		{
+			name:       "reparent",
+			children:   []io.TodoCategory{category(3, 2), category(4, 2)},
+			todos:      []io.Todo{todo(7, 2)},
+			policy:     io.DeleteReparent,
+			categories: []io.TodoCategory{category(3, 1), category(4, 1)},
+			moved:      []io.Todo{todo(7, 1)},
+		},
+		{name: "cascade moves nothing", children: []io.TodoCategory{category(3, 2)}, todos: []io.Todo{todo(7, 2)}, policy: io.DeleteCascade},
+		{name: "unknown policy", policy: "shred", fails: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categories, moved, err := categoryDeletion(deleted, tt.children, tt.todos, tt.policy)
+			if (err != nil) != tt.fails || (tt.wantErr != nil && err != tt.wantErr) {
+				t.Fatalf("categoryDeletion error = %v", err)
+			}
+			if !reflect.DeepEqual(categories, tt.categories) {
+				t.Errorf("categories = %+v, want %+v", categories, tt.categories)
+			}
+			if !reflect.DeepEqual(moved, tt.moved) {
+				t.Errorf("moved = %+v, want %+v", moved, tt.moved)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrBlocked is returned when completing a todo that still has open
+// blockers, with Config.EnforceDependencies set.
+var ErrBlocked = errors.New("the todo has open blockers")
+
+// ErrDependencyCycle is returned when a todo would wait for itself.
+var ErrDependencyCycle = errors.New("the blocker is the todo itself or waits for it")
+
+// openBlockers matches the todos having a blocker that is neither
+// complete nor deleted.
+const openBlockers = `EXISTS (SELECT 1 FROM dependencies JOIN todos blockers ON blockers.id = dependencies.blocker_id
+	WHERE dependencies.todo_id = todos.id AND NOT blockers.complete AND blockers.deleted_at IS NULL)`
+
+// AddDependency makes the todo id wait for the todo blockerId, unless
+// blockerId already waits for id, directly or not.
+func (b *basicTodoService) AddDependency(ctx context.Context, id string, blockerId uint) (d io.Dependency, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	todo, blocker := io.Todo{}, io.Todo{}
+	if err := session.Where("id = ?", id).First(&todo).Error; err != nil {
+		return d, err
+	}
+	if err := session.Where("id = ?", blockerId).First(&blocker).Error; err != nil {
+		return d, err
+	}
+	tx := session.Begin()
+	error = checkDependency(tx, todo.ID, blocker.ID)
+	if error == nil {
+		d = io.Dependency{TodoID: todo.ID, BlockerID: blocker.ID}
+		error = tx.Where(d).First(&d).Error
+	}
+	// Adding a dependency twice changes nothing.
+	if gorm.IsRecordNotFoundError(error) {
+		error = tx.Create(&d).Error
+		if error == nil {
+			error = recordDependency(tx, io.TodoBlocked, todo, d)
+		}
+	}
+	return d, finish(tx, error)
+}
+
+func (b *basicTodoService) RemoveDependency(ctx context.Context, id string, blockerId uint) (error error) {
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	if err := session.Where("id = ?", id).First(&todo).Error; err != nil {
+		return err
+	}
+	tx := session.Begin()
+	d := io.Dependency{}
+	error = tx.Where("todo_id = ? AND blocker_id = ?", todo.ID, blockerId).First(&d).Error
+	if error == nil {
+		error = tx.Delete(&d).Error
+	}
+	if error == nil {
+		error = recordDependency(tx, io.TodoUnblocked, todo, d)
+	}
+	return finish(tx, error)
+}
+
+// GetBlocked returns the open todos waiting for an open blocker.
+func (b *basicTodoService) GetBlocked(ctx context.Context) (t []io.Todo, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Where("NOT complete AND " + openBlockers).Order(positionOrder).Find(&t).Error
+	return t, error
+}
+
+// GetReady returns the open todos that wait for nothing, or only for
+// completed todos.
+func (b *basicTodoService) GetReady(ctx context.Context) (t []io.Todo, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Where("NOT complete AND NOT " + openBlockers).Order(positionOrder).Find(&t).Error
+	return t, error
+}
+
+// GetDependencyGraph returns every dependency between todos that are not
+// deleted, with those todos.
+func (b *basicTodoService) GetDependencyGraph(ctx context.Context) (g io.DependencyGraph, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	var deps []io.Dependency
+	if err := session.Order("id").Find(&deps).Error; err != nil {
+		return g, err
+	}
+	ids := []uint{}
+	for _, v := range deps {
+		ids = append(ids, v.TodoID, v.BlockerID)
+	}
+	g = io.DependencyGraph{Nodes: []io.Todo{}, Edges: []io.Dependency{}}
+	if len(ids) == 0 {
+		return g, nil
+	}
+	if err := session.Where("id in (?)", ids).Order("id").Find(&g.Nodes).Error; err != nil {
+		return g, err
+	}
+	live := map[uint]bool{}
+	for _, v := range g.Nodes {
+		live[v.ID] = true
+	}
+	for _, v := range deps {
+		if live[v.TodoID] && live[v.BlockerID] {
+			g.Edges = append(g.Edges, v)
+		}
+	}
+	return g, nil
+}
+
+// checkDependency makes sure that todo can wait for blocker: blocker must
+// not be todo itself, or wait for it through its own blockers.
+func checkDependency(tx *gorm.DB, todo, blocker uint) error {
+	return checkWaits(todo, blocker, func(level []uint) (next []uint, err error) {
+		err = tx.Model(&io.Dependency{}).Where("todo_id in (?)", level).Pluck("blocker_id", &next).Error
+		return next, err
+	})
+}
+
+// checkWaits walks the blockers of blocker, one level at a time through
+// blockersOf, and fails with ErrDependencyCycle if it meets todo.
+func checkWaits(todo, blocker uint, blockersOf func(level []uint) ([]uint, error)) error {
+	seen := map[uint]bool{}
+	for level := []uint{blocker}; len(level) > 0; {
+		for _, v := range level {
+			if v == todo {
+				return ErrDependencyCycle
+			}
+			seen[v] = true
+		}
+		next, err := blockersOf(level)
+		if err != nil {
+			return err
+		}
+		level = nil
+		for _, v := range next {
+			if !seen[v] {
+				seen[v] = true
+				level = append(level, v)
+			}
+		}
+	}
+	return nil
+}
+
+// checkBlockers fails with ErrBlocked when todo has open blockers.
+func checkBlockers(tx *gorm.DB, todo uint) error {
+	var n int
+	err := tx.Model(&io.Todo{}).Where("todos.id = ? AND "+openBlockers, todo).Count(&n).Error
+	if err == nil && n > 0 {
+		return ErrBlocked
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckWaits(t *testing.T) {
+	// blockers maps each todo to the todos it waits for.
+	tests := []struct {
+		name     string
+		blockers map[uint][]uint
+		todo     uint
+		blocker  uint
+		want     error
+	}{
+		{"no dependencies", nil, 1, 2, nil},
+		{"itself", nil, 1, 1, ErrDependencyCycle},
+		{"direct cycle", map[uint][]uint{2: {1}}, 1, 2, ErrDependencyCycle},
+		{"indirect cycle", map[uint][]uint{2: {3}, 3: {4}, 4: {1}}, 1, 2, ErrDependencyCycle},
+		{"chain without todo", map[uint][]uint{2: {3}, 3: {4}}, 1, 2, nil},
+		{"diamond", map[uint][]uint{2: {3, 4}, 3: {5}, 4: {5}}, 1, 2, nil},
+		{"diamond back to todo", map[uint][]uint{2: {3, 4}, 3: {5}, 4: {5}, 5: {1}}, 1, 2, ErrDependencyCycle},
+		{"existing cycle elsewhere", map[uint][]uint{2: {3}, 3: {2}}, 1, 2, nil},
+		{"todo waits for blocker already", map[uint][]uint{1: {2}}, 1, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			got := checkWaits(tt.todo, tt.blocker, func(level []uint) (next []uint, err error) {
+				if calls++; calls > 10 {
+					t.Fatal("walk does not end")
+				}
+				for _, v := range level {
+					next = append(next, tt.blockers[v]...)
+				}
+				return next, nil
+			})
+			if got != tt.want {
+				t.Errorf("checkWaits(%d, %d) = %v, want %v", tt.todo, tt.blocker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWaitsError(t *testing.T) {
+	failed := errors.New("failed")
+	got := checkWaits(1, 2, func(level []uint) ([]uint, error) { return nil, failed })
+	if got != failed {
+		t.Errorf("checkWaits = %v, want %v", got, failed)
+	}
+}
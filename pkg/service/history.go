@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"todo/pkg/db"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+	asOfKey
+)
+
+// Keys of the gorm settings carrying the actor and the request id from the
+// session down to the history.
+const (
+	actorSetting     = "todo:actor"
+	requestIDSetting = "todo:request_id"
+)
+
+// WithActor returns a copy of ctx naming actor as the author of the
+// changes made with it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithRequestID returns a copy of ctx tying the changes made with it to
+// the request id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// connect opens a session carrying the actor and the request id of ctx,
+// which the history records with every change made through it. Without a
+// request id, the session gets one of its own, so that its changes still
+// make one operation.
+func connect(ctx context.Context) *gorm.DB {
+	id, _ := ctx.Value(requestIDKey).(string)
+	if id == "" {
+		id = NewRequestID()
+	}
+	return db.ConnectPGDB().Set(actorSetting, actor(ctx)).Set(requestIDSetting, id)
+}
+
+// actor returns the actor of ctx, "" when it has none.
+func actor(ctx context.Context) string {
+	a, _ := ctx.Value(actorKey).(string)
+	return a
+}
+
+// NewRequestID returns a random request id.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GetTodoHistory returns the changes to the todo id, deleted or not, the
+// oldest first.
+func (b *basicTodoService) GetTodoHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	if err := session.Unscoped().Where("id = ?", id).First(&todo).Error; err != nil {
+		return h, err
+	}
+	return changesOf(session, io.TrashTodo, todo.ID)
+}
+
+// GetCategoryHistory returns the changes to the category id, deleted or
+// not, the oldest first.
+func (b *basicTodoService) GetCategoryHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	category := io.TodoCategory{}
+	if err := session.Unscoped().Where("id = ?", id).First(&category).Error; err != nil {
+		return h, err
+	}
+	return changesOf(session, io.TrashCategory, category.ID)
+}
+
+func changesOf(session *gorm.DB, kind string, id uint) (h []io.Change, err error) {
+	err = session.Where("kind = ? AND item_id = ?", kind, id).Order("id").Find(&h).Error
+	for i := range h {
+		if h[i].Diff, err = diff(h[i].Before, h[i].After); err != nil {
+			return nil, err
+		}
+	}
+	return h, err
+}
+
+// recordChange appends to the history of the item id of kind its state
+// after the operation typ, nil for a deletion. Its state before is the
+// one the previous change left it in.
+func recordChange(tx *gorm.DB, kind string, id uint, typ string, after interface{}) error {
+	c := io.Change{Kind: kind, ItemID: id, Operation: typ}
+	if after != nil {
+		b, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		c.After = b
+	}
+	last := io.Change{}
+	err := tx.Where("kind = ? AND item_id = ?", kind, id).Order("id desc").First(&last).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+	c.Before = last.After
+	if v, ok := tx.Get(actorSetting); ok {
+		c.Actor = v.(string)
+	}
+	if v, ok := tx.Get(requestIDSetting); ok {
+		c.RequestID = v.(string)
+	}
+	if v, ok := tx.Get(undoSetting); ok {
+		c.Undo = v.(string)
+	}
+	if v, ok := tx.Get(redoSetting); ok {
+		c.Redo = v.(string)
+	}
+	return tx.Create(&c).Error
+}
+
+// diff lists the fields that differ between two snapshots, by name, but
+// for the update time which always does.
+func diff(before, after io.Snapshot) ([]io.FieldChange, error) {
+	var from, to map[string]interface{}
+	if before != nil {
+		if err := json.Unmarshal(before, &from); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &to); err != nil {
+			return nil, err
+		}
+	}
+	fields := []string{}
+	for k := range from {
+		fields = append(fields, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	sort.Strings(fields)
+	d := []io.FieldChange{}
+	for _, k := range fields {
+		if k != "UpdatedAt" && k != "updated_at" && !reflect.DeepEqual(from[k], to[k]) {
+			d = append(d, io.FieldChange{Field: k, Before: from[k], After: to[k]})
+		}
+	}
+	return d, nil
+}
+
+// SnapshotHistory starts the history of the items from before it, deleted
+// or not, with their current state.
+func SnapshotHistory(session *gorm.DB) error {
+	var todos []io.Todo
+	err := unrecorded(session, io.TrashTodo, &todos)
+	for i := 0; err == nil && i < len(todos); i++ {
+		err = recordChange(session, io.TrashTodo, todos[i].ID, snapshotOf(io.TrashTodo), todos[i])
+	}
+	var categories []io.TodoCategory
+	if err == nil {
+		err = unrecorded(session, io.TrashCategory, &categories)
+	}
+	for i := 0; err == nil && i < len(categories); i++ {
+		err = recordChange(session, io.TrashCategory, categories[i].ID, snapshotOf(io.TrashCategory), categories[i])
+	}
+	var deps []io.Dependency
+	if err == nil {
+		err = unrecorded(session, io.HistoryDependency, &deps)
+	}
+	for i := 0; err == nil && i < len(deps); i++ {
+		err = recordChange(session, io.HistoryDependency, deps[i].ID, snapshotOf(io.HistoryDependency), deps[i])
+	}
+	var entries []io.TimeEntry
+	if err == nil {
+		err = unrecorded(session, io.HistoryTimeEntry, &entries)
+	}
+	for i := 0; err == nil && i < len(entries); i++ {
+		err = recordChange(session, io.HistoryTimeEntry, entries[i].ID, snapshotOf(io.HistoryTimeEntry), entries[i])
+	}
+	var workflows []io.Workflow
+	if err == nil {
+		err = unrecorded(session, io.HistoryWorkflow, &workflows)
+	}
+	for i := 0; err == nil && i < len(workflows); i++ {
+		err = recordChange(session, io.HistoryWorkflow, workflows[i].ID, snapshotOf(io.HistoryWorkflow), workflows[i])
+	}
+	return err
+}
+
+// unrecorded finds the items of kind without a history into items.
+func unrecorded(session *gorm.DB, kind string, items interface{}) error {
+	return session.Unscoped().Where("id NOT IN (SELECT item_id FROM changes WHERE kind = ?)", kind).Find(items).Error
+}
+
+// snapshotOf returns the operation of the changes SnapshotHistory makes to
+// items of kind.
+func snapshotOf(kind string) string {
+	return kind + ".snapshot"
+}
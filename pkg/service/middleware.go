@@ -48,11 +48,11 @@ func (l loggingMiddleware) RemoveComplete(ctx context.Context, id string) (error
 	}()
 	return l.next.RemoveComplete(ctx, id)
 }
-func (l loggingMiddleware) Delete(ctx context.Context, id string) (error error) {
+func (l loggingMiddleware) Delete(ctx context.Context, id string, policy string) (error error) {
 	defer func() {
-		l.logger.Log("method", "Delete", "id", id, "error", error)
+		l.logger.Log("method", "Delete", "id", id, "policy", policy, "error", error)
 	}()
-	return l.next.Delete(ctx, id)
+	return l.next.Delete(ctx, id, policy)
 }
 
 func (l loggingMiddleware) Update(ctx context.Context, todo io.Todo) (t io.Todo, error error) {
@@ -102,11 +102,11 @@ func (l loggingMiddleware) UpdateCategory(ctx context.Context, category io.TodoC
 	}()
 	return l.next.UpdateCategory(ctx, category)
 }
-func (l loggingMiddleware) DeleteCategory(ctx context.Context, id string) (error error) {
+func (l loggingMiddleware) DeleteCategory(ctx context.Context, id string, policy string) (error error) {
 	defer func() {
-		l.logger.Log("method", "DeleteCategory", "id", id, "error", error)
+		l.logger.Log("method", "DeleteCategory", "id", id, "policy", policy, "error", error)
 	}()
-	return l.next.DeleteCategory(ctx, id)
+	return l.next.DeleteCategory(ctx, id, policy)
 }
 
 func (l loggingMiddleware) GetCatChildes(ctx context.Context, id string) (c []io.TodoCategory, error error) {
@@ -115,3 +115,298 @@ func (l loggingMiddleware) GetCatChildes(ctx context.Context, id string) (c []io
 	}()
 	return l.next.GetCatChildes(ctx, id)
 }
+
+// AddWebhook leaves the secret out of the log.
+func (l loggingMiddleware) AddWebhook(ctx context.Context, webhook io.Webhook) (w io.Webhook, error error) {
+	defer func() {
+		l.logger.Log("method", "AddWebhook", "url", webhook.URL, "events", webhook.Events, "id", w.ID, "error", error)
+	}()
+	return l.next.AddWebhook(ctx, webhook)
+}
+
+func (l loggingMiddleware) GetWebhooks(ctx context.Context) (w []io.Webhook, error error) {
+	defer func() {
+		l.logger.Log("method", "GetWebhooks", "w", w, "error", error)
+	}()
+	return l.next.GetWebhooks(ctx)
+}
+
+func (l loggingMiddleware) DeleteWebhook(ctx context.Context, id string) (error error) {
+	defer func() {
+		l.logger.Log("method", "DeleteWebhook", "id", id, "error", error)
+	}()
+	return l.next.DeleteWebhook(ctx, id)
+}
+
+func (l loggingMiddleware) GetWebhookDeliveries(ctx context.Context, id string) (d []io.WebhookDelivery, error error) {
+	defer func() {
+		l.logger.Log("method", "GetWebhookDeliveries", "id", id, "d", d, "error", error)
+	}()
+	return l.next.GetWebhookDeliveries(ctx, id)
+}
+
+func (l loggingMiddleware) GetDeadLetters(ctx context.Context) (d []io.WebhookDelivery, error error) {
+	defer func() {
+		l.logger.Log("method", "GetDeadLetters", "d", d, "error", error)
+	}()
+	return l.next.GetDeadLetters(ctx)
+}
+
+func (l loggingMiddleware) RetryDelivery(ctx context.Context, id string) (d io.WebhookDelivery, error error) {
+	defer func() {
+		l.logger.Log("method", "RetryDelivery", "id", id, "d", d, "error", error)
+	}()
+	return l.next.RetryDelivery(ctx, id)
+}
+
+func (l loggingMiddleware) GetTree(ctx context.Context, id string, maxDepth int) (t io.TodoNode, error error) {
+	defer func() {
+		l.logger.Log("method", "GetTree", "id", id, "maxDepth", maxDepth, "t", t, "error", error)
+	}()
+	return l.next.GetTree(ctx, id, maxDepth)
+}
+
+func (l loggingMiddleware) Move(ctx context.Context, id string, parentId uint) (t io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "Move", "id", id, "parentId", parentId, "t", t, "error", error)
+	}()
+	return l.next.Move(ctx, id, parentId)
+}
+
+func (l loggingMiddleware) MoveCategory(ctx context.Context, id string, parentId uint) (c io.TodoCategory, error error) {
+	defer func() {
+		l.logger.Log("method", "MoveCategory", "id", id, "parentId", parentId, "c", c, "error", error)
+	}()
+	return l.next.MoveCategory(ctx, id, parentId)
+}
+
+func (l loggingMiddleware) ListTrash(ctx context.Context) (t io.Trash, error error) {
+	defer func() {
+		l.logger.Log("method", "ListTrash", "t", t, "error", error)
+	}()
+	return l.next.ListTrash(ctx)
+}
+
+func (l loggingMiddleware) Restore(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	defer func() {
+		l.logger.Log("method", "Restore", "kind", kind, "id", id, "subtree", subtree, "error", error)
+	}()
+	return l.next.Restore(ctx, kind, id, subtree)
+}
+
+func (l loggingMiddleware) Purge(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	defer func() {
+		l.logger.Log("method", "Purge", "kind", kind, "id", id, "subtree", subtree, "error", error)
+	}()
+	return l.next.Purge(ctx, kind, id, subtree)
+}
+
+func (l loggingMiddleware) GetCategoryTree(ctx context.Context) (c []io.CategoryNode, error error) {
+	defer func() {
+		l.logger.Log("method", "GetCategoryTree", "c", c, "error", error)
+	}()
+	return l.next.GetCategoryTree(ctx)
+}
+
+func (l loggingMiddleware) GetCategoryPath(ctx context.Context, id string) (c []io.TodoCategory, error error) {
+	defer func() {
+		l.logger.Log("method", "GetCategoryPath", "id", id, "c", c, "error", error)
+	}()
+	return l.next.GetCategoryPath(ctx, id)
+}
+
+func (l loggingMiddleware) Reorder(ctx context.Context, id string, before uint, after uint) (t io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "Reorder", "id", id, "before", before, "after", after, "t", t, "error", error)
+	}()
+	return l.next.Reorder(ctx, id, before, after)
+}
+
+func (l loggingMiddleware) Transition(ctx context.Context, id string, status string) (t io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "Transition", "id", id, "status", status, "t", t, "error", error)
+	}()
+	return l.next.Transition(ctx, id, status)
+}
+
+func (l loggingMiddleware) GetWorkflow(ctx context.Context, id string) (w io.Workflow, error error) {
+	defer func() {
+		l.logger.Log("method", "GetWorkflow", "id", id, "w", w, "error", error)
+	}()
+	return l.next.GetWorkflow(ctx, id)
+}
+
+func (l loggingMiddleware) SetWorkflow(ctx context.Context, id string, workflow io.Workflow) (w io.Workflow, error error) {
+	defer func() {
+		l.logger.Log("method", "SetWorkflow", "id", id, "workflow", workflow, "w", w, "error", error)
+	}()
+	return l.next.SetWorkflow(ctx, id, workflow)
+}
+
+func (l loggingMiddleware) GetBoard(ctx context.Context, id string) (b io.Board, error error) {
+	defer func() {
+		l.logger.Log("method", "GetBoard", "id", id, "b", b, "error", error)
+	}()
+	return l.next.GetBoard(ctx, id)
+}
+
+func (l loggingMiddleware) SetPriority(ctx context.Context, id string, priority uint8) (error error) {
+	defer func() {
+		l.logger.Log("method", "SetPriority", "id", id, "priority", priority, "error", error)
+	}()
+	return l.next.SetPriority(ctx, id, priority)
+}
+
+func (l loggingMiddleware) GetMatrix(ctx context.Context, urgentHours int) (m io.Matrix, error error) {
+	defer func() {
+		l.logger.Log("method", "GetMatrix", "urgentHours", urgentHours, "m", m, "error", error)
+	}()
+	return l.next.GetMatrix(ctx, urgentHours)
+}
+
+func (l loggingMiddleware) AddDependency(ctx context.Context, id string, blockerId uint) (d io.Dependency, error error) {
+	defer func() {
+		l.logger.Log("method", "AddDependency", "id", id, "blockerId", blockerId, "d", d, "error", error)
+	}()
+	return l.next.AddDependency(ctx, id, blockerId)
+}
+
+func (l loggingMiddleware) RemoveDependency(ctx context.Context, id string, blockerId uint) (error error) {
+	defer func() {
+		l.logger.Log("method", "RemoveDependency", "id", id, "blockerId", blockerId, "error", error)
+	}()
+	return l.next.RemoveDependency(ctx, id, blockerId)
+}
+
+func (l loggingMiddleware) GetBlocked(ctx context.Context) (t []io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "GetBlocked", "t", t, "error", error)
+	}()
+	return l.next.GetBlocked(ctx)
+}
+
+func (l loggingMiddleware) GetReady(ctx context.Context) (t []io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "GetReady", "t", t, "error", error)
+	}()
+	return l.next.GetReady(ctx)
+}
+
+func (l loggingMiddleware) GetDependencyGraph(ctx context.Context) (g io.DependencyGraph, error error) {
+	defer func() {
+		l.logger.Log("method", "GetDependencyGraph", "g", g, "error", error)
+	}()
+	return l.next.GetDependencyGraph(ctx)
+}
+
+func (l loggingMiddleware) GetCategoryPlan(ctx context.Context, id string) (p io.Plan, error error) {
+	defer func() {
+		l.logger.Log("method", "GetCategoryPlan", "id", id, "p", p, "error", error)
+	}()
+	return l.next.GetCategoryPlan(ctx, id)
+}
+
+func (l loggingMiddleware) GetTreePlan(ctx context.Context, id string) (p io.Plan, error error) {
+	defer func() {
+		l.logger.Log("method", "GetTreePlan", "id", id, "p", p, "error", error)
+	}()
+	return l.next.GetTreePlan(ctx, id)
+}
+
+func (l loggingMiddleware) StartTimer(ctx context.Context, id string) (te io.TimeEntry, error error) {
+	defer func() {
+		l.logger.Log("method", "StartTimer", "id", id, "te", te, "error", error)
+	}()
+	return l.next.StartTimer(ctx, id)
+}
+
+func (l loggingMiddleware) StopTimer(ctx context.Context) (te io.TimeEntry, error error) {
+	defer func() {
+		l.logger.Log("method", "StopTimer", "te", te, "error", error)
+	}()
+	return l.next.StopTimer(ctx)
+}
+
+func (l loggingMiddleware) AddTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	defer func() {
+		l.logger.Log("method", "AddTimeEntry", "id", id, "entry", entry, "te", te, "error", error)
+	}()
+	return l.next.AddTimeEntry(ctx, id, entry)
+}
+
+func (l loggingMiddleware) GetTimeEntries(ctx context.Context, id string) (te []io.TimeEntry, error error) {
+	defer func() {
+		l.logger.Log("method", "GetTimeEntries", "id", id, "te", te, "error", error)
+	}()
+	return l.next.GetTimeEntries(ctx, id)
+}
+
+func (l loggingMiddleware) UpdateTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	defer func() {
+		l.logger.Log("method", "UpdateTimeEntry", "id", id, "entry", entry, "te", te, "error", error)
+	}()
+	return l.next.UpdateTimeEntry(ctx, id, entry)
+}
+
+func (l loggingMiddleware) DeleteTimeEntry(ctx context.Context, id string) (error error) {
+	defer func() {
+		l.logger.Log("method", "DeleteTimeEntry", "id", id, "error", error)
+	}()
+	return l.next.DeleteTimeEntry(ctx, id)
+}
+
+func (l loggingMiddleware) GetTimeReport(ctx context.Context, from string, to string, user string) (r io.TimeReport, error error) {
+	defer func() {
+		l.logger.Log("method", "GetTimeReport", "from", from, "to", to, "user", user, "r", r, "error", error)
+	}()
+	return l.next.GetTimeReport(ctx, from, to, user)
+}
+
+func (l loggingMiddleware) GetBurndown(ctx context.Context, id string, from string, to string, unit string) (bd io.Burndown, error error) {
+	defer func() {
+		l.logger.Log("method", "GetBurndown", "id", id, "from", from, "to", to, "unit", unit, "bd", bd, "error", error)
+	}()
+	return l.next.GetBurndown(ctx, id, from, to, unit)
+}
+
+func (l loggingMiddleware) GetTodoHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	defer func() {
+		l.logger.Log("method", "GetTodoHistory", "id", id, "h", h, "error", error)
+	}()
+	return l.next.GetTodoHistory(ctx, id)
+}
+
+func (l loggingMiddleware) GetCategoryHistory(ctx context.Context, id string) (h []io.Change, error error) {
+	defer func() {
+		l.logger.Log("method", "GetCategoryHistory", "id", id, "h", h, "error", error)
+	}()
+	return l.next.GetCategoryHistory(ctx, id)
+}
+
+func (l loggingMiddleware) Undo(ctx context.Context, steps int) (h []io.Change, error error) {
+	defer func() {
+		l.logger.Log("method", "Undo", "steps", steps, "h", h, "error", error)
+	}()
+	return l.next.Undo(ctx, steps)
+}
+
+func (l loggingMiddleware) Redo(ctx context.Context, steps int) (h []io.Change, error error) {
+	defer func() {
+		l.logger.Log("method", "Redo", "steps", steps, "h", h, "error", error)
+	}()
+	return l.next.Redo(ctx, steps)
+}
+
+func (l loggingMiddleware) FindTodos(ctx context.Context, by string, ids []uint) (t []io.Todo, error error) {
+	defer func() {
+		l.logger.Log("method", "FindTodos", "by", by, "ids", ids, "t", t, "error", error)
+	}()
+	return l.next.FindTodos(ctx, by, ids)
+}
+
+func (l loggingMiddleware) FindCategories(ctx context.Context, by string, ids []uint) (c []io.TodoCategory, error error) {
+	defer func() {
+		l.logger.Log("method", "FindCategories", "by", by, "ids", ids, "c", c, "error", error)
+	}()
+	return l.next.FindCategories(ctx, by, ids)
+}
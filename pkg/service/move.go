@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrCycle is returned when a todo or a category would become its own
+// ancestor.
+var ErrCycle = errors.New("parent is the item itself or one of its descendants")
+
+// ErrParentNotFound is returned when the new parent of a todo or a
+// category does not exist, or is in the trash.
+var ErrParentNotFound = errors.New("parent not found")
+
+// Move makes the todo id a subtask of parentId, or a top level todo when
+// parentId is 0.
+func (b *basicTodoService) Move(ctx context.Context, id string, parentId uint) (t io.Todo, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	error = tx.Where("id = ?", id).First(&t).Error
+	if error == nil {
+		error = checkParent(tx, "todos", t.ID, parentId)
+	}
+	if error == nil && t.ParentID != parentId {
+		t.ParentID = parentId
+		t.Position, error = lastPosition(tx, t.ParentID, t.CategoryID)
+	}
+	if error == nil {
+		error = tx.Save(&t).Error
+	}
+	if error == nil {
+		error = recordTodo(tx, io.TodoMoved, t)
+	}
+	if error = finish(tx, error); error == nil {
+		error = withProgress(session, &t)
+	}
+	return t, error
+}
+
+// MoveCategory makes the category id a sub category of parentId, or a top
+// level category when parentId is 0.
+func (b *basicTodoService) MoveCategory(ctx context.Context, id string, parentId uint) (c io.TodoCategory, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	error = tx.Where("id = ?", id).First(&c).Error
+	if error == nil {
+		error = checkParent(tx, "todo_categories", c.ID, parentId)
+	}
+	if error == nil {
+		c.ParentID = parentId
+		error = tx.Save(&c).Error
+	}
+	if error == nil {
+		error = recordCategory(tx, io.CategoryMoved, c)
+	}
+	return c, finish(tx, error)
+}
+
+// checkParent makes sure that parentID, 0 for the root, exists in table
+// and can become the parent of id: id must not be among its ancestors.
+func checkParent(tx *gorm.DB, table string, id, parentID uint) error {
+	return checkAncestors(id, parentID, func(p uint) (uint, bool, error) {
+		var row struct{ ParentID uint }
+		err := tx.Table(table).Select("parent_id").Where("id = ? AND deleted_at IS NULL", p).Scan(&row).Error
+		if gorm.IsRecordNotFoundError(err) {
+			return 0, false, nil
+		}
+		return row.ParentID, err == nil, err
+	})
+}
+
+// checkAncestors walks up from parentID through parentOf, which reports
+// whether the item exists, and fails with ErrCycle if it meets id.
+func checkAncestors(id, parentID uint, parentOf func(id uint) (uint, bool, error)) error {
+	seen := map[uint]bool{}
+	for p := parentID; p != 0 && !seen[p]; {
+		if p == id {
+			return ErrCycle
+		}
+		seen[p] = true
+		next, ok, err := parentOf(p)
+		if err != nil {
+			return err
+		}
+		if !ok && p == parentID {
+			return fmt.Errorf("%w: %d", ErrParentNotFound, parentID)
+		}
+		p = next
+	}
+	return nil
+}
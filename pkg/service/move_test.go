@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckAncestors(t *testing.T) {
+	// parents maps each existing item to its parent, 0 for the root.
+	parents := map[uint]uint{1: 0, 2: 1, 3: 2, 4: 0, 5: 6, 6: 5}
+	tests := []struct {
+		name     string
+		id       uint
+		parentID uint
+		wantErr  error
+		fails    bool
+	}{
+		{"to the root", 3, 0, nil, false},
+		{"to a sibling tree", 4, 3, nil, false},
+		{"under its parent again", 3, 2, nil, false},
+		{"under itself", 2, 2, ErrCycle, true},
+		{"under its child", 2, 3, ErrCycle, true},
+		{"under a grandchild", 1, 3, ErrCycle, true},
+		{"missing parent", 1, 9, ErrParentNotFound, true},
+		{"below a loop", 1, 5, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAncestors(tt.id, tt.parentID, func(id uint) (uint, bool, error) {
+				p, ok := parents[id]
+				return p, ok, nil
+			})
+			if (err != nil) != tt.fails || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("checkAncestors(%d, %d) = %v", tt.id, tt.parentID, err)
+			}
+		})
+	}
+}
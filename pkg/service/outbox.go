@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// finish commits tx if err is nil and rolls it back otherwise.
+func finish(tx *gorm.DB, err error) error {
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// recordTodo records the change to todo, keeping its history and its
+// completion history up to date along the way.
+func recordTodo(tx *gorm.DB, typ string, todo io.Todo) error {
+	if err := logCompletion(tx, todo); err != nil {
+		return err
+	}
+	var after interface{}
+	if typ != io.TodoDeleted {
+		state := todo
+		state.Progress = 0
+		after = state
+	}
+	if err := recordChange(tx, io.TrashTodo, todo.ID, typ, after); err != nil {
+		return err
+	}
+	return record(tx, io.Event{Type: typ, CategoryID: todo.CategoryID, Todo: &todo})
+}
+
+// recordCategory records the change to category, keeping its history up
+// to date along the way.
+func recordCategory(tx *gorm.DB, typ string, category io.TodoCategory) error {
+	var after interface{}
+	if typ != io.CategoryDeleted {
+		after = category
+	}
+	if err := recordChange(tx, io.TrashCategory, category.ID, typ, after); err != nil {
+		return err
+	}
+	return record(tx, io.Event{Type: typ, CategoryID: category.ID, Category: &category})
+}
+
+// recordDependency records that todo started or stopped, typ telling
+// which, waiting for the blocker of d.
+func recordDependency(tx *gorm.DB, typ string, todo io.Todo, d io.Dependency) error {
+	var after interface{}
+	if typ != io.TodoUnblocked {
+		after = d
+	}
+	if err := recordChange(tx, io.HistoryDependency, d.ID, typ, after); err != nil {
+		return err
+	}
+	return record(tx, io.Event{Type: typ, CategoryID: todo.CategoryID, Todo: &todo})
+}
+
+// recordTimeEntry keeps the history of the time entry e up to date.
+func recordTimeEntry(tx *gorm.DB, typ string, e io.TimeEntry) error {
+	var after interface{}
+	if typ != io.TimeEntryDeleted {
+		e.Hours = 0
+		after = e
+	}
+	return recordChange(tx, io.HistoryTimeEntry, e.ID, typ, after)
+}
+
+// recordWorkflow keeps the history of the workflow w up to date.
+func recordWorkflow(tx *gorm.DB, typ string, w io.Workflow) error {
+	var after interface{}
+	if typ != io.WorkflowDropped {
+		after = w
+	}
+	return recordChange(tx, io.HistoryWorkflow, w.ID, typ, after)
+}
+
+// record writes event to the outbox in tx, so it exists if and only if the
+// change it describes is committed. The outbox relay publishes it.
+func record(tx *gorm.DB, event io.Event) error {
+	event.Time = time.Now().UTC()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&io.OutboxEvent{Type: event.Type, Payload: string(payload)}).Error
+}
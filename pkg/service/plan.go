@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// GetCategoryPlan schedules the open todos of the category id and of its
+// sub categories, from now.
+func (b *basicTodoService) GetCategoryPlan(ctx context.Context, id string) (p io.Plan, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	category, err := categoryID(session, id)
+	if err != nil {
+		return p, err
+	}
+	ids := []uint{category}
+	if category != 0 {
+		below, err := categoryDescendants(session, category)
+		if err != nil {
+			return p, err
+		}
+		for _, v := range below {
+			ids = append(ids, v.ID)
+		}
+	}
+	var todos []io.Todo
+	if err := session.Where("category_id in (?) AND NOT complete", ids).Find(&todos).Error; err != nil {
+		return p, err
+	}
+	return planTodos(session, todos)
+}
+
+// GetTreePlan schedules the todo id and its open subtasks, from now.
+func (b *basicTodoService) GetTreePlan(ctx context.Context, id string) (p io.Plan, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	root := io.Todo{}
+	if err := session.Where("id = ?", id).First(&root).Error; err != nil {
+		return p, err
+	}
+	below, err := descendants(session, root.ID)
+	if err != nil {
+		return p, err
+	}
+	var todos []io.Todo
+	for _, v := range append([]io.Todo{root}, below...) {
+		if !v.Complete {
+			todos = append(todos, v)
+		}
+	}
+	return planTodos(session, todos)
+}
+
+// planTodos loads the dependencies of todos, and their open blockers
+// outside of todos, and schedules them.
+func planTodos(session *gorm.DB, todos []io.Todo) (io.Plan, error) {
+	var deps []io.Dependency
+	var outside []io.Todo
+	if len(todos) > 0 {
+		ids := []uint{}
+		in := map[uint]bool{}
+		for _, v := range todos {
+			ids = append(ids, v.ID)
+			in[v.ID] = true
+		}
+		if err := session.Where("todo_id in (?)", ids).Find(&deps).Error; err != nil {
+			return io.Plan{}, err
+		}
+		blockers := []uint{}
+		for _, d := range deps {
+			if !in[d.BlockerID] {
+				blockers = append(blockers, d.BlockerID)
+			}
+		}
+		if len(blockers) > 0 {
+			if err := session.Where("id in (?) AND NOT complete", blockers).Find(&outside).Error; err != nil {
+				return io.Plan{}, err
+			}
+		}
+	}
+	return schedule(todos, deps, outside, time.Now())
+}
+
+// schedule runs the critical path method over todos, taking their
+// estimates as durations and the deps between them as precedences, for a
+// plan starting at start. The open todos outside of the plan some of them
+// wait for are fixed constraints: they could be done their estimate after
+// the start at best. Deps on anything else are done, and ignored.
+func schedule(todos []io.Todo, deps []io.Dependency, outside []io.Todo, start time.Time) (io.Plan, error) {
+	p := io.Plan{Start: start, Todos: []io.PlannedTodo{}, CriticalPath: []uint{}}
+	index := map[uint]int{}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	for i, v := range todos {
+		index[v.ID] = i
+		p.Todos = append(p.Todos, io.PlannedTodo{Todo: v})
+	}
+	ready := map[uint]float64{}
+	for _, v := range outside {
+		ready[v.ID] = v.Estimate
+	}
+	blockers := make([][]int, len(todos))
+	waiting := make([][]int, len(todos))
+	for _, d := range deps {
+		t, ok := index[d.TodoID]
+		if !ok {
+			continue
+		}
+		if b, ok := index[d.BlockerID]; ok {
+			blockers[t] = append(blockers[t], b)
+			waiting[b] = append(waiting[b], t)
+		} else if finish, ok := ready[d.BlockerID]; ok {
+			p.Todos[t].BlockedBy = append(p.Todos[t].BlockedBy, d.BlockerID)
+			p.Todos[t].EarliestStart = math.Max(p.Todos[t].EarliestStart, finish)
+		}
+	}
+
+	// Forward pass, in topological order.
+	order := make([]int, 0, len(todos))
+	left := make([]int, len(todos))
+	for i := range todos {
+		left[i] = len(blockers[i])
+		if left[i] == 0 {
+			order = append(order, i)
+		}
+	}
+	for n := 0; n < len(order); n++ {
+		i := order[n]
+		t := &p.Todos[i]
+		for _, b := range blockers[i] {
+			t.EarliestStart = math.Max(t.EarliestStart, p.Todos[b].EarliestFinish)
+		}
+		t.EarliestFinish = t.EarliestStart + t.Estimate
+		p.Duration = math.Max(p.Duration, t.EarliestFinish)
+		for _, w := range waiting[i] {
+			if left[w]--; left[w] == 0 {
+				order = append(order, w)
+			}
+		}
+	}
+	if len(order) < len(todos) {
+		return p, ErrDependencyCycle
+	}
+
+	// Backward pass.
+	for n := len(order) - 1; n >= 0; n-- {
+		i := order[n]
+		t := &p.Todos[i]
+		t.LatestFinish = p.Duration
+		for _, w := range waiting[i] {
+			t.LatestFinish = math.Min(t.LatestFinish, p.Todos[w].LatestStart)
+		}
+		t.LatestStart = t.LatestFinish - t.Estimate
+		t.Slack = t.LatestStart - t.EarliestStart
+		t.Critical = math.Abs(t.Slack) < epsilon
+		if t.Due != nil {
+			finish := start.Add(time.Duration(t.EarliestFinish * float64(time.Hour)))
+			t.Infeasible = t.Due.Before(finish)
+		}
+	}
+
+	// Every critical todo but the last one has a critical todo waiting for
+	// it that starts as it finishes, and every one but the first, which
+	// starts first or once the todos outside the plan could be done, waits
+	// for such a todo. The path runs from the first one in order to the
+	// end.
+	for _, i := range order {
+		if p.Todos[i].Critical && p.Duration > 0 {
+			for next := i; next >= 0; {
+				i, next = next, -1
+				p.CriticalPath = append(p.CriticalPath, p.Todos[i].ID)
+				for _, w := range waiting[i] {
+					if p.Todos[w].Critical && math.Abs(p.Todos[w].EarliestStart-p.Todos[i].EarliestFinish) < epsilon {
+						next = w
+						break
+					}
+				}
+			}
+			break
+		}
+	}
+	return p, nil
+}
+
+// epsilon absorbs the rounding of float hours.
+const epsilon = 1e-9
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo/pkg/io"
+)
+
+func TestSchedule(t *testing.T) {
+	start := time.Date(2020, 1, 6, 9, 0, 0, 0, time.UTC)
+	todo := func(id uint, estimate float64) io.Todo {
+		v := io.Todo{Estimate: estimate}
+		v.ID = id
+		return v
+	}
+	due := func(v io.Todo, hours float64) io.Todo {
+		d := start.Add(time.Duration(hours * float64(time.Hour)))
+		v.Due = &d
+		return v
+	}
+	dep := func(todo, blocker uint) io.Dependency {
+		return io.Dependency{TodoID: todo, BlockerID: blocker}
+	}
+	tests := []struct {
+		name       string
+		todos      []io.Todo
+		deps       []io.Dependency
+		outside    []io.Todo
+		wantErr    error
+		duration   float64
+		path       []uint
+		starts     map[uint]float64
+		slacks     map[uint]float64
+		infeasible []uint
+	}{
+		{
+			name: "empty",
+			path: []uint{},
+		},
+		{
+			name:     "independent",
+			todos:    []io.Todo{todo(1, 2), todo(2, 5)},
+			duration: 5,
+			path:     []uint{2},
+			starts:   map[uint]float64{1: 0, 2: 0},
+			slacks:   map[uint]float64{1: 3, 2: 0},
+		},
+		{
+			name:     "chain",
+			todos:    []io.Todo{todo(3, 3), todo(1, 1), todo(2, 2)},
+			deps:     []io.Dependency{dep(2, 1), dep(3, 2)},
+			duration: 6,
+			path:     []uint{1, 2, 3},
+			starts:   map[uint]float64{1: 0, 2: 1, 3: 3},
+			slacks:   map[uint]float64{1: 0, 2: 0, 3: 0},
+		},
+		{
+			name:     "diamond",
+			todos:    []io.Todo{todo(1, 2), todo(2, 3), todo(3, 1), todo(4, 1)},
+			deps:     []io.Dependency{dep(2, 1), dep(3, 1), dep(4, 2), dep(4, 3)},
+			duration: 6,
+			path:     []uint{1, 2, 4},
+			starts:   map[uint]float64{1: 0, 2: 2, 3: 2, 4: 5},
+			slacks:   map[uint]float64{1: 0, 2: 0, 3: 2, 4: 0},
+		},
+		{
+			name:     "open blocker outside",
+			todos:    []io.Todo{todo(1, 2), todo(2, 5)},
+			deps:     []io.Dependency{dep(1, 9)},
+			outside:  []io.Todo{todo(9, 4)},
+			duration: 6,
+			path:     []uint{1},
+			starts:   map[uint]float64{1: 4, 2: 0},
+			slacks:   map[uint]float64{1: 0, 2: 1},
+		},
+		{
+			name:     "done blocker outside",
+			todos:    []io.Todo{todo(1, 2)},
+			deps:     []io.Dependency{dep(1, 9)},
+			duration: 2,
+			path:     []uint{1},
+			starts:   map[uint]float64{1: 0},
+		},
+		{
+			name:       "due too early",
+			todos:      []io.Todo{due(todo(1, 2), 3), due(todo(2, 2), 3)},
+			deps:       []io.Dependency{dep(2, 1)},
+			duration:   4,
+			path:       []uint{1, 2},
+			infeasible: []uint{2},
+		},
+		{
+			name:    "cycle",
+			todos:   []io.Todo{todo(1, 1), todo(2, 1)},
+			deps:    []io.Dependency{dep(1, 2), dep(2, 1)},
+			wantErr: ErrDependencyCycle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := schedule(tt.todos, tt.deps, tt.outside, start)
+			if err != tt.wantErr {
+				t.Fatalf("schedule error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if p.Duration != tt.duration {
+				t.Errorf("Duration = %g, want %g", p.Duration, tt.duration)
+			}
+			if !reflect.DeepEqual(p.CriticalPath, tt.path) {
+				t.Errorf("CriticalPath = %v, want %v", p.CriticalPath, tt.path)
+			}
+			infeasible := []uint{}
+			for _, v := range p.Todos {
+				if s, ok := tt.starts[v.ID]; ok && v.EarliestStart != s {
+					t.Errorf("EarliestStart of %d = %g, want %g", v.ID, v.EarliestStart, s)
+				}
+				if s, ok := tt.slacks[v.ID]; ok && v.Slack != s {
+					t.Errorf("Slack of %d = %g, want %g", v.ID, v.Slack, s)
+				}
+				if v.Critical != (v.Slack == 0) {
+					t.Errorf("Critical of %d = %v with a slack of %g", v.ID, v.Critical, v.Slack)
+				}
+				if v.Infeasible {
+					infeasible = append(infeasible, v.ID)
+				}
+			}
+			if len(tt.infeasible) > 0 || len(infeasible) > 0 {
+				if !reflect.DeepEqual(infeasible, tt.infeasible) {
+					t.Errorf("infeasible todos = %v, want %v", infeasible, tt.infeasible)
+				}
+			}
+		})
+	}
+}
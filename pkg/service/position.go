@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// positionOrder sorts todos by position, the oldest first on a tie as
+// with todos created before positions existed.
+const positionOrder = "position, id"
+
+// Reorder moves the todo id right before the todo before, or right after
+// the todo after, joining its list if it was in another one. Only the todo
+// itself is rewritten, unless its neighbours' positions are too close to
+// fit it in between: then their list is renumbered.
+func (b *basicTodoService) Reorder(ctx context.Context, id string, before uint, after uint) (t io.Todo, error error) {
+	if (before == 0) == (after == 0) {
+		return t, errors.New("reorder needs either before or after")
+	}
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	error = tx.Where("id = ?", id).First(&t).Error
+	sibling := io.Todo{}
+	if error == nil {
+		error = tx.Where("id = ?", before+after).First(&sibling).Error
+	}
+	if error == nil && sibling.ID == t.ID {
+		error = errors.New("a todo can not be reordered around itself")
+	}
+	if error == nil && sibling.ParentID != t.ParentID {
+		error = checkParent(tx, "todos", t.ID, sibling.ParentID)
+	}
+	if error == nil {
+		t.ParentID, t.CategoryID = sibling.ParentID, sibling.CategoryID
+		t.Position, error = positionNear(tx, t, sibling, before != 0)
+	}
+	if error == nil {
+		error = saveTodo(tx, io.TodoMoved, t)
+	}
+	if error = finish(tx, error); error == nil {
+		error = withProgress(session, &t)
+	}
+	return t, error
+}
+
+// positionNear returns a position for todo right before or after sibling,
+// halfway to the next todo of the list.
+func positionNear(tx *gorm.DB, todo, sibling io.Todo, before bool) (float64, error) {
+	for renumbered := false; ; renumbered = true {
+		list, err := siblings(tx, sibling.ParentID, sibling.CategoryID, todo.ID)
+		if err != nil {
+			return 0, err
+		}
+		i := 0
+		for i < len(list) && list[i].ID != sibling.ID {
+			i++
+		}
+		if i == len(list) {
+			return 0, gorm.ErrRecordNotFound
+		}
+		if p, ok := between(list, i, before); ok {
+			return p, nil
+		}
+		if renumbered {
+			return 0, errors.New("no room left between the todos")
+		}
+		if err := renumber(tx, list); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// between returns a position right before or after list[i], halfway to
+// its neighbour, or one past the end of the list. It reports false when
+// the neighbours are too close to fit one in between.
+func between(list []io.Todo, i int, before bool) (float64, bool) {
+	var lo, hi float64
+	switch {
+	case before && i == 0:
+		return list[i].Position - 1, true
+	case before:
+		lo, hi = list[i-1].Position, list[i].Position
+	case i == len(list)-1:
+		return list[i].Position + 1, true
+	default:
+		lo, hi = list[i].Position, list[i+1].Position
+	}
+	p := lo + (hi-lo)/2
+	return p, lo < p && p < hi
+}
+
+// siblings returns the list of todos with parent and category, by
+// position, leaving out the todo except.
+func siblings(tx *gorm.DB, parent, category, except uint) (t []io.Todo, err error) {
+	err = tx.Where("parent_id = ? AND category_id = ? AND id <> ?", parent, category, except).Order(positionOrder).Find(&t).Error
+	return t, err
+}
+
+// renumber spreads the positions of list, in its order, one apart.
+func renumber(tx *gorm.DB, list []io.Todo) error {
+	spread(list)
+	for i := range list {
+		if err := saveTodo(tx, io.TodoMoved, list[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spread sets the positions of list, in its order, one apart from 1.
+func spread(list []io.Todo) {
+	for i := range list {
+		list[i].Position = float64(i + 1)
+	}
+}
+
+// lastPosition returns the position that puts a todo at the end of the
+// list with parent and category.
+func lastPosition(tx *gorm.DB, parent, category uint) (float64, error) {
+	var max sql.NullFloat64
+	err := tx.Model(&io.Todo{}).Where("parent_id = ? AND category_id = ?", parent, category).
+		Select("max(position)").Row().Scan(&max)
+	return max.Float64 + 1, err
+}
+
+// keepPosition returns the position todo is saved with by Update: the one
+// it has, or the end of its new list when it changed parent or category.
+func keepPosition(tx *gorm.DB, todo io.Todo) (float64, error) {
+	stored := io.Todo{}
+	err := tx.Where("id = ?", todo.ID).First(&stored).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return lastPosition(tx, todo.ParentID, todo.CategoryID)
+	}
+	if err != nil || (stored.ParentID == todo.ParentID && stored.CategoryID == todo.CategoryID) {
+		return stored.Position, err
+	}
+	return lastPosition(tx, todo.ParentID, todo.CategoryID)
+}
+
+// byPosition sorts todos like positionOrder does.
+func byPosition(t []io.Todo) {
+	sort.SliceStable(t, func(i, j int) bool {
+		if t[i].Position != t[j].Position {
+			return t[i].Position < t[j].Position
+		}
+		return t[i].ID < t[j].ID
+	})
+}
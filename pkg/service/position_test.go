@@ -0,0 +1,88 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"todo/pkg/io"
+)
+
+// atPositions returns todos with ids 1, 2, ... at positions.
+func atPositions(positions ...float64) []io.Todo {
+	t := make([]io.Todo, len(positions))
+	for i, p := range positions {
+		t[i].ID, t[i].Position = uint(i+1), p
+	}
+	return t
+}
+
+func TestBetween(t *testing.T) {
+	tight := math.Nextafter(1, 2)
+	tests := []struct {
+		name   string
+		list   []io.Todo
+		i      int
+		before bool
+		want   float64
+		ok     bool
+	}{
+		{"before the first", atPositions(1, 2, 3), 0, true, 0, true},
+		{"after the last", atPositions(1, 2, 3), 2, false, 4, true},
+		{"before the middle", atPositions(1, 2, 3), 1, true, 1.5, true},
+		{"after the middle", atPositions(1, 2, 3), 1, false, 2.5, true},
+		{"negative positions", atPositions(-3, -1), 1, true, -2, true},
+		{"fractional positions", atPositions(1, 1.5), 0, false, 1.25, true},
+		{"only todo, before", atPositions(5), 0, true, 4, true},
+		{"only todo, after", atPositions(5), 0, false, 6, true},
+		{"no room before", atPositions(1, tight), 1, true, 0, false},
+		{"no room after", atPositions(1, tight), 0, false, 0, false},
+		{"same positions", atPositions(2, 2), 0, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := between(tt.list, tt.i, tt.before)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("between(%d, %v) = %g, %v, want %g, %v", tt.i, tt.before, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSpread(t *testing.T) {
+	tests := []struct {
+		name string
+		list []io.Todo
+		i    int
+		want float64
+	}{
+		{"crowded", atPositions(1, math.Nextafter(1, 2), math.Nextafter(math.Nextafter(1, 2), 2)), 0, 1.5},
+		{"equal", atPositions(0, 0, 0), 1, 2.5},
+		{"already apart", atPositions(10, 20, 30), 1, 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spread(tt.list)
+			for i, v := range tt.list {
+				if v.Position != float64(i+1) || v.ID != uint(i+1) {
+					t.Fatalf("todo %d at %g after spread, want %d", v.ID, v.Position, i+1)
+				}
+			}
+			got, ok := between(tt.list, tt.i, false)
+			if !ok || got != tt.want {
+				t.Errorf("between after spread = %g, %v, want %g", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestByPosition(t *testing.T) {
+	todos := atPositions(3, 1, 2, 1)
+	byPosition(todos)
+	var ids []uint
+	for _, v := range todos {
+		ids = append(ids, v.ID)
+	}
+	if want := []uint{2, 4, 3, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("byPosition = %v, want %v", ids, want)
+	}
+}
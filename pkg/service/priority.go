@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+	"todo/pkg/io"
+)
+
+// defaultUrgentHours is how close GetMatrix takes a due date to be urgent
+// when not told otherwise.
+const defaultUrgentHours = 48
+
+var errPriority = errors.New("priority value out of range. valid range is 1 to 4, 0 for none")
+
+func (b *basicTodoService) SetPriority(ctx context.Context, id string, priority uint8) (error error) {
+	if priority > 4 {
+		return errPriority
+	}
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	err := session.Where("id = ?", id).Find(&todo).Error
+	if err != nil {
+		return err
+	}
+	todo.Priority = priority
+	tx := session.Begin()
+	return finish(tx, saveTodo(tx, io.TodoPrioritized, todo))
+}
+
+// GetMatrix sorts the open todos into the Eisenhower quadrants, each one
+// by due date, then priority. Todos due within urgentHours, 48 if 0, or
+// overdue are urgent.
+func (b *basicTodoService) GetMatrix(ctx context.Context, urgentHours int) (m io.Matrix, error error) {
+	if urgentHours < 0 {
+		return m, errors.New("urgent hours must not be negative")
+	}
+	if urgentHours == 0 {
+		urgentHours = defaultUrgentHours
+	}
+	session := connect(ctx)
+	defer session.Close()
+	var todos []io.Todo
+	if err := session.Where("NOT complete").Find(&todos).Error; err != nil {
+		return m, err
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		switch {
+		case a.Due != nil && b.Due != nil && !a.Due.Equal(*b.Due):
+			return a.Due.Before(*b.Due)
+		case (a.Due == nil) != (b.Due == nil):
+			return a.Due != nil
+		}
+		return rank(a.Priority) < rank(b.Priority)
+	})
+	m = io.Matrix{DoFirst: []io.Todo{}, Schedule: []io.Todo{}, Delegate: []io.Todo{}, Eliminate: []io.Todo{}}
+	soon := time.Now().Add(time.Duration(urgentHours) * time.Hour)
+	for _, v := range todos {
+		urgent := v.Due != nil && v.Due.Before(soon)
+		important := v.Priority == 1 || v.Priority == 2
+		switch {
+		case urgent && important:
+			m.DoFirst = append(m.DoFirst, v)
+		case important:
+			m.Schedule = append(m.Schedule, v)
+		case urgent:
+			m.Delegate = append(m.Delegate, v)
+		default:
+			m.Eliminate = append(m.Eliminate, v)
+		}
+	}
+	return m, nil
+}
+
+// checkTodo validates the priority and the estimates of a todo about to be
+// saved.
+func checkTodo(todo io.Todo) error {
+	if todo.Priority > 4 {
+		return errPriority
+	}
+	if todo.Estimate < 0 || todo.Points < 0 {
+		return errors.New("estimate and points must not be negative")
+	}
+	return nil
+}
+
+// rank orders priorities from the highest, with none last.
+func rank(priority uint8) uint8 {
+	if priority == 0 {
+		return 5
+	}
+	return priority
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// progress is the percentage of completed subtasks out of total, or 0 or
+// 100 after the todo's own state when it has none.
+func progress(total, completed int, complete bool) int {
+	if total == 0 {
+		if complete {
+			return 100
+		}
+		return 0
+	}
+	return completed * 100 / total
+}
+
+// setProgress fills the Progress of todos, whose subtasks are all found
+// among all.
+func setProgress(todos []io.Todo, all []io.Todo) {
+	children := byParent(all)
+	counts := map[uint][2]int{}
+	for i := range todos {
+		total, completed := rollup(children, todos[i].ID, counts, map[uint]bool{})
+		todos[i].Progress = progress(total, completed, todos[i].Complete)
+	}
+}
+
+// rollup counts the subtasks below id and the completed ones among them,
+// remembering the counts of every subtree in counts.
+func rollup(children map[uint][]io.Todo, id uint, counts map[uint][2]int, seen map[uint]bool) (total, completed int) {
+	if c, ok := counts[id]; ok {
+		return c[0], c[1]
+	}
+	seen[id] = true
+	for _, v := range children[id] {
+		if seen[v.ID] {
+			continue
+		}
+		t, c := rollup(children, v.ID, counts, seen)
+		total += 1 + t
+		completed += c
+		if v.Complete {
+			completed++
+		}
+	}
+	counts[id] = [2]int{total, completed}
+	return total, completed
+}
+
+// withProgress fills the Progress of todo from its subtasks.
+func withProgress(session *gorm.DB, todo *io.Todo) error {
+	all, err := descendants(session, todo.ID)
+	if err != nil {
+		return err
+	}
+	todos := []io.Todo{*todo}
+	setProgress(todos, all)
+	todo.Progress = todos[0].Progress
+	return nil
+}
+
+// propagate completes or reopens the parents of todo, whose Complete just
+// changed, when the service propagates completion.
+func (b *basicTodoService) propagate(tx *gorm.DB, todo io.Todo) error {
+	if !b.config.PropagateCompletion {
+		return nil
+	}
+	f := storedFamily{tx}
+	typ := io.TodoCompleted
+	parents, err := completedParents(f, todo, b.config.EnforceDependencies)
+	if !todo.Complete {
+		typ = io.TodoReopened
+		parents, err = reopenedParents(f, todo)
+	}
+	for i := 0; err == nil && i < len(parents); i++ {
+		var w io.Workflow
+		if w, err = workflowFor(tx, parents[i].CategoryID); err != nil {
+			break
+		}
+		parents[i].Complete, parents[i].Status = todo.Complete, w.Initial()
+		if todo.Complete {
+			parents[i].Status = w.Terminal()
+		}
+		err = saveTodo(tx, typ, parents[i])
+	}
+	return err
+}
+
+// family is what propagating completion reads about the todos above one.
+type family interface {
+	// todo returns the live todo id, reporting whether there is one.
+	todo(id uint) (io.Todo, bool, error)
+	// open counts the open subtasks of the todo id.
+	open(id uint) (int, error)
+	// blocked reports whether the todo id has open blockers.
+	blocked(id uint) (bool, error)
+}
+
+// storedFamily reads the family of a todo in the database.
+type storedFamily struct {
+	tx *gorm.DB
+}
+
+func (f storedFamily) todo(id uint) (io.Todo, bool, error) {
+	t := io.Todo{}
+	err := f.tx.Where("id = ?", id).First(&t).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return t, false, nil
+	}
+	return t, err == nil, err
+}
+
+func (f storedFamily) open(id uint) (n int, err error) {
+	err = f.tx.Model(&io.Todo{}).Where("parent_id = ? AND NOT complete", id).Count(&n).Error
+	return n, err
+}
+
+func (f storedFamily) blocked(id uint) (bool, error) {
+	err := checkBlockers(f.tx, id)
+	if err == ErrBlocked {
+		return true, nil
+	}
+	return false, err
+}
+
+// completedParents walks up from todo, just completed, and returns the
+// parents to complete along with it, the nearest first: every one whose
+// subtasks are then all complete, up to the first that is not, or that has
+// open blockers when enforce is set. Complete parents are walked through.
+func completedParents(f family, todo io.Todo, enforce bool) (parents []io.Todo, err error) {
+	seen := map[uint]bool{todo.ID: true}
+	// completing is set when the child walked up from is being completed
+	// here, and so still counted among the open subtasks of its parent.
+	completing := false
+	for p := todo.ParentID; p != 0 && !seen[p]; {
+		seen[p] = true
+		open, err := f.open(p)
+		if err != nil {
+			return parents, err
+		}
+		if completing {
+			open--
+		}
+		if open > 0 {
+			break
+		}
+		parent, ok, err := f.todo(p)
+		if err != nil || !ok {
+			return parents, err
+		}
+		if enforce {
+			blocked, err := f.blocked(p)
+			if err != nil || blocked {
+				return parents, err
+			}
+		}
+		completing = !parent.Complete
+		if completing {
+			parents = append(parents, parent)
+		}
+		p = parent.ParentID
+	}
+	return parents, nil
+}
+
+// reopenedParents walks up from todo, just reopened, and returns its
+// complete ancestors up to the first open one, the nearest first.
+func reopenedParents(f family, todo io.Todo) (parents []io.Todo, err error) {
+	seen := map[uint]bool{todo.ID: true}
+	for p := todo.ParentID; p != 0 && !seen[p]; {
+		seen[p] = true
+		parent, ok, err := f.todo(p)
+		if err != nil || !ok || !parent.Complete {
+			return parents, err
+		}
+		parents = append(parents, parent)
+		p = parent.ParentID
+	}
+	return parents, nil
+}
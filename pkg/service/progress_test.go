@@ -0,0 +1,142 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"todo/pkg/io"
+)
+
+// fakeFamily holds the live todos by id, and the ones with open blockers.
+type fakeFamily struct {
+	todos  map[uint]io.Todo
+	blocks map[uint]bool
+}
+
+func newFamily(blocked []uint, todos ...io.Todo) fakeFamily {
+	f := fakeFamily{todos: map[uint]io.Todo{}, blocks: map[uint]bool{}}
+	for _, v := range todos {
+		f.todos[v.ID] = v
+	}
+	for _, v := range blocked {
+		f.blocks[v] = true
+	}
+	return f
+}
+
+func (f fakeFamily) todo(id uint) (io.Todo, bool, error) {
+	t, ok := f.todos[id]
+	return t, ok, nil
+}
+
+func (f fakeFamily) open(id uint) (n int, err error) {
+	for _, v := range f.todos {
+		if v.ParentID == id && !v.Complete {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f fakeFamily) blocked(id uint) (bool, error) {
+	return f.blocks[id], nil
+}
+
+func subtask(id, parent uint, complete bool) io.Todo {
+	t := io.Todo{ParentID: parent, Complete: complete}
+	t.ID = id
+	return t
+}
+
+func idsOf(todos []io.Todo) []uint {
+	v := []uint{}
+	for _, t := range todos {
+		v = append(v, t.ID)
+	}
+	return v
+}
+
+func TestCompletedParents(t *testing.T) {
+	// Todo 3 was just completed, below 2, below 1.
+	tests := []struct {
+		name    string
+		others  []io.Todo
+		blocked []uint
+		enforce bool
+		want    []uint
+	}{
+		{"last open subtask", []io.Todo{subtask(1, 0, false), subtask(2, 1, false), subtask(4, 2, true)}, nil, false, []uint{2, 1}},
+		{"open sibling", []io.Todo{subtask(1, 0, false), subtask(2, 1, false), subtask(4, 2, false)}, nil, false, []uint{}},
+		{"open uncle", []io.Todo{subtask(1, 0, false), subtask(2, 1, false), subtask(5, 1, false)}, nil, false, []uint{2}},
+		{"complete parent", []io.Todo{subtask(1, 0, false), subtask(2, 1, true)}, nil, false, []uint{1}},
+		{"all complete", []io.Todo{subtask(1, 0, true), subtask(2, 1, true)}, nil, false, []uint{}},
+		{"blocked parent", []io.Todo{subtask(1, 0, false), subtask(2, 1, false)}, []uint{2}, true, []uint{}},
+		{"blocked parent, not enforced", []io.Todo{subtask(1, 0, false), subtask(2, 1, false)}, []uint{2}, false, []uint{2, 1}},
+		{"blocked grandparent", []io.Todo{subtask(1, 0, false), subtask(2, 1, false)}, []uint{1}, true, []uint{2}},
+		{"trashed parent", []io.Todo{subtask(1, 0, false)}, nil, false, []uint{}},
+		{"parent cycle", []io.Todo{subtask(2, 3, false)}, nil, false, []uint{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := subtask(3, 2, true)
+			f := newFamily(tt.blocked, append(tt.others, todo)...)
+			parents, err := completedParents(f, todo, tt.enforce)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := idsOf(parents); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completedParents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReopenedParents(t *testing.T) {
+	// Todo 3 was just reopened, below 2, below 1.
+	tests := []struct {
+		name   string
+		others []io.Todo
+		want   []uint
+	}{
+		{"complete ancestors", []io.Todo{subtask(1, 0, true), subtask(2, 1, true)}, []uint{2, 1}},
+		{"open grandparent", []io.Todo{subtask(1, 0, false), subtask(2, 1, true)}, []uint{2}},
+		{"open parent", []io.Todo{subtask(1, 0, true), subtask(2, 1, false)}, []uint{}},
+		{"trashed parent", []io.Todo{subtask(1, 0, true)}, []uint{}},
+		{"parent cycle", []io.Todo{subtask(2, 3, true)}, []uint{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := subtask(3, 2, false)
+			parents, err := reopenedParents(newFamily(nil, append(tt.others, todo)...), todo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := idsOf(parents); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reopenedParents = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	all := []io.Todo{
+		subtask(2, 1, true), subtask(3, 1, false),
+		subtask(4, 3, true), subtask(5, 3, true), subtask(6, 5, false),
+	}
+	tests := []struct {
+		todo io.Todo
+		want int
+	}{
+		{subtask(1, 0, false), 60},
+		{subtask(3, 1, false), 66},
+		{subtask(5, 3, true), 0},
+		{subtask(2, 1, true), 100},
+		{subtask(6, 5, false), 0},
+	}
+	for _, tt := range tests {
+		todos := []io.Todo{tt.todo}
+		setProgress(todos, all)
+		if todos[0].Progress != tt.want {
+			t.Errorf("Progress of %d = %d, want %d", tt.todo.ID, todos[0].Progress, tt.want)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"todo/pkg/db"
 	"todo/pkg/io"
 )
 
@@ -16,82 +15,188 @@ type TodoService interface {
 	Add(ctx context.Context, todo io.Todo) (t io.Todo, error error)
 	SetComplete(ctx context.Context, id string) (error error)
 	RemoveComplete(ctx context.Context, id string) (error error)
-	Delete(ctx context.Context, id string) (error error)
+	Delete(ctx context.Context, id string, policy string) (error error)
 	Update(ctx context.Context, todo io.Todo) (t io.Todo, error error)
 	SetStar(ctx context.Context, id string, star uint8) (error error)
 	ReplyTo(ctx context.Context, parentId uint, todo io.Todo) (t io.Todo, error error)
 	GetChildes(ctx context.Context, id string) (t []io.Todo, error error)
+	GetTree(ctx context.Context, id string, maxDepth int) (t io.TodoNode, error error)
+	FindTodos(ctx context.Context, by string, ids []uint) (t []io.Todo, error error)
+	Move(ctx context.Context, id string, parentId uint) (t io.Todo, error error)
+	Reorder(ctx context.Context, id string, before uint, after uint) (t io.Todo, error error)
+	Transition(ctx context.Context, id string, status string) (t io.Todo, error error)
+	SetPriority(ctx context.Context, id string, priority uint8) (error error)
+	GetMatrix(ctx context.Context, urgentHours int) (m io.Matrix, error error)
 
 	// Category methods
 	GetCategory(ctx context.Context) (c []io.TodoCategory, error error)
 	AddCategory(ctx context.Context, category io.TodoCategory) (c io.TodoCategory, error error)
 	UpdateCategory(ctx context.Context, category io.TodoCategory) (c io.TodoCategory, error error)
-	DeleteCategory(ctx context.Context, id string) (error error)
+	DeleteCategory(ctx context.Context, id string, policy string) (error error)
 	GetCatChildes(ctx context.Context, id string) (c []io.TodoCategory, error error)
+	FindCategories(ctx context.Context, by string, ids []uint) (c []io.TodoCategory, error error)
+	MoveCategory(ctx context.Context, id string, parentId uint) (c io.TodoCategory, error error)
+	GetCategoryTree(ctx context.Context) (c []io.CategoryNode, error error)
+	GetCategoryPath(ctx context.Context, id string) (c []io.TodoCategory, error error)
+	GetWorkflow(ctx context.Context, id string) (w io.Workflow, error error)
+	SetWorkflow(ctx context.Context, id string, workflow io.Workflow) (w io.Workflow, error error)
+	GetBoard(ctx context.Context, id string) (b io.Board, error error)
+
+	// Webhook methods
+	AddWebhook(ctx context.Context, webhook io.Webhook) (w io.Webhook, error error)
+	GetWebhooks(ctx context.Context) (w []io.Webhook, error error)
+	DeleteWebhook(ctx context.Context, id string) (error error)
+	GetWebhookDeliveries(ctx context.Context, id string) (d []io.WebhookDelivery, error error)
+	GetDeadLetters(ctx context.Context) (d []io.WebhookDelivery, error error)
+	RetryDelivery(ctx context.Context, id string) (d io.WebhookDelivery, error error)
+
+	// Trash methods
+	ListTrash(ctx context.Context) (t io.Trash, error error)
+	Restore(ctx context.Context, kind string, id string, subtree bool) (error error)
+	Purge(ctx context.Context, kind string, id string, subtree bool) (error error)
+
+	// Dependency methods
+	AddDependency(ctx context.Context, id string, blockerId uint) (d io.Dependency, error error)
+	RemoveDependency(ctx context.Context, id string, blockerId uint) (error error)
+	GetBlocked(ctx context.Context) (t []io.Todo, error error)
+	GetReady(ctx context.Context) (t []io.Todo, error error)
+	GetDependencyGraph(ctx context.Context) (g io.DependencyGraph, error error)
+
+	// Planning methods
+	GetCategoryPlan(ctx context.Context, id string) (p io.Plan, error error)
+	GetTreePlan(ctx context.Context, id string) (p io.Plan, error error)
+
+	// Time tracking methods
+	StartTimer(ctx context.Context, id string) (te io.TimeEntry, error error)
+	StopTimer(ctx context.Context) (te io.TimeEntry, error error)
+	AddTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error)
+	GetTimeEntries(ctx context.Context, id string) (te []io.TimeEntry, error error)
+	UpdateTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error)
+	DeleteTimeEntry(ctx context.Context, id string) (error error)
+	GetTimeReport(ctx context.Context, from string, to string, user string) (r io.TimeReport, error error)
+
+	// Reporting methods
+	GetBurndown(ctx context.Context, id string, from string, to string, unit string) (bd io.Burndown, error error)
+
+	// History methods
+	GetTodoHistory(ctx context.Context, id string) (h []io.Change, error error)
+	GetCategoryHistory(ctx context.Context, id string) (h []io.Change, error error)
+	Undo(ctx context.Context, steps int) (h []io.Change, error error)
+	Redo(ctx context.Context, steps int) (h []io.Change, error error)
 }
 
-type basicTodoService struct{}
+// Config tunes the behaviour of the basic service.
+type Config struct {
+	// PropagateCompletion completes a todo once all of its subtasks are,
+	// and reopens it when one of them is reopened.
+	PropagateCompletion bool
+	// EnforceDependencies refuses to complete a todo, with ErrBlocked,
+	// while it has open blockers.
+	EnforceDependencies bool
+}
+
+type basicTodoService struct {
+	config Config
+}
 
 func (b *basicTodoService) Get(ctx context.Context) (t []io.Todo, error error) {
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Find(&t).Error
+	if at, ok := asOf(ctx); ok {
+		t, error = todosAsOf(session, at)
+	} else {
+		error = session.Order(positionOrder).Find(&t).Error
+	}
+	setProgress(t, t)
 	return t, error
 }
 func (b *basicTodoService) Add(ctx context.Context, todo io.Todo) (t io.Todo, error error) {
-	session := db.ConnectPGDB()
+	if err := checkTodo(todo); err != nil {
+		return t, err
+	}
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Create(&todo).Error
-	return todo, error
+	tx := session.Begin()
+	todo.Position, error = lastPosition(tx, todo.ParentID, todo.CategoryID)
+	if error == nil {
+		error = newStatus(tx, &todo)
+	}
+	if error == nil {
+		error = tx.Create(&todo).Error
+	}
+	if error == nil {
+		error = recordTodo(tx, io.TodoCreated, todo)
+	}
+	todo.Progress = progress(0, 0, todo.Complete)
+	return todo, finish(tx, error)
 }
+// SetComplete moves the todo id to the last status of its workflow,
+// whatever the transitions it allows.
 func (b *basicTodoService) SetComplete(ctx context.Context, id string) (error error) {
-	session := db.ConnectPGDB()
-	defer session.Close()
-	todo := io.Todo{}
-	err := session.Where("id = ?", id).Find(&todo).Error
-	if err != nil {
-		return err
-	}
-	todo.Complete = true
-	return session.Save(&todo).Error
+	_, error = b.transition(ctx, id, terminal)
+	return error
 }
+
+// RemoveComplete moves the todo id back to the first status of its
+// workflow.
 func (b *basicTodoService) RemoveComplete(ctx context.Context, id string) (error error) {
-	session := db.ConnectPGDB()
-	defer session.Close()
-	todo := io.Todo{}
-	err := session.Where("id = ?", id).Find(&todo).Error
-	if err != nil {
-		return err
-	}
-	todo.Complete = false
-	return session.Save(&todo).Error
+	_, error = b.transition(ctx, id, initial)
+	return error
 }
-func (b *basicTodoService) Delete(ctx context.Context, id string) (error error) {
-	session := db.ConnectPGDB()
+func (b *basicTodoService) Delete(ctx context.Context, id string, policy string) (error error) {
+	session := connect(ctx)
 	defer session.Close()
 	todo := io.Todo{}
 	err := session.Where("id = ?", id).Find(&todo).Error
 	if err != nil {
 		return err
 	}
-	return session.Delete(&todo).Error
+	tx := session.Begin()
+	return finish(tx, deleteTodo(tx, todo, policy))
 }
 
 func (b *basicTodoService) Update(ctx context.Context, todo io.Todo) (t io.Todo, error error) {
-	session := db.ConnectPGDB()
+	if err := checkTodo(todo); err != nil {
+		return t, err
+	}
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Save(&todo).Error
+	tx := session.Begin()
+	error = checkParent(tx, "todos", todo.ID, todo.ParentID)
+	if error == nil {
+		todo.Position, error = keepPosition(tx, todo)
+	}
+	var toggled bool
+	if error == nil {
+		todo.Status, toggled, error = keepStatus(tx, todo)
+	}
+	// Completing through Update is held to the same rules as SetComplete.
+	if error == nil && toggled && todo.Complete && b.config.EnforceDependencies {
+		error = checkBlockers(tx, todo.ID)
+	}
+	if error == nil {
+		error = tx.Save(&todo).Error
+	}
+	if error == nil {
+		error = recordTodo(tx, io.TodoUpdated, todo)
+	}
+	if error == nil && toggled {
+		error = b.propagate(tx, todo)
+	}
+	if error = finish(tx, error); error == nil {
+		error = withProgress(session, &todo)
+	}
 	return todo, error
 }
 
 // NewBasicTodoService returns a naive, stateless implementation of TodoService.
-func NewBasicTodoService() TodoService {
-	return &basicTodoService{}
+func NewBasicTodoService(config Config) TodoService {
+	return &basicTodoService{config: config}
 }
 
 // New returns a TodoService with all of the expected middleware wired in.
-func New(middleware []Middleware) TodoService {
-	var svc TodoService = NewBasicTodoService()
+func New(middleware []Middleware, config Config) TodoService {
+	var svc TodoService = NewBasicTodoService(config)
 	for _, m := range middleware {
 		svc = m(svc)
 	}
@@ -99,7 +204,7 @@ func New(middleware []Middleware) TodoService {
 }
 
 func (b *basicTodoService) SetStar(ctx context.Context, id string, star uint8) (error error) {
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
 	todo := io.Todo{}
 	err := session.Where("id = ?", id).Find(&todo).Error
@@ -110,59 +215,109 @@ func (b *basicTodoService) SetStar(ctx context.Context, id string, star uint8) (
 		return errors.New("star value out of range. valid range is 0 to 5")
 	}
 	todo.Star = star
-	return session.Save(&todo).Error
+	tx := session.Begin()
+	err = tx.Save(&todo).Error
+	if err == nil {
+		err = recordTodo(tx, io.TodoStarred, todo)
+	}
+	return finish(tx, err)
 }
 
 func (b *basicTodoService) ReplyTo(ctx context.Context, parentId uint, todo io.Todo) (t io.Todo, error error) {
-	session := db.ConnectPGDB()
+	if err := checkTodo(todo); err != nil {
+		return t, err
+	}
+	session := connect(ctx)
 	defer session.Close()
 	todo.ParentID = parentId
-	error = session.Create(&todo).Error
-	return todo, error
+	tx := session.Begin()
+	todo.Position, error = lastPosition(tx, todo.ParentID, todo.CategoryID)
+	if error == nil {
+		error = newStatus(tx, &todo)
+	}
+	if error == nil {
+		error = tx.Create(&todo).Error
+	}
+	if error == nil {
+		error = recordTodo(tx, io.TodoCreated, todo)
+	}
+	todo.Progress = progress(0, 0, todo.Complete)
+	return todo, finish(tx, error)
 }
 
 func (b *basicTodoService) GetChildes(ctx context.Context, id string) (t []io.Todo, error error) {
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Where("parent_id = ?", id).Find(&t).Error
+	if at, ok := asOf(ctx); ok {
+		var all []io.Todo
+		all, error = todosAsOf(session, at)
+		t = childrenOf(all, id)
+		setProgress(t, all)
+		return t, error
+	}
+	error = session.Where("parent_id = ?", id).Order(positionOrder).Find(&t).Error
+	if error == nil && len(t) > 0 {
+		var all []io.Todo
+		all, error = descendants(session, t[0].ParentID)
+		setProgress(t, all)
+	}
 	return t, error
 }
 
 func (b *basicTodoService) AddCategory(ctx context.Context, category io.TodoCategory) (c io.TodoCategory, error error) {
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Create(&category).Error
-	return category, error
+	tx := session.Begin()
+	error = tx.Create(&category).Error
+	if error == nil {
+		error = recordCategory(tx, io.CategoryCreated, category)
+	}
+	return category, finish(tx, error)
 }
 
 func (b *basicTodoService) GetCategory(ctx context.Context) (c []io.TodoCategory, error error) {
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
+	if at, ok := asOf(ctx); ok {
+		return categoriesAsOf(session, at)
+	}
 	error = session.Find(&c).Error
 	return c, error
 }
 func (b *basicTodoService) UpdateCategory(ctx context.Context, category io.TodoCategory) (c io.TodoCategory, error error) {
 	// TODO implement the business logic of UpdateCategory
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
-	error = session.Save(&category).Error
-	return category, error
+	tx := session.Begin()
+	error = checkParent(tx, "todo_categories", category.ID, category.ParentID)
+	if error == nil {
+		error = tx.Save(&category).Error
+	}
+	if error == nil {
+		error = recordCategory(tx, io.CategoryUpdated, category)
+	}
+	return category, finish(tx, error)
 }
-func (b *basicTodoService) DeleteCategory(ctx context.Context, id string) (error error) {
-	// TODO implement the business logic of DeleteCategory
-	session := db.ConnectPGDB()
+func (b *basicTodoService) DeleteCategory(ctx context.Context, id string, policy string) (error error) {
+	session := connect(ctx)
 	defer session.Close()
 	category := io.TodoCategory{}
 	err := session.Where("id = ?", id).Find(&category).Error
 	if err != nil {
 		return err
 	}
-	return session.Delete(&category).Error
+	tx := session.Begin()
+	return finish(tx, deleteCategory(tx, category, policy))
 }
 
 func (b *basicTodoService) GetCatChildes(ctx context.Context, id string) (c []io.TodoCategory, error error) {
 	// TODO implement the business logic of GetCatChildes
-	session := db.ConnectPGDB()
+	session := connect(ctx)
 	defer session.Close()
+	if at, ok := asOf(ctx); ok {
+		var all []io.TodoCategory
+		all, error = categoriesAsOf(session, at)
+		return subCategoriesOf(all, id), error
+	}
 	error = session.Where("parent_id = ?", id).Find(&c).Error
 	return c, error}
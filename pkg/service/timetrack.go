@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// ErrTimerRunning is returned when starting a timer for a user who already
+// has one running.
+var ErrTimerRunning = errors.New("a timer is already running")
+
+// errNoUser is returned when time is tracked without an actor, whose
+// entries they would be.
+var errNoUser = errors.New("time is tracked for the actor of the request, and there is none")
+
+// runningTimerIndex is the unique index allowing one running timer per
+// user.
+const runningTimerIndex = "idx_running_timer"
+
+// StartTimer starts a timer for the actor of ctx on the todo id, unless one
+// of theirs is already running.
+func (b *basicTodoService) StartTimer(ctx context.Context, id string) (te io.TimeEntry, error error) {
+	user := actor(ctx)
+	if user == "" {
+		return te, errNoUser
+	}
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	if err := session.Where("id = ?", id).First(&todo).Error; err != nil {
+		return te, err
+	}
+	tx := session.Begin()
+	running, error := runningTimer(tx, user)
+	switch {
+	case error == nil:
+		error = fmt.Errorf("%w on todo %d", ErrTimerRunning, running.TodoID)
+	case gorm.IsRecordNotFoundError(error):
+		te = io.TimeEntry{TodoID: todo.ID, User: user, StartedAt: time.Now()}
+		error = tx.Create(&te).Error
+		// A concurrent StartTimer won the race to the index.
+		if isUniqueViolation(error, runningTimerIndex) {
+			error = ErrTimerRunning
+		}
+		if error == nil {
+			error = recordTimeEntry(tx, io.TimeEntryCreated, te)
+		}
+	}
+	return withHours(te), finish(tx, error)
+}
+
+// StopTimer stops the running timer of the actor of ctx.
+func (b *basicTodoService) StopTimer(ctx context.Context) (te io.TimeEntry, error error) {
+	user := actor(ctx)
+	if user == "" {
+		return te, errNoUser
+	}
+	session := connect(ctx)
+	defer session.Close()
+	te, error = runningTimer(session, user)
+	if error != nil {
+		return te, error
+	}
+	now := time.Now()
+	te.EndedAt = &now
+	tx := session.Begin()
+	error = tx.Save(&te).Error
+	if error == nil {
+		error = recordTimeEntry(tx, io.TimeEntryUpdated, te)
+	}
+	return withHours(te), finish(tx, error)
+}
+
+// AddTimeEntry logs time of the actor of ctx on the todo id after the
+// fact. The entry needs a start and an end.
+func (b *basicTodoService) AddTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	if entry.User = actor(ctx); entry.User == "" {
+		return te, errNoUser
+	}
+	if err := checkTimeEntry(entry, false); err != nil {
+		return te, err
+	}
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	if err := session.Where("id = ?", id).First(&todo).Error; err != nil {
+		return te, err
+	}
+	te = io.TimeEntry{TodoID: todo.ID, User: entry.User, Note: entry.Note, StartedAt: entry.StartedAt, EndedAt: entry.EndedAt}
+	tx := session.Begin()
+	error = tx.Create(&te).Error
+	if error == nil {
+		error = recordTimeEntry(tx, io.TimeEntryCreated, te)
+	}
+	return withHours(te), finish(tx, error)
+}
+
+// GetTimeEntries returns the time logged on the todo id, the oldest first,
+// running timer included.
+func (b *basicTodoService) GetTimeEntries(ctx context.Context, id string) (te []io.TimeEntry, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	todo := io.Todo{}
+	if err := session.Unscoped().Where("id = ?", id).First(&todo).Error; err != nil {
+		return te, err
+	}
+	error = session.Where("todo_id = ?", todo.ID).Order("started_at, id").Find(&te).Error
+	for i := range te {
+		te[i] = withHours(te[i])
+	}
+	return te, error
+}
+
+// UpdateTimeEntry rewrites the entry id of the actor of ctx, moving it to
+// the todo entry.TodoID when set. The entry keeps its user, and a running
+// timer can only be given an end, stopping it.
+func (b *basicTodoService) UpdateTimeEntry(ctx context.Context, id string, entry io.TimeEntry) (te io.TimeEntry, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	if te, error = ownEntry(ctx, session, id); error != nil {
+		return te, error
+	}
+	running := te.EndedAt == nil
+	entry.User = te.User
+	if err := checkTimeEntry(entry, running); err != nil {
+		return te, err
+	}
+	if entry.TodoID != 0 && entry.TodoID != te.TodoID {
+		if err := session.Where("id = ?", entry.TodoID).First(&io.Todo{}).Error; err != nil {
+			return te, err
+		}
+		te.TodoID = entry.TodoID
+	}
+	te.User, te.Note, te.StartedAt, te.EndedAt = entry.User, entry.Note, entry.StartedAt, entry.EndedAt
+	tx := session.Begin()
+	error = tx.Save(&te).Error
+	if error == nil {
+		error = recordTimeEntry(tx, io.TimeEntryUpdated, te)
+	}
+	return withHours(te), finish(tx, error)
+}
+
+// DeleteTimeEntry drops the entry id of the actor of ctx.
+func (b *basicTodoService) DeleteTimeEntry(ctx context.Context, id string) (error error) {
+	session := connect(ctx)
+	defer session.Close()
+	te, err := ownEntry(ctx, session, id)
+	if err != nil {
+		return err
+	}
+	tx := session.Begin()
+	error = tx.Delete(&te).Error
+	if error == nil {
+		error = recordTimeEntry(tx, io.TimeEntryDeleted, te)
+	}
+	return finish(tx, error)
+}
+
+// ownEntry loads the time entry id of the actor of ctx. The entries of
+// other users are not found.
+func ownEntry(ctx context.Context, session *gorm.DB, id string) (te io.TimeEntry, err error) {
+	user := actor(ctx)
+	if user == "" {
+		return te, errNoUser
+	}
+	err = session.Where("id = ? AND user_name = ?", id, user).First(&te).Error
+	return te, err
+}
+
+// GetTimeReport sums the time logged between from and to, by user or by
+// everyone when empty, per todo and per category, sub categories
+// included. Either bound may be empty; a day bound is midnight local time,
+// so to is the day after the last one reported.
+func (b *basicTodoService) GetTimeReport(ctx context.Context, from string, to string, user string) (r io.TimeReport, error error) {
+	if r.From, error = parseBound(from); error != nil {
+		return r, fmt.Errorf("from: %v", error)
+	}
+	if r.To, error = parseBound(to); error != nil {
+		return r, fmt.Errorf("to: %v", error)
+	}
+	if r.From != nil && r.To != nil && !r.To.After(*r.From) {
+		return r, errors.New("to must be after from")
+	}
+	r.User = user
+	session := connect(ctx)
+	defer session.Close()
+	query := session.Order("todo_id, started_at")
+	if r.From != nil {
+		query = query.Where("ended_at IS NULL OR ended_at > ?", *r.From)
+	}
+	if r.To != nil {
+		query = query.Where("started_at < ?", *r.To)
+	}
+	if user != "" {
+		query = query.Where("user_name = ?", user)
+	}
+	var entries []io.TimeEntry
+	if err := query.Find(&entries).Error; err != nil {
+		return r, err
+	}
+	now := time.Now()
+	hours := map[uint]float64{}
+	ids := []uint{}
+	for _, v := range entries {
+		if r.From != nil && v.StartedAt.Before(*r.From) {
+			v.StartedAt = *r.From
+		}
+		if r.To != nil && (v.EndedAt == nil || v.EndedAt.After(*r.To)) {
+			v.EndedAt = r.To
+		}
+		if _, ok := hours[v.TodoID]; !ok {
+			ids = append(ids, v.TodoID)
+		}
+		hours[v.TodoID] += v.Duration(now).Hours()
+	}
+	// Deleted todos still count: the time was spent.
+	var todos []io.Todo
+	if len(ids) > 0 {
+		if err := session.Unscoped().Where("id in (?)", ids).Order("id").Find(&todos).Error; err != nil {
+			return r, err
+		}
+	}
+	r.Todos, r.Categories = []io.TodoTime{}, []io.CategoryTime{}
+	own := map[uint]float64{}
+	for _, v := range todos {
+		r.Todos = append(r.Todos, io.TodoTime{TodoID: v.ID, Title: v.Title, Hours: hours[v.ID]})
+		r.Hours += hours[v.ID]
+		own[v.CategoryID] += hours[v.ID]
+	}
+	if own[0] > 0 {
+		r.Categories = append(r.Categories, io.CategoryTime{Hours: own[0]})
+	}
+	// The report is about the categories as they are now.
+	tree, err := b.GetCategoryTree(withoutAsOf(ctx))
+	if err != nil {
+		return r, err
+	}
+	times, _ := categoryTimes(tree, own)
+	r.Categories = append(r.Categories, times...)
+	return r, nil
+}
+
+// categoryTimes lists the nodes depth first with the hours logged in each
+// one's subtree, leaving out the ones without any, and returns the hours
+// of all the nodes.
+func categoryTimes(nodes []io.CategoryNode, own map[uint]float64) (c []io.CategoryTime, total float64) {
+	for _, n := range nodes {
+		below, hours := categoryTimes(n.Children, own)
+		hours += own[n.ID]
+		if hours > 0 {
+			c = append(c, io.CategoryTime{CategoryID: n.ID, Path: n.Path, Hours: hours})
+			c = append(c, below...)
+		}
+		total += hours
+	}
+	return c, total
+}
+
+func runningTimer(tx *gorm.DB, user string) (e io.TimeEntry, err error) {
+	err = tx.Where("user_name = ? AND ended_at IS NULL", user).First(&e).Error
+	return e, err
+}
+
+// isUniqueViolation tells whether err is the violation of the unique
+// index.
+func isUniqueViolation(err error, index string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == index
+}
+
+// checkTimeEntry validates an entry about to be saved, which may lack an
+// end only if it is a running timer.
+func checkTimeEntry(e io.TimeEntry, running bool) error {
+	switch {
+	case e.User == "":
+		return errors.New("a time entry needs a user")
+	case e.StartedAt.IsZero():
+		return errors.New("a time entry needs a start")
+	case e.EndedAt == nil && !running:
+		return errors.New("a time entry needs an end, or start a timer instead")
+	case e.EndedAt != nil && !e.EndedAt.After(e.StartedAt):
+		return errors.New("a time entry must end after it starts")
+	case e.StartedAt.After(time.Now()):
+		return errors.New("a time entry can not start in the future")
+	}
+	return nil
+}
+
+func withHours(e io.TimeEntry) io.TimeEntry {
+	e.Hours = e.Duration(time.Now()).Hours()
+	return e
+}
+
+// parseBound reads a report bound as a day, midnight local time, or an
+// RFC 3339 time. Empty means no bound.
+func parseBound(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, s)
+	}
+	return &t, err
+}
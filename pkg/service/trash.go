@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrParentTrashed is returned when restoring an item whose parent, or
+// whose category, is still in the trash.
+var ErrParentTrashed = errors.New("the parent is in the trash, restore it first")
+
+// ListTrash returns the soft deleted todos and categories.
+func (b *basicTodoService) ListTrash(ctx context.Context) (t io.Trash, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&t.Todos).Error
+	if error == nil {
+		error = session.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&t.Categories).Error
+	}
+	return t, error
+}
+
+// Restore takes the todo or category id out of the trash, along with the
+// trashed items below it when subtree is set. An item whose parent was
+// purged comes back at the top level.
+func (b *basicTodoService) Restore(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	if kind != io.TrashTodo && kind != io.TrashCategory {
+		return fmt.Errorf("unknown kind %q, want todo or category", kind)
+	}
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	if kind == io.TrashTodo {
+		error = restoreTodos(tx, id, subtree)
+	} else {
+		error = restoreCategory(tx, id, subtree)
+	}
+	return finish(tx, error)
+}
+
+// Purge deletes the todo or category id for good, along with the trashed
+// items below it when subtree is set. Only items in the trash can be
+// purged, and no event is recorded: their deletion already was. The
+// history keeps the purge, which can not be undone.
+func (b *basicTodoService) Purge(ctx context.Context, kind string, id string, subtree bool) (error error) {
+	if kind != io.TrashTodo && kind != io.TrashCategory {
+		return fmt.Errorf("unknown kind %q, want todo or category", kind)
+	}
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	var categories []io.TodoCategory
+	var todos []io.Todo
+	if kind == io.TrashTodo {
+		todos, error = trashedTodos(tx, id, subtree)
+	} else {
+		categories, todos, error = trashedCategory(tx, id, subtree)
+	}
+	if error == nil {
+		error = purge(tx, categories, todos)
+	}
+	return finish(tx, error)
+}
+
+// PurgeTrash deletes for good, in one transaction, the todos and categories
+// that went to the trash before cutoff, recording their purge as Purge
+// does, and returns how many there were.
+func PurgeTrash(ctx context.Context, cutoff time.Time) (int64, error) {
+	session := connect(ctx)
+	defer session.Close()
+	tx := session.Begin()
+	var categories []io.TodoCategory
+	var todos []io.Todo
+	err := tx.Unscoped().Where("deleted_at < ?", cutoff).Find(&todos).Error
+	if err == nil {
+		err = tx.Unscoped().Where("deleted_at < ?", cutoff).Find(&categories).Error
+	}
+	if err == nil {
+		err = purge(tx, categories, todos)
+	}
+	if err = finish(tx, err); err != nil {
+		return 0, err
+	}
+	return int64(len(todos) + len(categories)), nil
+}
+
+// purge deletes categories and todos for good, with the dependencies, time
+// entries and completion history of the todos, and records their purge.
+func purge(tx *gorm.DB, categories []io.TodoCategory, todos []io.Todo) (err error) {
+	for i := 0; err == nil && i < len(todos); i++ {
+		err = tx.Unscoped().Delete(&todos[i]).Error
+		if err == nil {
+			err = recordChange(tx, io.TrashTodo, todos[i].ID, io.TodoPurged, nil)
+		}
+		if err == nil {
+			err = tx.Where("todo_id = ? OR blocker_id = ?", todos[i].ID, todos[i].ID).Delete(io.Dependency{}).Error
+		}
+		if err == nil {
+			err = tx.Where("todo_id = ?", todos[i].ID).Delete(io.TimeEntry{}).Error
+		}
+		if err == nil {
+			err = tx.Where("todo_id = ?", todos[i].ID).Delete(io.Completion{}).Error
+		}
+	}
+	for i := 0; err == nil && i < len(categories); i++ {
+		err = tx.Unscoped().Delete(&categories[i]).Error
+		if err == nil {
+			err = recordChange(tx, io.TrashCategory, categories[i].ID, io.CategoryPurged, nil)
+		}
+	}
+	return err
+}
+
+func restoreTodos(tx *gorm.DB, id string, subtree bool) error {
+	todos, err := trashedTodos(tx, id, subtree)
+	if err != nil {
+		return err
+	}
+	if todos[0].ParentID, err = restoredParent(tx, "todos", todos[0].ParentID); err != nil {
+		return err
+	}
+	return untrashTodos(tx, todos)
+}
+
+func restoreCategory(tx *gorm.DB, id string, subtree bool) error {
+	categories, todos, err := trashedCategory(tx, id, subtree)
+	if err != nil {
+		return err
+	}
+	if categories[0].ParentID, err = restoredParent(tx, "todo_categories", categories[0].ParentID); err != nil {
+		return err
+	}
+	for _, v := range categories {
+		v.DeletedAt = nil
+		if err := tx.Unscoped().Save(&v).Error; err != nil {
+			return err
+		}
+		if err := recordCategory(tx, io.CategoryRestored, v); err != nil {
+			return err
+		}
+	}
+	return untrashTodos(tx, todos)
+}
+
+// untrashTodos restores todos, each in its category unless that one is
+// still in the trash or was purged.
+func untrashTodos(tx *gorm.DB, todos []io.Todo) error {
+	ids := []uint{}
+	for _, v := range todos {
+		ids = append(ids, v.CategoryID)
+	}
+	var stored []io.TodoCategory
+	if err := tx.Unscoped().Where("id IN (?)", ids).Find(&stored).Error; err != nil {
+		return err
+	}
+	categories := map[uint]*time.Time{}
+	for _, v := range stored {
+		categories[v.ID] = v.DeletedAt
+	}
+	if err := restoreCategories(todos, categories); err != nil {
+		return err
+	}
+	for _, v := range todos {
+		v.DeletedAt = nil
+		if err := tx.Unscoped().Save(&v).Error; err != nil {
+			return err
+		}
+		if err := recordTodo(tx, io.TodoRestored, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashedTodos returns the trashed todo id, first, followed by its trashed
+// subtasks when subtree is set.
+func trashedTodos(tx *gorm.DB, id string, subtree bool) ([]io.Todo, error) {
+	todo := io.Todo{}
+	if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&todo).Error; err != nil {
+		return nil, err
+	}
+	todos := []io.Todo{todo}
+	if !subtree {
+		return todos, nil
+	}
+	var below []io.Todo
+	if err := findDescendants(tx, "todos", trashed, todo.ID, &below); err != nil {
+		return nil, err
+	}
+	return append(todos, below...), nil
+}
+
+// trashedCategory returns the trashed category id, first, and with
+// subtree its trashed sub categories and the trashed todos, and their
+// trashed subtasks, of all of them.
+func trashedCategory(tx *gorm.DB, id string, subtree bool) ([]io.TodoCategory, []io.Todo, error) {
+	category := io.TodoCategory{}
+	if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&category).Error; err != nil {
+		return nil, nil, err
+	}
+	categories := []io.TodoCategory{category}
+	if !subtree {
+		return categories, nil, nil
+	}
+	var below []io.TodoCategory
+	if err := findDescendants(tx, "todo_categories", trashed, category.ID, &below); err != nil {
+		return nil, nil, err
+	}
+	categories = append(categories, below...)
+	ids := []uint{}
+	for _, v := range categories {
+		ids = append(ids, v.ID)
+	}
+	var roots []io.Todo
+	err := tx.Unscoped().Where("category_id in (?) AND deleted_at IS NOT NULL", ids).Find(&roots).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	var todos []io.Todo
+	seen := map[uint]bool{}
+	for _, t := range roots {
+		var below []io.Todo
+		if err := findDescendants(tx, "todos", trashed, t.ID, &below); err != nil {
+			return nil, nil, err
+		}
+		for _, v := range append([]io.Todo{t}, below...) {
+			if !seen[v.ID] {
+				seen[v.ID] = true
+				todos = append(todos, v)
+			}
+		}
+	}
+	return categories, todos, nil
+}
+
+// restoredParent checks the parent id, 0 for none, of an item leaving the
+// trash and returns the one to restore it with: 0 if it was purged.
+func restoredParent(tx *gorm.DB, table string, id uint) (uint, error) {
+	if id == 0 {
+		return 0, nil
+	}
+	var row struct{ DeletedAt *time.Time }
+	err := tx.Table(table).Select("deleted_at").Where("id = ?", id).Scan(&row).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return restoreUnder(id, false, nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return restoreUnder(id, true, row.DeletedAt)
+}
+
+// restoreUnder returns the parent to restore an item under from its parent
+// id, whether that was found and when it was trashed: id while it is live,
+// 0 once it is purged or for none, and ErrParentTrashed while it is in the
+// trash.
+func restoreUnder(id uint, found bool, deletedAt *time.Time) (uint, error) {
+	switch {
+	case id == 0 || !found:
+		return 0, nil
+	case deletedAt != nil:
+		return 0, ErrParentTrashed
+	}
+	return id, nil
+}
+
+// restoreCategories sets the category of todos leaving the trash through
+// restoreUnder, from the deletion time of the categories found, by id.
+func restoreCategories(todos []io.Todo, categories map[uint]*time.Time) (err error) {
+	for i := 0; err == nil && i < len(todos); i++ {
+		deletedAt, found := categories[todos[i].CategoryID]
+		todos[i].CategoryID, err = restoreUnder(todos[i].CategoryID, found, deletedAt)
+	}
+	return err
+}
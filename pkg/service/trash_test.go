@@ -0,0 +1,71 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"todo/pkg/io"
+)
+
+func TestRestoreUnder(t *testing.T) {
+	trashedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		id        uint
+		found     bool
+		deletedAt *time.Time
+		want      uint
+		wantErr   error
+	}{
+		{"top level", 0, false, nil, 0, nil},
+		{"live parent", 3, true, nil, 3, nil},
+		{"trashed parent", 3, true, &trashedAt, 0, ErrParentTrashed},
+		{"purged parent", 3, false, nil, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := restoreUnder(tt.id, tt.found, tt.deletedAt)
+			if got != tt.want || err != tt.wantErr {
+				t.Errorf("restoreUnder(%d) = %d, %v, want %d, %v", tt.id, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	// A subtree's todos filed under other categories each go through it.
+	filed := func(id, category uint) io.Todo {
+		v := io.Todo{CategoryID: category}
+		v.ID = id
+		return v
+	}
+	categories := map[uint]*time.Time{1: nil, 2: nil, 3: &trashedAt}
+	subtrees := []struct {
+		name    string
+		todos   []io.Todo
+		want    []uint
+		wantErr error
+	}{
+		{"same category", []io.Todo{filed(10, 1), filed(11, 1)}, []uint{1, 1}, nil},
+		{"other live category", []io.Todo{filed(10, 1), filed(11, 2)}, []uint{1, 2}, nil},
+		{"no category", []io.Todo{filed(10, 1), filed(11, 0)}, []uint{1, 0}, nil},
+		{"purged category", []io.Todo{filed(10, 1), filed(11, 9)}, []uint{1, 0}, nil},
+		{"trashed category", []io.Todo{filed(10, 1), filed(11, 3)}, nil, ErrParentTrashed},
+	}
+	for _, tt := range subtrees {
+		t.Run(tt.name, func(t *testing.T) {
+			err := restoreCategories(tt.todos, categories)
+			if err != tt.wantErr {
+				t.Fatalf("restoreCategories = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got := []uint{}
+			for _, v := range tt.todos {
+				got = append(got, v.CategoryID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("categories = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// descendantsQuery walks the children of todos or categories, below the
+// table's parent_id, in one statement, through the rows whose deleted_at
+// matches the condition. UNION rather than UNION ALL stops at rows already
+// seen, should parent ids ever form a cycle.
+const descendantsQuery = `WITH RECURSIVE tree AS (
+	SELECT * FROM %[1]s WHERE parent_id IN (?) AND deleted_at %[2]s
+	UNION
+	SELECT %[1]s.* FROM %[1]s JOIN tree ON %[1]s.parent_id = tree.id WHERE %[1]s.deleted_at %[2]s
+) SELECT * FROM tree`
+
+const (
+	live    = "IS NULL"
+	trashed = "IS NOT NULL"
+)
+
+// GetTree returns the todo id with its subtasks nested maxDepth levels
+// deep, 0 meaning all of them.
+func (b *basicTodoService) GetTree(ctx context.Context, id string, maxDepth int) (t io.TodoNode, error error) {
+	if maxDepth < 0 {
+		return t, errors.New("max depth must not be negative")
+	}
+	session := connect(ctx)
+	defer session.Close()
+	root := io.Todo{}
+	var todos []io.Todo
+	if at, ok := asOf(ctx); ok {
+		if todos, error = todosAsOf(session, at); error == nil {
+			root, error = findTodo(todos, id)
+		}
+	} else if error = session.Where("id = ?", id).First(&root).Error; error == nil {
+		todos, error = descendants(session, root.ID)
+	}
+	if error != nil {
+		return t, error
+	}
+	t = io.TodoNode{Todo: root}
+	t.Children, t.Total, t.Completed = nest(byParent(todos), root.ID, 1, maxDepth, map[uint]bool{root.ID: true})
+	t.Progress = progress(t.Total, t.Completed, root.Complete)
+	return t, nil
+}
+
+// Nest arranges todos into the trees rooted at the children of parentID,
+// like GetTree does.
+func Nest(todos []io.Todo, parentID uint, maxDepth int) []io.TodoNode {
+	nodes, _, _ := nest(byParent(todos), parentID, 1, maxDepth, map[uint]bool{parentID: true})
+	return nodes
+}
+
+// descendants returns every subtask below the todo id.
+func descendants(session *gorm.DB, id uint) (t []io.Todo, err error) {
+	err = findDescendants(session, "todos", live, id, &t)
+	return t, err
+}
+
+// FindTodos returns the todos whose column by, id, parent_id or
+// category_id, is one of ids, by position. It lets a caller resolving many
+// relations at once fetch them in one query.
+func (b *basicTodoService) FindTodos(ctx context.Context, by string, ids []uint) (t []io.Todo, error error) {
+	if !findable(by, "id", "parent_id", "category_id") {
+		return t, fmt.Errorf("can not find todos by %q", by)
+	}
+	session := connect(ctx)
+	defer session.Close()
+	t = []io.Todo{}
+	if len(ids) == 0 {
+		return t, nil
+	}
+	if at, ok := asOf(ctx); ok {
+		var all []io.Todo
+		all, error = todosAsOf(session, at)
+		for _, v := range all {
+			if containsID(ids, todoColumn(v, by)) {
+				t = append(t, v)
+			}
+		}
+		setProgress(t, all)
+		return t, error
+	}
+	error = session.Where(by+" in (?)", ids).Order(positionOrder).Find(&t).Error
+	if error == nil && len(t) > 0 {
+		roots := []uint{}
+		for _, v := range t {
+			roots = append(roots, v.ID)
+		}
+		var below []io.Todo
+		error = findBelow(session, "todos", live, roots, &below)
+		setProgress(t, below)
+	}
+	return t, error
+}
+
+// FindCategories returns the categories whose column by, id or parent_id,
+// is one of ids, like FindTodos.
+func (b *basicTodoService) FindCategories(ctx context.Context, by string, ids []uint) (c []io.TodoCategory, error error) {
+	if !findable(by, "id", "parent_id") {
+		return c, fmt.Errorf("can not find categories by %q", by)
+	}
+	session := connect(ctx)
+	defer session.Close()
+	c = []io.TodoCategory{}
+	if len(ids) == 0 {
+		return c, nil
+	}
+	if at, ok := asOf(ctx); ok {
+		var all []io.TodoCategory
+		all, error = categoriesAsOf(session, at)
+		for _, v := range all {
+			id := v.ID
+			if by == "parent_id" {
+				id = v.ParentID
+			}
+			if containsID(ids, id) {
+				c = append(c, v)
+			}
+		}
+		return c, error
+	}
+	error = session.Where(by+" in (?)", ids).Order("name, id").Find(&c).Error
+	return c, error
+}
+
+func findable(by string, columns ...string) bool {
+	for _, v := range columns {
+		if by == v {
+			return true
+		}
+	}
+	return false
+}
+
+func todoColumn(t io.Todo, by string) uint {
+	switch by {
+	case "parent_id":
+		return t.ParentID
+	case "category_id":
+		return t.CategoryID
+	}
+	return t.ID
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryDescendants returns every sub category below the category id.
+func categoryDescendants(session *gorm.DB, id uint) (c []io.TodoCategory, err error) {
+	err = findDescendants(session, "todo_categories", live, id, &c)
+	return c, err
+}
+
+// findDescendants loads the rows below id in table that are live or
+// trashed into out, with a recursive query on Postgres and one query per
+// level elsewhere.
+func findDescendants(session *gorm.DB, table, deleted string, id uint, out interface{}) error {
+	return findBelow(session, table, deleted, []uint{id}, out)
+}
+
+// findBelow is findDescendants for the rows below any of roots.
+func findBelow(session *gorm.DB, table, deleted string, roots []uint, out interface{}) error {
+	if session.Dialect().GetName() == "postgres" {
+		return session.Raw(fmt.Sprintf(descendantsQuery, table, deleted), roots).Scan(out).Error
+	}
+	var ids []uint
+	seen := map[uint]bool{}
+	for _, v := range roots {
+		seen[v] = true
+	}
+	for level := roots; len(level) > 0; {
+		var children []uint
+		err := session.Table(table).Where("parent_id in (?) AND deleted_at "+deleted, level).Pluck("id", &children).Error
+		if err != nil {
+			return err
+		}
+		level = nil
+		for _, v := range children {
+			if !seen[v] {
+				seen[v] = true
+				ids = append(ids, v)
+				level = append(level, v)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return session.Unscoped().Where("id in (?)", ids).Find(out).Error
+}
+
+func byParent(todos []io.Todo) map[uint][]io.Todo {
+	m := map[uint][]io.Todo{}
+	for _, v := range todos {
+		m[v.ParentID] = append(m[v.ParentID], v)
+	}
+	for _, v := range m {
+		byPosition(v)
+	}
+	return m
+}
+
+// nest builds the nodes of the children of parent, which sit at depth. The
+// counts of the whole subtree are returned even when maxDepth cuts it.
+func nest(children map[uint][]io.Todo, parent uint, depth, maxDepth int, seen map[uint]bool) (nodes []io.TodoNode, total, completed int) {
+	for _, v := range children[parent] {
+		if seen[v.ID] {
+			continue
+		}
+		seen[v.ID] = true
+		n := io.TodoNode{Todo: v}
+		var sub []io.TodoNode
+		sub, n.Total, n.Completed = nest(children, v.ID, depth+1, maxDepth, seen)
+		n.Progress = progress(n.Total, n.Completed, v.Complete)
+		if maxDepth == 0 || depth < maxDepth {
+			n.Children = sub
+		}
+		nodes = append(nodes, n)
+		total += 1 + n.Total
+		completed += n.Completed
+		if v.Complete {
+			completed++
+		}
+	}
+	return nodes, total, completed
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"todo/pkg/io"
+)
+
+// render writes nodes as id total/completed, with their children in
+// brackets.
+func render(nodes []io.TodoNode) string {
+	var s []string
+	for _, n := range nodes {
+		v := fmt.Sprintf("%d %d/%d", n.ID, n.Total, n.Completed)
+		if len(n.Children) > 0 {
+			v += " [" + render(n.Children) + "]"
+		}
+		s = append(s, v)
+	}
+	return strings.Join(s, ", ")
+}
+
+func TestNest(t *testing.T) {
+	at := func(v io.Todo, position float64) io.Todo {
+		v.Position = position
+		return v
+	}
+	todos := []io.Todo{
+		at(subtask(2, 1, false), 2), at(subtask(3, 1, true), 1),
+		subtask(4, 2, true), subtask(5, 2, false), subtask(6, 5, true),
+	}
+	tests := []struct {
+		name     string
+		todos    []io.Todo
+		parentID uint
+		maxDepth int
+		want     string
+	}{
+		{"all levels", todos, 1, 0, "3 0/0, 2 3/2 [4 0/0, 5 1/1 [6 0/0]]"},
+		{"one level", todos, 1, 1, "3 0/0, 2 3/2"},
+		{"two levels", todos, 1, 2, "3 0/0, 2 3/2 [4 0/0, 5 1/1]"},
+		{"subtree", todos, 2, 0, "4 0/0, 5 1/1 [6 0/0]"},
+		{"leaf", todos, 6, 0, ""},
+		{"cycle", []io.Todo{subtask(2, 1, false), subtask(3, 2, false), subtask(2, 3, false)}, 1, 0, "2 1/0 [3 0/0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(Nest(tt.todos, tt.parentID, tt.maxDepth)); got != tt.want {
+				t.Errorf("Nest = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
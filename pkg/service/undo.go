@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ErrUndoConflict is returned when an item an operation changed has
+// changed again since, or is gone for good.
+var ErrUndoConflict = errors.New("the item changed since")
+
+// ErrNotUndoable is returned when an operation changed items the history
+// can not bring back.
+var ErrNotUndoable = errors.New("the operation can not be undone")
+
+// ErrNothingToUndo is returned when there is no operation left to undo or
+// redo.
+var ErrNothingToUndo = errors.New("nothing to undo or redo")
+
+// Keys of the gorm settings marking the changes as undoing or redoing an
+// operation.
+const (
+	undoSetting = "todo:undo"
+	redoSetting = "todo:redo"
+)
+
+// Undo reverts the last steps operations of the actor of ctx, one if 0,
+// the newest first, in one transaction. An operation is every change made
+// while serving one request, so a subtree or category delete is undone at
+// once. It fails with ErrUndoConflict when an item changed since.
+func (b *basicTodoService) Undo(ctx context.Context, steps int) (h []io.Change, error error) {
+	return b.replay(ctx, steps, true)
+}
+
+// Redo applies again the last steps operations of the actor of ctx undone
+// since their last operation, one if 0, the most recently undone first.
+func (b *basicTodoService) Redo(ctx context.Context, steps int) (h []io.Change, error error) {
+	return b.replay(ctx, steps, false)
+}
+
+// replay undoes or redoes operations, returning the changes it made.
+func (b *basicTodoService) replay(ctx context.Context, steps int, undo bool) (h []io.Change, err error) {
+	if steps < 0 {
+		return h, errors.New("steps must not be negative")
+	}
+	if steps == 0 {
+		steps = 1
+	}
+	actor, _ := ctx.Value(actorKey).(string)
+	if actor == "" {
+		return h, errors.New("undo and redo need an actor")
+	}
+	session := connect(ctx)
+	defer session.Close()
+	ops, err := operations(session, actor, undo)
+	if err != nil {
+		return h, err
+	}
+	if len(ops) == 0 {
+		return h, ErrNothingToUndo
+	}
+	if len(ops) > steps {
+		ops = ops[:steps]
+	}
+	tx := session.Begin()
+	for i := 0; err == nil && i < len(ops); i++ {
+		if undo {
+			err = undoOperation(tx.Set(undoSetting, ops[i]), ops[i])
+		} else {
+			err = redoOperation(tx.Set(redoSetting, ops[i]), ops[i])
+		}
+	}
+	if err = finish(tx, err); err != nil {
+		return h, err
+	}
+	id, _ := session.Get(requestIDSetting)
+	err = session.Where("request_id = ? AND (undo IN (?) OR redo IN (?))", id, ops, ops).Order("id").Find(&h).Error
+	for i := 0; err == nil && i < len(h); i++ {
+		h[i].Diff, err = diff(h[i].Before, h[i].After)
+	}
+	return h, err
+}
+
+// purges are the operations recording items deleted for good, which can
+// not be undone.
+var purges = []string{io.TodoPurged, io.CategoryPurged}
+
+// operations returns the request ids of the operations of actor that can
+// be undone, the newest first, or redone, the most recently undone first.
+// Redo only goes as far back as the last operation of actor. Purges, every
+// change of which is one, are left out.
+func operations(session *gorm.DB, actor string, undo bool) ([]string, error) {
+	rows, err := session.Model(&io.Change{}).
+		Select("request_id, undo, redo, max(id), count(CASE WHEN operation NOT IN (?) THEN 1 END)", purges).
+		Where("actor = ?", actor).Group("request_id, undo, redo").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	done := map[string]uint{}
+	undone := map[string]uint{}
+	redone := map[string]uint{}
+	purged := map[string]bool{}
+	var latest uint
+	for rows.Next() {
+		var request, u, r string
+		var id uint
+		var kept int
+		if err := rows.Scan(&request, &u, &r, &id, &kept); err != nil {
+			return nil, err
+		}
+		switch {
+		case u != "":
+			undone[u] = maxID(undone[u], id)
+		case r != "":
+			redone[r] = maxID(redone[r], id)
+		default:
+			done[request] = id
+			latest = maxID(latest, id)
+			purged[request] = kept == 0
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return replayable(done, undone, redone, purged, latest, undo), nil
+}
+
+// replayable picks, among the operations done, by request id with the id
+// of their last change, the ones to undo, the newest first, or to redo,
+// the most recently undone first. undone and redone hold the last change
+// undoing and redoing each operation, and latest the last operation: redo
+// only goes back to it. The purged operations are skipped.
+func replayable(done, undone, redone map[string]uint, purged map[string]bool, latest uint, undo bool) []string {
+	ops := []string{}
+	at := map[string]uint{}
+	for request, id := range done {
+		if purged[request] {
+			continue
+		}
+		isUndone := undone[request] > redone[request]
+		switch {
+		case undo && !isUndone:
+			ops, at[request] = append(ops, request), id
+		case !undo && isUndone && undone[request] > latest:
+			ops, at[request] = append(ops, request), undone[request]
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return at[ops[i]] > at[ops[j]] })
+	return ops
+}
+
+func maxID(a, b uint) uint {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// undoOperation brings every item the operation request changed back to
+// its state before, the last change first.
+func undoOperation(tx *gorm.DB, request string) error {
+	var changes []io.Change
+	if err := tx.Where("request_id = ? AND undo = '' AND redo = ''", request).Order("id desc").Find(&changes).Error; err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if err := applyState(tx, c.Kind, c.ItemID, c.After, c.Before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// redoOperation brings every item the operation request changed back to
+// its state after, the first change first.
+func redoOperation(tx *gorm.DB, request string) error {
+	var changes []io.Change
+	if err := tx.Where("request_id = ? AND undo = '' AND redo = ''", request).Order("id").Find(&changes).Error; err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if err := applyState(tx, c.Kind, c.ItemID, c.Before, c.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyState moves the item id of kind from the state from to the state
+// to, nil meaning deleted, failing with ErrUndoConflict unless it is in
+// from.
+func applyState(tx *gorm.DB, kind string, id uint, from, to io.Snapshot) error {
+	current := io.Change{}
+	if err := tx.Where("kind = ? AND item_id = ?", kind, id).Order("id desc").First(&current).Error; err != nil {
+		return err
+	}
+	if err := checkState(current.After, from); err != nil {
+		return fmt.Errorf("%w: %s %d", err, kind, id)
+	}
+	switch kind {
+	case io.TrashTodo:
+		return applyTodo(tx, id, to)
+	case io.TrashCategory:
+		return applyCategory(tx, id, to)
+	case io.HistoryDependency:
+		return applyDependency(tx, id, to)
+	case io.HistoryTimeEntry:
+		return applyTimeEntry(tx, id, to)
+	case io.HistoryWorkflow:
+		return applyWorkflow(tx, id, to)
+	}
+	return fmt.Errorf("%w: %s %d", ErrNotUndoable, kind, id)
+}
+
+// checkState fails with ErrUndoConflict unless the item whose last
+// recorded state is current is in the state from, but for its update time.
+func checkState(current, from io.Snapshot) error {
+	d, err := diff(current, from)
+	if err != nil {
+		return err
+	}
+	if len(d) > 0 || (current == nil) != (from == nil) {
+		return ErrUndoConflict
+	}
+	return nil
+}
+
+func applyTodo(tx *gorm.DB, id uint, to io.Snapshot) error {
+	todo := io.Todo{}
+	err := tx.Unscoped().Where("id = ?", id).First(&todo).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return fmt.Errorf("%w: todo %d was purged", ErrUndoConflict, id)
+	}
+	if err != nil {
+		return err
+	}
+	if to == nil {
+		return removeTodo(tx, todo)
+	}
+	typ := io.TodoUpdated
+	if todo.DeletedAt != nil {
+		typ = io.TodoRestored
+	}
+	todo = io.Todo{}
+	if err := json.Unmarshal(to, &todo); err != nil {
+		return err
+	}
+	todo.DeletedAt = nil
+	if err := tx.Unscoped().Save(&todo).Error; err != nil {
+		return err
+	}
+	return recordTodo(tx, typ, todo)
+}
+
+func applyCategory(tx *gorm.DB, id uint, to io.Snapshot) error {
+	category := io.TodoCategory{}
+	err := tx.Unscoped().Where("id = ?", id).First(&category).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return fmt.Errorf("%w: category %d was purged", ErrUndoConflict, id)
+	}
+	if err != nil {
+		return err
+	}
+	if to == nil {
+		if err := tx.Delete(&category).Error; err != nil {
+			return err
+		}
+		return recordCategory(tx, io.CategoryDeleted, category)
+	}
+	typ := io.CategoryUpdated
+	if category.DeletedAt != nil {
+		typ = io.CategoryRestored
+	}
+	category = io.TodoCategory{}
+	if err := json.Unmarshal(to, &category); err != nil {
+		return err
+	}
+	category.DeletedAt = nil
+	if err := tx.Unscoped().Save(&category).Error; err != nil {
+		return err
+	}
+	return recordCategory(tx, typ, category)
+}
+
+func applyDependency(tx *gorm.DB, id uint, to io.Snapshot) error {
+	d := io.Dependency{}
+	err := tx.Where("id = ?", id).First(&d).Error
+	switch {
+	case to == nil && gorm.IsRecordNotFoundError(err):
+		return fmt.Errorf("%w: dependency %d was purged", ErrUndoConflict, id)
+	case to == nil && err == nil:
+		return removeDependency(tx, d)
+	case err == nil:
+		return nil
+	case !gorm.IsRecordNotFoundError(err):
+		return err
+	}
+	if err := json.Unmarshal(to, &d); err != nil {
+		return err
+	}
+	// The same dependency may have been added again since, or its todos
+	// purged, or the other way round.
+	var n int
+	if err := tx.Model(&io.Dependency{}).Where("todo_id = ? AND blocker_id = ?", d.TodoID, d.BlockerID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: dependency %d was added again", ErrUndoConflict, id)
+	}
+	if err := tx.Unscoped().Model(&io.Todo{}).Where("id IN (?)", []uint{d.TodoID, d.BlockerID}).Count(&n).Error; err != nil {
+		return err
+	}
+	if n < 2 {
+		return fmt.Errorf("%w: a todo of dependency %d was purged", ErrUndoConflict, id)
+	}
+	err = checkDependency(tx, d.TodoID, d.BlockerID)
+	if err == ErrDependencyCycle {
+		return fmt.Errorf("%w: dependency %d would close a cycle", ErrUndoConflict, id)
+	}
+	if err != nil {
+		return err
+	}
+	todo := io.Todo{}
+	if err := tx.Unscoped().Where("id = ?", d.TodoID).First(&todo).Error; err != nil {
+		return err
+	}
+	if err := tx.Create(&d).Error; err != nil {
+		return err
+	}
+	return recordDependency(tx, io.TodoBlocked, todo, d)
+}
+
+func removeDependency(tx *gorm.DB, d io.Dependency) error {
+	todo := io.Todo{}
+	if err := tx.Unscoped().Where("id = ?", d.TodoID).First(&todo).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(&d).Error; err != nil {
+		return err
+	}
+	return recordDependency(tx, io.TodoUnblocked, todo, d)
+}
+
+func applyTimeEntry(tx *gorm.DB, id uint, to io.Snapshot) error {
+	e := io.TimeEntry{}
+	err := tx.Where("id = ?", id).First(&e).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+	if to == nil {
+		if err != nil {
+			return fmt.Errorf("%w: time entry %d was purged", ErrUndoConflict, id)
+		}
+		if err := tx.Delete(&e).Error; err != nil {
+			return err
+		}
+		return recordTimeEntry(tx, io.TimeEntryDeleted, e)
+	}
+	typ := io.TimeEntryUpdated
+	if err != nil {
+		typ = io.TimeEntryCreated
+	}
+	e = io.TimeEntry{}
+	if err := json.Unmarshal(to, &e); err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Where("id = ?", e.TodoID).First(&io.Todo{}).Error; err != nil {
+		if gorm.IsRecordNotFoundError(err) {
+			return fmt.Errorf("%w: the todo of time entry %d was purged", ErrUndoConflict, id)
+		}
+		return err
+	}
+	if e.EndedAt == nil {
+		if running, err := runningTimer(tx, e.User); err == nil && running.ID != e.ID {
+			return fmt.Errorf("%w: %s has started another timer", ErrUndoConflict, e.User)
+		}
+	}
+	if err := tx.Save(&e).Error; err != nil {
+		return err
+	}
+	return recordTimeEntry(tx, typ, e)
+}
+
+func applyWorkflow(tx *gorm.DB, id uint, to io.Snapshot) error {
+	w := io.Workflow{}
+	err := tx.Where("id = ?", id).First(&w).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return err
+	}
+	if to == nil {
+		if err != nil {
+			return fmt.Errorf("%w: workflow %d is gone", ErrUndoConflict, id)
+		}
+		if err := tx.Unscoped().Delete(&w).Error; err != nil {
+			return err
+		}
+		return recordWorkflow(tx, io.WorkflowDropped, w)
+	}
+	w = io.Workflow{}
+	if err := json.Unmarshal(to, &w); err != nil {
+		return err
+	}
+	var n int
+	if err := tx.Model(&io.Workflow{}).Where("category_id = ? AND id <> ?", w.CategoryID, w.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: category %d has another workflow", ErrUndoConflict, w.CategoryID)
+	}
+	w.DeletedAt = nil
+	if err := tx.Unscoped().Save(&w).Error; err != nil {
+		return err
+	}
+	return recordWorkflow(tx, io.WorkflowSet, w)
+}
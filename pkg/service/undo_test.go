@@ -0,0 +1,70 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"todo/pkg/io"
+)
+
+func TestCheckState(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  io.Snapshot
+		from     io.Snapshot
+		conflict bool
+		fails    bool
+	}{
+		{"unchanged", io.Snapshot(`{"ID":1,"Title":"a"}`), io.Snapshot(`{"ID":1,"Title":"a"}`), false, false},
+		{"updated at only", io.Snapshot(`{"ID":1,"UpdatedAt":"2020-01-02T00:00:00Z"}`), io.Snapshot(`{"ID":1,"UpdatedAt":"2020-01-01T00:00:00Z"}`), false, false},
+		{"field changed", io.Snapshot(`{"ID":1,"Title":"b"}`), io.Snapshot(`{"ID":1,"Title":"a"}`), true, true},
+		{"field added", io.Snapshot(`{"ID":1,"Title":"a","Star":1}`), io.Snapshot(`{"ID":1,"Title":"a"}`), true, true},
+		{"deleted since", nil, io.Snapshot(`{"ID":1}`), true, true},
+		{"created since", io.Snapshot(`{"ID":1}`), nil, true, true},
+		{"still deleted", nil, nil, false, false},
+		{"broken snapshot", io.Snapshot(`{`), io.Snapshot(`{"ID":1}`), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkState(tt.current, tt.from)
+			if (err != nil) != tt.fails || (err == ErrUndoConflict) != tt.conflict {
+				t.Errorf("checkState = %v", err)
+			}
+		})
+	}
+}
+
+func TestReplayable(t *testing.T) {
+	tests := []struct {
+		name   string
+		done   map[string]uint
+		undone map[string]uint
+		redone map[string]uint
+		purged map[string]bool
+		undo   bool
+		want   []string
+	}{
+		{"undo the newest first", map[string]uint{"a": 1, "b": 5, "c": 3}, nil, nil, nil, true, []string{"b", "c", "a"}},
+		{"undo skips undone", map[string]uint{"a": 1, "b": 5}, map[string]uint{"b": 6}, nil, nil, true, []string{"a"}},
+		{"undo again once redone", map[string]uint{"a": 1, "b": 5}, map[string]uint{"b": 6}, map[string]uint{"b": 7}, nil, true, []string{"b", "a"}},
+		{"nothing to undo", map[string]uint{"a": 1}, map[string]uint{"a": 2}, nil, nil, true, []string{}},
+		{"redo the last undone first", map[string]uint{"a": 1, "b": 5}, map[string]uint{"b": 6, "a": 7}, nil, nil, false, []string{"a", "b"}},
+		{"redo skips redone", map[string]uint{"a": 1, "b": 5}, map[string]uint{"b": 6, "a": 7}, map[string]uint{"a": 8}, nil, false, []string{"b"}},
+		{"no redo past a new operation", map[string]uint{"a": 1, "b": 5, "c": 8}, map[string]uint{"b": 6}, nil, nil, false, []string{}},
+		{"purge skipped", map[string]uint{"a": 1, "p": 5}, nil, nil, map[string]bool{"p": true}, true, []string{"a"}},
+		{"only a purge", map[string]uint{"p": 5}, nil, nil, map[string]bool{"p": true}, true, []string{}},
+		{"no redo past a purge", map[string]uint{"a": 1, "p": 5}, map[string]uint{"a": 2}, nil, map[string]bool{"p": true}, false, []string{}},
+		{"nothing to redo", map[string]uint{"a": 1}, nil, nil, nil, false, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var latest uint
+			for _, id := range tt.done {
+				latest = maxID(latest, id)
+			}
+			got := replayable(tt.done, tt.undone, tt.redone, tt.purged, latest, tt.undo)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("replayable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+	"todo/pkg/io"
+)
+
+func (b *basicTodoService) AddWebhook(ctx context.Context, webhook io.Webhook) (w io.Webhook, error error) {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return w, errors.New("webhook url must be an absolute http or https url")
+	}
+	for _, typ := range webhook.Events {
+		if !knownEventType(typ) {
+			return w, fmt.Errorf("unknown event type %q", typ)
+		}
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return w, err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Create(&webhook).Error
+	return webhook, error
+}
+
+// GetWebhooks never returns the secrets, they are only shown by AddWebhook.
+func (b *basicTodoService) GetWebhooks(ctx context.Context) (w []io.Webhook, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Find(&w).Error
+	for i := range w {
+		w[i].Secret = ""
+	}
+	return w, error
+}
+
+func (b *basicTodoService) DeleteWebhook(ctx context.Context, id string) (error error) {
+	session := connect(ctx)
+	defer session.Close()
+	webhook := io.Webhook{}
+	err := session.Where("id = ?", id).Find(&webhook).Error
+	if err != nil {
+		return err
+	}
+	return session.Delete(&webhook).Error
+}
+
+func (b *basicTodoService) GetWebhookDeliveries(ctx context.Context, id string) (d []io.WebhookDelivery, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Where("webhook_id = ?", id).Order("id desc").Find(&d).Error
+	return d, error
+}
+
+func (b *basicTodoService) GetDeadLetters(ctx context.Context) (d []io.WebhookDelivery, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	error = session.Where("status = ?", io.DeliveryDead).Order("id desc").Find(&d).Error
+	return d, error
+}
+
+// RetryDelivery puts a dead letter back in the queue with a fresh set of
+// attempts.
+func (b *basicTodoService) RetryDelivery(ctx context.Context, id string) (d io.WebhookDelivery, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	err := session.Where("id = ?", id).Find(&d).Error
+	if err != nil {
+		return d, err
+	}
+	if d.Status != io.DeliveryDead {
+		return d, errors.New("only dead letters can be retried")
+	}
+	d.Status = io.DeliveryPending
+	d.Attempts = 0
+	d.NextAttempt = time.Now()
+	error = session.Save(&d).Error
+	return d, error
+}
+
+func knownEventType(typ string) bool {
+	for _, v := range io.EventTypes {
+		if v == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todo/pkg/io"
+
+	"github.com/jinzhu/gorm"
+	"github.com/lib/pq"
+)
+
+// DefaultWorkflow applies to the categories without a workflow, unless
+// one was set for category 0.
+var DefaultWorkflow = io.Workflow{Statuses: pq.StringArray{"todo", "in progress", "done"}}
+
+// ErrTransition is returned when a workflow does not allow a transition.
+var ErrTransition = errors.New("transition not allowed")
+
+// Transition moves the todo id to status, if the workflow of its category
+// allows it. Reaching the last status completes the todo, leaving it
+// reopens it.
+func (b *basicTodoService) Transition(ctx context.Context, id string, status string) (t io.Todo, error error) {
+	return b.transition(ctx, id, allowed(status))
+}
+
+// GetWorkflow returns the workflow the todos of the category id follow:
+// its own, the one of its nearest ancestor with one, or the default.
+func (b *basicTodoService) GetWorkflow(ctx context.Context, id string) (w io.Workflow, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	category, err := categoryID(session, id)
+	if err != nil {
+		return w, err
+	}
+	return workflowFor(session, category)
+}
+
+// SetWorkflow sets the workflow of the category id, and of its sub
+// categories without their own. Setting one without statuses drops it.
+// Todos in a status the new workflow lacks are shown in its first or
+// last one, after Complete, until they transition.
+func (b *basicTodoService) SetWorkflow(ctx context.Context, id string, workflow io.Workflow) (w io.Workflow, error error) {
+	if err := checkWorkflow(workflow); err != nil {
+		return w, err
+	}
+	session := connect(ctx)
+	defer session.Close()
+	category, err := categoryID(session, id)
+	if err != nil {
+		return w, err
+	}
+	err = session.Where("category_id = ?", category).First(&w).Error
+	switch {
+	case err != nil && !gorm.IsRecordNotFoundError(err):
+		return w, err
+	case len(workflow.Statuses) == 0:
+		if err == nil {
+			tx := session.Begin()
+			error = tx.Unscoped().Delete(&w).Error
+			if error == nil {
+				error = recordWorkflow(tx, io.WorkflowDropped, w)
+			}
+			error = finish(tx, error)
+		}
+		if error == nil {
+			w, error = workflowFor(session, category)
+		}
+		return w, error
+	}
+	w.CategoryID = category
+	w.Statuses = workflow.Statuses
+	w.Transitions = workflow.Transitions
+	tx := session.Begin()
+	error = tx.Save(&w).Error
+	if error == nil {
+		error = recordWorkflow(tx, io.WorkflowSet, w)
+	}
+	return w, finish(tx, error)
+}
+
+// GetBoard returns the todos filed directly under the category id in the
+// columns of its workflow, each by position.
+func (b *basicTodoService) GetBoard(ctx context.Context, id string) (board io.Board, error error) {
+	session := connect(ctx)
+	defer session.Close()
+	category, err := categoryID(session, id)
+	if err != nil {
+		return board, err
+	}
+	if board.Workflow, err = workflowFor(session, category); err != nil {
+		return board, err
+	}
+	var todos []io.Todo
+	if err := session.Where("category_id = ?", category).Order(positionOrder).Find(&todos).Error; err != nil {
+		return board, err
+	}
+	column := map[string]int{}
+	for i, v := range board.Workflow.Statuses {
+		column[v] = i
+		board.Columns = append(board.Columns, io.BoardColumn{Status: v, Todos: []io.Todo{}})
+	}
+	for _, v := range todos {
+		v.Status = board.Workflow.StatusOf(v)
+		c := &board.Columns[column[v.Status]]
+		c.Todos = append(c.Todos, v)
+	}
+	return board, nil
+}
+
+// statusPicker picks the status a todo moves to in workflow w.
+type statusPicker func(w io.Workflow, todo io.Todo) (string, error)
+
+func allowed(status string) statusPicker {
+	return func(w io.Workflow, todo io.Todo) (string, error) {
+		if !w.Has(status) {
+			return "", fmt.Errorf("unknown status %q", status)
+		}
+		if from := w.StatusOf(todo); !w.Allows(from, status) {
+			return "", fmt.Errorf("%w from %q to %q", ErrTransition, from, status)
+		}
+		return status, nil
+	}
+}
+
+func terminal(w io.Workflow, todo io.Todo) (string, error) {
+	return w.Terminal(), nil
+}
+
+func initial(w io.Workflow, todo io.Todo) (string, error) {
+	return w.Initial(), nil
+}
+
+// transition moves the todo id to the status picked in the workflow of
+// its category, recording the change as a completion, a reopening or a
+// plain transition.
+func (b *basicTodoService) transition(ctx context.Context, id string, to statusPicker) (t io.Todo, err error) {
+	session := connect(ctx)
+	defer session.Close()
+	if err := session.Where("id = ?", id).First(&t).Error; err != nil {
+		return t, err
+	}
+	w, err := workflowFor(session, t.CategoryID)
+	if err != nil {
+		return t, err
+	}
+	status, err := to(w, t)
+	if err != nil {
+		return t, err
+	}
+	if status == w.Terminal() && b.config.EnforceDependencies {
+		if err := checkBlockers(session, t.ID); err != nil {
+			return t, err
+		}
+	}
+	typ := io.TodoTransitioned
+	switch {
+	case status == w.Terminal():
+		typ = io.TodoCompleted
+	case t.Complete:
+		typ = io.TodoReopened
+	}
+	t.Status, t.Complete = status, status == w.Terminal()
+	tx := session.Begin()
+	err = saveTodo(tx, typ, t)
+	if err == nil {
+		err = b.propagate(tx, t)
+	}
+	if err = finish(tx, err); err == nil {
+		err = withProgress(session, &t)
+	}
+	return t, err
+}
+
+// workflowFor returns the workflow of category: its own, the one of its
+// nearest ancestor with one, the one of category 0 or the default.
+func workflowFor(tx *gorm.DB, category uint) (w io.Workflow, err error) {
+	seen := map[uint]bool{}
+	for c := category; !seen[c]; {
+		seen[c] = true
+		err = tx.Where("category_id = ?", c).First(&w).Error
+		if err == nil || !gorm.IsRecordNotFoundError(err) || c == 0 {
+			break
+		}
+		var row struct{ ParentID uint }
+		err = tx.Table("todo_categories").Select("parent_id").Where("id = ? AND deleted_at IS NULL", c).Scan(&row).Error
+		if err != nil && !gorm.IsRecordNotFoundError(err) {
+			return w, err
+		}
+		c = row.ParentID
+	}
+	if gorm.IsRecordNotFoundError(err) {
+		return DefaultWorkflow, nil
+	}
+	return w, err
+}
+
+// BackfillStatuses gives the todos from before workflows, trashed or not,
+// the first or last status of the workflow of their category, following
+// Complete.
+func BackfillStatuses(session *gorm.DB) error {
+	var categories []uint
+	err := session.Unscoped().Model(&io.Todo{}).Where("status = ''").Pluck("DISTINCT category_id", &categories).Error
+	for i := 0; err == nil && i < len(categories); i++ {
+		var w io.Workflow
+		if w, err = workflowFor(session, categories[i]); err != nil {
+			break
+		}
+		todos := session.Unscoped().Model(&io.Todo{}).Where("status = '' AND category_id = ?", categories[i])
+		err = todos.Where("complete").UpdateColumn("status", w.Terminal()).Error
+		if err == nil {
+			err = todos.Where("NOT complete").UpdateColumn("status", w.Initial()).Error
+		}
+	}
+	return err
+}
+
+// newStatus sets the status of a todo about to be created: the one it
+// asks for if the workflow of its category has it, else the first or
+// last one after Complete, which then follows the status.
+func newStatus(tx *gorm.DB, todo *io.Todo) error {
+	w, err := workflowFor(tx, todo.CategoryID)
+	if err != nil {
+		return err
+	}
+	todo.Status = w.StatusOf(*todo)
+	todo.Complete = todo.Status == w.Terminal()
+	return nil
+}
+
+// keepStatus returns the status todo is saved with by Update: the stored
+// one, unless Complete changed, which moves it to the first or last
+// status. toggled reports whether Complete changed.
+func keepStatus(tx *gorm.DB, todo io.Todo) (status string, toggled bool, err error) {
+	stored := io.Todo{}
+	err = tx.Where("id = ?", todo.ID).First(&stored).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return "", false, err
+	}
+	if err == nil && stored.Complete == todo.Complete {
+		return stored.Status, false, nil
+	}
+	w, err := workflowFor(tx, todo.CategoryID)
+	if err != nil {
+		return "", false, err
+	}
+	if todo.Complete {
+		return w.Terminal(), true, nil
+	}
+	return w.Initial(), true, nil
+}
+
+func checkWorkflow(w io.Workflow) error {
+	if len(w.Statuses) == 1 {
+		return errors.New("a workflow needs at least two statuses")
+	}
+	seen := map[string]bool{}
+	for _, v := range w.Statuses {
+		if v == "" || seen[v] {
+			return fmt.Errorf("status %q is empty or repeated", v)
+		}
+		seen[v] = true
+	}
+	for from, to := range w.Transitions {
+		for _, v := range append([]string{from}, to...) {
+			if !seen[v] {
+				return fmt.Errorf("transition with unknown status %q", v)
+			}
+		}
+	}
+	return nil
+}
+
+// categoryID resolves the category id, "0" standing for the todos without
+// a category.
+func categoryID(session *gorm.DB, id string) (uint, error) {
+	if id == "0" {
+		return 0, nil
+	}
+	category := io.TodoCategory{}
+	err := session.Where("id = ?", id).First(&category).Error
+	return category.ID, err
+}
@@ -0,0 +1,149 @@
+package thrift
+
+import (
+	"time"
+	io "todo/pkg/io"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+//go:generate go run gen.go todo.thrift
+
+// fieldSet holds the ids of the fields a struct read off the wire sets.
+type fieldSet map[int16]bool
+
+// readStruct reads a thrift struct off the wire and hands every field to
+// field. Fields that field does not handle are skipped, so older clients
+// sending extra fields keep working.
+func readStruct(iprot thrift.TProtocol, field func(id int16, typeId thrift.TType) (bool, error)) error {
+	if _, err := iprot.ReadStructBegin(); err != nil {
+		return err
+	}
+	for {
+		_, typeId, id, err := iprot.ReadFieldBegin()
+		if err != nil {
+			return err
+		}
+		if typeId == thrift.STOP {
+			break
+		}
+		ok, err := field(id, typeId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if err := iprot.Skip(typeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(); err != nil {
+			return err
+		}
+	}
+	return iprot.ReadStructEnd()
+}
+
+// structWriter writes the fields of a thrift struct and keeps the first
+// error, so callers only have to check once at the end.
+type structWriter struct {
+	oprot thrift.TProtocol
+	err   error
+}
+
+func (w *structWriter) field(name string, typeId thrift.TType, id int16, value func() error) {
+	if w.err != nil {
+		return
+	}
+	if w.err = w.oprot.WriteFieldBegin(name, typeId, id); w.err != nil {
+		return
+	}
+	if w.err = value(); w.err != nil {
+		return
+	}
+	w.err = w.oprot.WriteFieldEnd()
+}
+
+func (w *structWriter) i64(name string, id int16, v int64) {
+	w.field(name, thrift.I64, id, func() error { return w.oprot.WriteI64(v) })
+}
+
+func (w *structWriter) i32(name string, id int16, v int32) {
+	w.field(name, thrift.I32, id, func() error { return w.oprot.WriteI32(v) })
+}
+
+func (w *structWriter) double(name string, id int16, v float64) {
+	w.field(name, thrift.DOUBLE, id, func() error { return w.oprot.WriteDouble(v) })
+}
+
+func (w *structWriter) str(name string, id int16, v string) {
+	w.field(name, thrift.STRING, id, func() error { return w.oprot.WriteString(v) })
+}
+
+func (w *structWriter) byte(name string, id int16, v int8) {
+	w.field(name, thrift.BYTE, id, func() error { return w.oprot.WriteByte(v) })
+}
+
+func (w *structWriter) bool(name string, id int16, v bool) {
+	w.field(name, thrift.BOOL, id, func() error { return w.oprot.WriteBool(v) })
+}
+
+func (w *structWriter) begin(name string) {
+	if w.err == nil {
+		w.err = w.oprot.WriteStructBegin(name)
+	}
+}
+
+func (w *structWriter) end() error {
+	if w.err == nil {
+		w.err = w.oprot.WriteFieldStop()
+	}
+	if w.err == nil {
+		w.err = w.oprot.WriteStructEnd()
+	}
+	return w.err
+}
+
+func unixTime(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.Unix(v, 0)
+}
+
+func timeUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func writeTodoError(oprot thrift.TProtocol, err error) error {
+	w := &structWriter{oprot: oprot}
+	w.begin("TodoError")
+	w.str("message", 1, err.Error())
+	return w.end()
+}
+
+func writeTodoList(oprot thrift.TProtocol, t []io.Todo) error {
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(t)); err != nil {
+		return err
+	}
+	for _, v := range t {
+		if err := writeTodo(oprot, v); err != nil {
+			return err
+		}
+	}
+	return oprot.WriteListEnd()
+}
+
+func writeTodoCategoryList(oprot thrift.TProtocol, c []io.TodoCategory) error {
+	if err := oprot.WriteListBegin(thrift.STRUCT, len(c)); err != nil {
+		return err
+	}
+	for _, v := range c {
+		if err := writeTodoCategory(oprot, v); err != nil {
+			return err
+		}
+	}
+	return oprot.WriteListEnd()
+}
@@ -0,0 +1,180 @@
+//go:build ignore
+// +build ignore
+
+// gen.go writes codec_gen.go, the readers, writers and mergers of the
+// structs of todo.thrift, so the codec follows the IDL rather than being
+// kept in sync with it by hand. Every IDL field is mapped to the io field
+// of the same name in CamelCase, "id" spelled "ID", and converted
+// according to both types.
+//
+//	go generate ./pkg/thrift
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	io "todo/pkg/io"
+)
+
+// models are the io types of the IDL structs.
+var models = map[string]interface{}{
+	"Todo":         io.Todo{},
+	"TodoCategory": io.TodoCategory{},
+}
+
+// conversion reads a wire value, x, into a Go field and writes one back.
+type conversion struct {
+	read  string // sets %[1]s from x
+	write string // the wire value of %[1]s
+}
+
+var conversions = map[[2]string]conversion{
+	{"i64", "uint"}:       {"%[1]s = uint(x)", "int64(%[1]s)"},
+	{"i64", "time.Time"}:  {"%[1]s = unixTime(x)", "timeUnix(%[1]s)"},
+	{"i64", "*time.Time"}: {"%[1]s = nil\nif x != 0 {\nt := unixTime(x)\n%[1]s = &t\n}", "timeUnix(*%[1]s)"},
+	{"i32", "int"}:        {"%[1]s = int(x)", "int32(%[1]s)"},
+	{"byte", "uint8"}:     {"%[1]s = uint8(x)", "int8(%[1]s)"},
+	{"string", "string"}:  {"%[1]s = x", "%[1]s"},
+	{"bool", "bool"}:      {"%[1]s = x", "%[1]s"},
+	{"double", "float64"}: {"%[1]s = x", "%[1]s"},
+}
+
+// wire names the thrift.TType, the protocol method suffix and the
+// structWriter method of the IDL base types.
+var wire = map[string][3]string{
+	"i64":    {"I64", "I64", "i64"},
+	"i32":    {"I32", "I32", "i32"},
+	"byte":   {"BYTE", "Byte", "byte"},
+	"string": {"STRING", "String", "str"},
+	"bool":   {"BOOL", "Bool", "bool"},
+	"double": {"DOUBLE", "Double", "double"},
+}
+
+type field struct {
+	id       string
+	optional bool
+	typ      string
+	name     string
+	goName   string
+	goType   string
+}
+
+var (
+	structRe = regexp.MustCompile(`(?s)\bstruct\s+(\w+)\s*\{(.*?)\}`)
+	fieldRe  = regexp.MustCompile(`^(\d+):\s*(optional\s+|required\s+)?(\w+)\s+(\w+)`)
+	comment  = regexp.MustCompile(`//.*`)
+)
+
+func main() {
+	idl, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen.go from %s; DO NOT EDIT.\n\n", os.Args[1])
+	b.WriteString("package thrift\n\nimport (\n\tio \"todo/pkg/io\"\n\n\tthrift \"github.com/apache/thrift/lib/go/thrift\"\n)\n")
+	for _, m := range structRe.FindAllStringSubmatch(string(comment.ReplaceAllString(string(idl), "")), -1) {
+		model, ok := models[m[1]]
+		if !ok {
+			log.Fatalf("struct %s has no io type", m[1])
+		}
+		fields, err := parse(reflect.TypeOf(model), m[2])
+		if err != nil {
+			log.Fatalf("struct %s: %v", m[1], err)
+		}
+		generate(&b, m[1], fields)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("codec_gen.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse(model reflect.Type, body string) ([]field, error) {
+	var fields []field
+	for _, line := range strings.Split(body, "\n") {
+		m := fieldRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		f := field{id: m[1], optional: strings.TrimSpace(m[2]) == "optional", typ: m[3], name: m[4]}
+		for _, v := range strings.Split(f.name, "_") {
+			if v == "id" {
+				f.goName += "ID"
+			} else {
+				f.goName += strings.Title(v)
+			}
+		}
+		sf, ok := model.FieldByName(f.goName)
+		if !ok {
+			return nil, fmt.Errorf("no field %s for %s", f.goName, f.name)
+		}
+		f.goType = sf.Type.String()
+		if _, ok := conversions[[2]string{f.typ, f.goType}]; !ok {
+			return nil, fmt.Errorf("can not convert %s %s to %s", f.typ, f.name, f.goType)
+		}
+		if f.optional != (sf.Type.Kind() == reflect.Ptr) {
+			return nil, fmt.Errorf("%s must be optional if and only if %s is a pointer", f.name, f.goName)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func generate(b *bytes.Buffer, name string, fields []field) {
+	fmt.Fprintf(b, "\n// read%[1]s reads a %[1]s and the ids of the fields it sets.\n", name)
+	fmt.Fprintf(b, "func read%[1]s(iprot thrift.TProtocol) (v io.%[1]s, set fieldSet, err error) {\n", name)
+	b.WriteString("set = fieldSet{}\nerr = readStruct(iprot, func(id int16, typeId thrift.TType) (bool, error) {\n")
+	b.WriteString("var err error\nswitch {\n")
+	for _, f := range fields {
+		c := conversions[[2]string{f.typ, f.goType}]
+		w := wire[f.typ]
+		fmt.Fprintf(b, "case id == %s && typeId == thrift.%s:\n", f.id, w[0])
+		fmt.Fprintf(b, "var x %s\nx, err = iprot.Read%s()\n", goWire(f.typ), w[1])
+		fmt.Fprintf(b, c.read+"\n", "v."+f.goName)
+	}
+	b.WriteString("default:\nreturn false, nil\n}\nset[id] = true\nreturn true, err\n})\nreturn v, set, err\n}\n")
+
+	fmt.Fprintf(b, "\nfunc write%[1]s(oprot thrift.TProtocol, v io.%[1]s) error {\n", name)
+	fmt.Fprintf(b, "w := &structWriter{oprot: oprot}\nw.begin(%q)\n", name)
+	for _, f := range fields {
+		c := conversions[[2]string{f.typ, f.goType}]
+		call := fmt.Sprintf("w.%s(%q, %s, %s)\n", wire[f.typ][2], f.name, f.id, fmt.Sprintf(c.write, "v."+f.goName))
+		if f.optional {
+			call = fmt.Sprintf("if v.%s != nil {\n%s}\n", f.goName, call)
+		}
+		b.WriteString(call)
+	}
+	b.WriteString("return w.end()\n}\n")
+
+	fmt.Fprintf(b, "\n// merge%[1]s copies the fields of src that are in set onto dst.\n", name)
+	fmt.Fprintf(b, "func merge%[1]s(dst *io.%[1]s, src io.%[1]s, set fieldSet) {\n", name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "if set[%s] {\ndst.%s = src.%[2]s\n}\n", f.id, f.goName)
+	}
+	b.WriteString("}\n")
+}
+
+func goWire(typ string) string {
+	switch typ {
+	case "i64":
+		return "int64"
+	case "i32":
+		return "int32"
+	case "byte":
+		return "int8"
+	case "double":
+		return "float64"
+	}
+	return typ
+}
@@ -0,0 +1,372 @@
+package thrift
+
+import (
+	"context"
+	"fmt"
+	endpoint "todo/pkg/endpoint"
+	io "todo/pkg/io"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+	endpoint1 "github.com/go-kit/kit/endpoint"
+)
+
+// method describes how a single TodoService call is read off the wire and
+// how its successful result of wire type result is written back. encode is
+// nil for void methods.
+type method struct {
+	endpoint endpoint1.Endpoint
+	decode   func(iprot thrift.TProtocol) (interface{}, error)
+	encode   func(oprot thrift.TProtocol, response interface{}) error
+	result   thrift.TType
+}
+
+type processor struct {
+	methods map[string]method
+}
+
+// NewThriftHandler returns a thrift processor that makes the endpoints
+// available to clients generated from todo.thrift.
+func NewThriftHandler(endpoints endpoint.Endpoints) thrift.TProcessor {
+	return &processor{methods: map[string]method{
+		"Get":            {endpoints.GetEndpoint, decodeGetRequest, encodeGetResponse, thrift.LIST},
+		"Add":            {endpoints.AddEndpoint, decodeAddRequest, encodeAddResponse, thrift.STRUCT},
+		"SetComplete":    {endpoints.SetCompleteEndpoint, decodeSetCompleteRequest, nil, thrift.VOID},
+		"RemoveComplete": {endpoints.RemoveCompleteEndpoint, decodeRemoveCompleteRequest, nil, thrift.VOID},
+		"Delete":         {endpoints.DeleteEndpoint, decodeDeleteRequest, nil, thrift.VOID},
+		"Update":         {updateTodo(endpoints), decodeUpdateRequest, encodeUpdateResponse, thrift.STRUCT},
+		"SetStar":        {endpoints.SetStarEndpoint, decodeSetStarRequest, nil, thrift.VOID},
+		"ReplyTo":        {endpoints.ReplyToEndpoint, decodeReplyToRequest, encodeReplyToResponse, thrift.STRUCT},
+		"GetChildes":     {endpoints.GetChildesEndpoint, decodeGetChildesRequest, encodeGetChildesResponse, thrift.LIST},
+		"GetCategory":    {endpoints.GetCategoryEndpoint, decodeGetCategoryRequest, encodeGetCategoryResponse, thrift.LIST},
+		"AddCategory":    {endpoints.AddCategoryEndpoint, decodeAddCategoryRequest, encodeAddCategoryResponse, thrift.STRUCT},
+		"UpdateCategory": {updateCategory(endpoints), decodeUpdateCategoryRequest, encodeUpdateCategoryResponse, thrift.STRUCT},
+		"DeleteCategory": {endpoints.DeleteCategoryEndpoint, decodeDeleteCategoryRequest, nil, thrift.VOID},
+		"GetCatChildes":  {endpoints.GetCatChildesEndpoint, decodeGetCatChildesRequest, encodeGetCatChildesResponse, thrift.LIST},
+	}}
+}
+
+// Process implements thrift.TProcessor.
+func (p *processor) Process(ctx context.Context, in, out thrift.TProtocol) (bool, thrift.TException) {
+	name, typeId, seqId, err := in.ReadMessageBegin()
+	if err != nil {
+		return false, err
+	}
+	m, ok := p.methods[name]
+	if !ok {
+		in.Skip(thrift.STRUCT)
+		in.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
+		writeException(ctx, out, name, seqId, x)
+		return false, x
+	}
+	if typeId != thrift.CALL && typeId != thrift.ONEWAY {
+		in.Skip(thrift.STRUCT)
+		in.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.INVALID_MESSAGE_TYPE_EXCEPTION, fmt.Sprintf("%s: unexpected message type %d", name, typeId))
+		writeException(ctx, out, name, seqId, x)
+		return false, x
+	}
+	request, err := m.decode(in)
+	if err != nil {
+		in.ReadMessageEnd()
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+		writeException(ctx, out, name, seqId, x)
+		return false, x
+	}
+	if err := in.ReadMessageEnd(); err != nil {
+		return false, err
+	}
+	response, err := m.endpoint(ctx, request)
+	if typeId == thrift.ONEWAY {
+		return true, nil
+	}
+	if err != nil {
+		x := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing "+name+": "+err.Error())
+		writeException(ctx, out, name, seqId, x)
+		return true, x
+	}
+	if err := writeResult(ctx, out, name, seqId, m, response); err != nil {
+		return false, thrift.NewTTransportExceptionFromError(err)
+	}
+	return true, nil
+}
+
+// writeResult writes the <name>_result struct: field 0 holds the return
+// value, field 1 the TodoError when the service failed.
+func writeResult(ctx context.Context, out thrift.TProtocol, name string, seqId int32, m method, response interface{}) error {
+	if err := out.WriteMessageBegin(name, thrift.REPLY, seqId); err != nil {
+		return err
+	}
+	w := &structWriter{oprot: out}
+	w.begin(name + "_result")
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		w.field("err", thrift.STRUCT, 1, func() error { return writeTodoError(out, f.Failed()) })
+	} else if m.encode != nil {
+		w.field("success", m.result, 0, func() error { return m.encode(out, response) })
+	}
+	if err := w.end(); err != nil {
+		return err
+	}
+	if err := out.WriteMessageEnd(); err != nil {
+		return err
+	}
+	return out.Flush(ctx)
+}
+
+func writeException(ctx context.Context, out thrift.TProtocol, name string, seqId int32, x thrift.TApplicationException) {
+	out.WriteMessageBegin(name, thrift.EXCEPTION, seqId)
+	x.Write(out)
+	out.WriteMessageEnd()
+	out.Flush(ctx)
+}
+
+// readIdArgs reads a <name>_args struct whose only argument is a string id.
+func readIdArgs(iprot thrift.TProtocol) (id string, err error) {
+	err = readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		if fid != 1 || typeId != thrift.STRING {
+			return false, nil
+		}
+		var err error
+		id, err = iprot.ReadString()
+		return true, err
+	})
+	return id, err
+}
+
+// readDeleteArgs reads the <name>_args struct of Delete and DeleteCategory.
+func readDeleteArgs(iprot thrift.TProtocol) (id, policy string, err error) {
+	err = readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		var err error
+		switch {
+		case fid == 1 && typeId == thrift.STRING:
+			id, err = iprot.ReadString()
+		case fid == 2 && typeId == thrift.STRING:
+			policy, err = iprot.ReadString()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return id, policy, err
+}
+
+// readTodoArgs reads a <name>_args struct whose only argument is a Todo,
+// along with the fields the Todo sets.
+func readTodoArgs(iprot thrift.TProtocol) (t io.Todo, set fieldSet, err error) {
+	err = readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		if fid != 1 || typeId != thrift.STRUCT {
+			return false, nil
+		}
+		var err error
+		t, set, err = readTodo(iprot)
+		return true, err
+	})
+	return t, set, err
+}
+
+// readTodoCategoryArgs reads a <name>_args struct whose only argument is a
+// TodoCategory, along with the fields the TodoCategory sets.
+func readTodoCategoryArgs(iprot thrift.TProtocol) (c io.TodoCategory, set fieldSet, err error) {
+	err = readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		if fid != 1 || typeId != thrift.STRUCT {
+			return false, nil
+		}
+		var err error
+		c, set, err = readTodoCategory(iprot)
+		return true, err
+	})
+	return c, set, err
+}
+
+func readNoArgs(iprot thrift.TProtocol) error {
+	return readStruct(iprot, func(int16, thrift.TType) (bool, error) { return false, nil })
+}
+
+func decodeGetRequest(iprot thrift.TProtocol) (interface{}, error) {
+	return endpoint.GetRequest{}, readNoArgs(iprot)
+}
+
+func encodeGetResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoList(oprot, response.(endpoint.GetResponse).T)
+}
+
+func decodeAddRequest(iprot thrift.TProtocol) (interface{}, error) {
+	t, _, err := readTodoArgs(iprot)
+	return endpoint.AddRequest{Todo: t}, err
+}
+
+func encodeAddResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodo(oprot, response.(endpoint.AddResponse).T)
+}
+
+func decodeSetCompleteRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, err := readIdArgs(iprot)
+	return endpoint.SetCompleteRequest{Id: id}, err
+}
+
+func decodeRemoveCompleteRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, err := readIdArgs(iprot)
+	return endpoint.RemoveCompleteRequest{Id: id}, err
+}
+
+func decodeDeleteRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, policy, err := readDeleteArgs(iprot)
+	return endpoint.DeleteRequest{Id: id, Policy: policy}, err
+}
+
+// updateTodoRequest is an Update of the fields in set only.
+type updateTodoRequest struct {
+	todo io.Todo
+	set  fieldSet
+}
+
+func decodeUpdateRequest(iprot thrift.TProtocol) (interface{}, error) {
+	t, set, err := readTodoArgs(iprot)
+	return updateTodoRequest{todo: t, set: set}, err
+}
+
+// updateTodo makes Update change only the fields the client sets: the todo
+// is read first and the others keep their value, so a client built from an
+// older todo.thrift does not zero the fields it does not know about.
+func updateTodo(endpoints endpoint.Endpoints) endpoint1.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateTodoRequest)
+		response, err := endpoints.FindTodosEndpoint(ctx, endpoint.FindTodosRequest{By: "id", Ids: []uint{req.todo.ID}})
+		if err != nil {
+			return nil, err
+		}
+		found := response.(endpoint.FindTodosResponse)
+		if found.Error == nil && len(found.T) == 0 {
+			found.Error = fmt.Errorf("todo %d not found", req.todo.ID)
+		}
+		if found.Error != nil {
+			return endpoint.UpdateResponse{Error: found.Error}, nil
+		}
+		t := found.T[0]
+		mergeTodo(&t, req.todo, req.set)
+		return endpoints.UpdateEndpoint(ctx, endpoint.UpdateRequest{Todo: t})
+	}
+}
+
+func encodeUpdateResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodo(oprot, response.(endpoint.UpdateResponse).T)
+}
+
+func decodeSetStarRequest(iprot thrift.TProtocol) (interface{}, error) {
+	req := endpoint.SetStarRequest{}
+	err := readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		var err error
+		switch {
+		case fid == 1 && typeId == thrift.STRING:
+			req.Id, err = iprot.ReadString()
+		case fid == 2 && typeId == thrift.BYTE:
+			var b int8
+			b, err = iprot.ReadByte()
+			req.Star = uint8(b)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return req, err
+}
+
+func decodeReplyToRequest(iprot thrift.TProtocol) (interface{}, error) {
+	req := endpoint.ReplyToRequest{}
+	err := readStruct(iprot, func(fid int16, typeId thrift.TType) (bool, error) {
+		var err error
+		switch {
+		case fid == 1 && typeId == thrift.I64:
+			var v int64
+			v, err = iprot.ReadI64()
+			req.ParentId = uint(v)
+		case fid == 2 && typeId == thrift.STRUCT:
+			req.Todo, _, err = readTodo(iprot)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return req, err
+}
+
+func encodeReplyToResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodo(oprot, response.(endpoint.ReplyToResponse).T)
+}
+
+func decodeGetChildesRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, err := readIdArgs(iprot)
+	return endpoint.GetChildesRequest{Id: id}, err
+}
+
+func encodeGetChildesResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoList(oprot, response.(endpoint.GetChildesResponse).T)
+}
+
+func decodeGetCategoryRequest(iprot thrift.TProtocol) (interface{}, error) {
+	return endpoint.GetCategoryRequest{}, readNoArgs(iprot)
+}
+
+func encodeGetCategoryResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoCategoryList(oprot, response.(endpoint.GetCategoryResponse).C)
+}
+
+func decodeAddCategoryRequest(iprot thrift.TProtocol) (interface{}, error) {
+	c, _, err := readTodoCategoryArgs(iprot)
+	return endpoint.AddCategoryRequest{Category: c}, err
+}
+
+func encodeAddCategoryResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoCategory(oprot, response.(endpoint.AddCategoryResponse).C)
+}
+
+// updateCategoryRequest is an UpdateCategory of the fields in set only.
+type updateCategoryRequest struct {
+	category io.TodoCategory
+	set      fieldSet
+}
+
+func decodeUpdateCategoryRequest(iprot thrift.TProtocol) (interface{}, error) {
+	c, set, err := readTodoCategoryArgs(iprot)
+	return updateCategoryRequest{category: c, set: set}, err
+}
+
+// updateCategory makes UpdateCategory change only the fields the client
+// sets, like updateTodo.
+func updateCategory(endpoints endpoint.Endpoints) endpoint1.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateCategoryRequest)
+		response, err := endpoints.FindCategoriesEndpoint(ctx, endpoint.FindCategoriesRequest{By: "id", Ids: []uint{req.category.ID}})
+		if err != nil {
+			return nil, err
+		}
+		found := response.(endpoint.FindCategoriesResponse)
+		if found.Error == nil && len(found.C) == 0 {
+			found.Error = fmt.Errorf("category %d not found", req.category.ID)
+		}
+		if found.Error != nil {
+			return endpoint.UpdateCategoryResponse{Error: found.Error}, nil
+		}
+		c := found.C[0]
+		mergeTodoCategory(&c, req.category, req.set)
+		return endpoints.UpdateCategoryEndpoint(ctx, endpoint.UpdateCategoryRequest{Category: c})
+	}
+}
+
+func encodeUpdateCategoryResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoCategory(oprot, response.(endpoint.UpdateCategoryResponse).C)
+}
+
+func decodeDeleteCategoryRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, policy, err := readDeleteArgs(iprot)
+	return endpoint.DeleteCategoryRequest{Id: id, Policy: policy}, err
+}
+
+func decodeGetCatChildesRequest(iprot thrift.TProtocol) (interface{}, error) {
+	id, err := readIdArgs(iprot)
+	return endpoint.GetCatChildesRequest{Id: id}, err
+}
+
+func encodeGetCatChildesResponse(oprot thrift.TProtocol, response interface{}) error {
+	return writeTodoCategoryList(oprot, response.(endpoint.GetCatChildesResponse).C)
+}
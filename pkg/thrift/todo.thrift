@@ -0,0 +1,64 @@
+// Thrift IDL for the todo service. It mirrors service.TodoService so legacy
+// Thrift consumers can talk to the same endpoints as the HTTP transport.
+// Ids that the HTTP API takes as path or body strings are kept as strings,
+// timestamps are unix seconds. codec_gen.go is generated from the structs
+// below: run go generate after changing them.
+
+namespace go todo
+
+struct Todo {
+  1: i64 id
+  2: string title
+  3: string description
+  4: i64 category_id
+  5: byte star
+  6: bool complete
+  7: i64 parent_id
+  8: i64 created_at
+  9: i64 updated_at
+  // Percentage of completed subtasks, only set in responses.
+  10: i32 progress
+  // Order among the todos sharing the parent and category, set by Reorder.
+  11: double position
+  // Step in the workflow of the category, set by Transition.
+  12: string status
+  // 1, the highest, to 4, 0 for none.
+  13: byte priority
+  // Unix seconds, unset for none.
+  14: optional i64 due
+  // Hours of work left, used by the plans.
+  15: double estimate
+  // Story points.
+  16: double points
+}
+
+struct TodoCategory {
+  1: i64 id
+  2: string name
+  3: i64 parent_id
+  4: i64 created_at
+  5: i64 updated_at
+}
+
+exception TodoError {
+  1: string message
+}
+
+service TodoService {
+  list<Todo> Get() throws (1: TodoError err)
+  Todo Add(1: Todo todo) throws (1: TodoError err)
+  void SetComplete(1: string id) throws (1: TodoError err)
+  void RemoveComplete(1: string id) throws (1: TodoError err)
+  void Delete(1: string id, 2: string policy) throws (1: TodoError err)
+  // Update and UpdateCategory only change the fields the struct sets.
+  Todo Update(1: Todo todo) throws (1: TodoError err)
+  void SetStar(1: string id, 2: byte star) throws (1: TodoError err)
+  Todo ReplyTo(1: i64 parent_id, 2: Todo todo) throws (1: TodoError err)
+  list<Todo> GetChildes(1: string id) throws (1: TodoError err)
+
+  list<TodoCategory> GetCategory() throws (1: TodoError err)
+  TodoCategory AddCategory(1: TodoCategory category) throws (1: TodoError err)
+  TodoCategory UpdateCategory(1: TodoCategory category) throws (1: TodoError err)
+  void DeleteCategory(1: string id, 2: string policy) throws (1: TodoError err)
+  list<TodoCategory> GetCatChildes(1: string id) throws (1: TodoError err)
+}
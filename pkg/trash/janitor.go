@@ -0,0 +1,56 @@
+package trash
+
+import (
+	"context"
+	"sync"
+	"time"
+	service "todo/pkg/service"
+
+	log "github.com/go-kit/kit/log"
+)
+
+// Janitor empties the trash of the todos and categories that have been in
+// it for longer than the retention.
+type Janitor struct {
+	logger    log.Logger
+	retention time.Duration
+	interval  time.Duration
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewJanitor returns a Janitor purging the trash every interval.
+func NewJanitor(logger log.Logger, retention, interval time.Duration) *Janitor {
+	return &Janitor{
+		logger:    logger,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Run purges the trash, right away and then every interval, until Stop is
+// called.
+func (j *Janitor) Run() error {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		n, err := service.PurgeTrash(context.Background(), time.Now().Add(-j.retention))
+		if err != nil {
+			j.logger.Log("during", "purge", "err", err)
+		} else if n > 0 {
+			j.logger.Log("purged", n)
+		}
+		select {
+		case <-j.stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop makes Run return after the current pass.
+func (j *Janitor) Stop() {
+	j.once.Do(func() { close(j.stop) })
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	http1 "net/http"
+	"strconv"
+	"sync"
+	"time"
+	"todo/pkg/db"
+	io "todo/pkg/io"
+
+	log "github.com/go-kit/kit/log"
+)
+
+const (
+	pollInterval = time.Second
+	batchSize    = 100
+	maxBackoff   = time.Hour
+)
+
+// Sign returns the value of the X-Todo-Signature header of a delivery: the
+// hex HMAC-SHA256 of the payload keyed with the webhook secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher delivers change events to the webhooks subscribed to them.
+// The outbox relay publishes every event to it, which queues it in the
+// database as pending deliveries, so they survive a restart, and a
+// separate loop sends them, retrying failures with exponential backoff. A
+// delivery failing attempts times is a dead letter.
+type Dispatcher struct {
+	logger   log.Logger
+	client   *http1.Client
+	attempts int
+	backoff  time.Duration
+
+	wake chan struct{}
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewDispatcher returns a Dispatcher delivering the events published to
+// it. The first retry waits backoff, every later one twice as long as the
+// last.
+func NewDispatcher(logger log.Logger, attempts int, backoff time.Duration) *Dispatcher {
+	return &Dispatcher{
+		logger:   logger,
+		client:   &http1.Client{Timeout: 10 * time.Second},
+		attempts: attempts,
+		backoff:  backoff,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Run delivers the queued events until Stop is called.
+func (d *Dispatcher) Run() error {
+	d.deliverLoop()
+	return nil
+}
+
+// Stop makes Run return once the deliveries in flight are done.
+func (d *Dispatcher) Stop() {
+	d.once.Do(func() { close(d.stop) })
+}
+
+// Publish implements outbox.EventPublisher: it queues a delivery of e to
+// every webhook subscribed to it and wakes the delivery loop. The outbox
+// relay only marks e published once the deliveries are stored, and
+// publishing e again queues nothing more.
+func (d *Dispatcher) Publish(e io.Event) error {
+	if err := d.enqueue(e); err != nil {
+		return err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (d *Dispatcher) enqueue(e io.Event) error {
+	session := db.ConnectPGDB()
+	defer session.Close()
+	var webhooks []io.Webhook
+	if err := session.Find(&webhooks).Error; err != nil {
+		return err
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tx := session.Begin()
+	for _, w := range webhooks {
+		if !w.Wants(e.Type) {
+			continue
+		}
+		delivery := io.WebhookDelivery{
+			EventType:   e.Type,
+			Payload:     string(payload),
+			Status:      io.DeliveryPending,
+			NextAttempt: time.Now(),
+		}
+		err := tx.Where(io.WebhookDelivery{WebhookID: w.ID, EventID: e.ID}).Attrs(delivery).FirstOrCreate(&delivery).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+func (d *Dispatcher) deliverLoop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-d.wake:
+		case <-ticker.C:
+		}
+		if err := d.deliverDue(); err != nil {
+			d.logger.Log("during", "deliver", "err", err)
+		}
+	}
+}
+
+// deliverDue sends the pending deliveries whose next attempt is due, at
+// most batchSize of them, concurrently.
+func (d *Dispatcher) deliverDue() error {
+	session := db.ConnectPGDB()
+	defer session.Close()
+	var due []io.WebhookDelivery
+	err := session.Where("status = ? AND next_attempt <= ?", io.DeliveryPending, time.Now()).
+		Order("id").Limit(batchSize).Find(&due).Error
+	if err != nil || len(due) == 0 {
+		return err
+	}
+	ids := []uint{}
+	for _, v := range due {
+		ids = append(ids, v.WebhookID)
+	}
+	var webhooks []io.Webhook
+	if err := session.Where("id in (?)", ids).Find(&webhooks).Error; err != nil {
+		return err
+	}
+	byID := map[uint]io.Webhook{}
+	for _, w := range webhooks {
+		byID[w.ID] = w
+	}
+
+	var wg sync.WaitGroup
+	for i := range due {
+		delivery := &due[i]
+		w, ok := byID[delivery.WebhookID]
+		if !ok {
+			delivery.Status = io.DeliveryDead
+			delivery.LastError = "webhook deleted"
+			session.Save(delivery)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.attempt(w, delivery)
+			if err := session.Save(delivery).Error; err != nil {
+				d.logger.Log("during", "save", "delivery", delivery.ID, "err", err)
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// attempt posts the delivery and records the outcome on it.
+func (d *Dispatcher) attempt(w io.Webhook, delivery *io.WebhookDelivery) {
+	delivery.Attempts++
+	code, err := d.post(w, delivery)
+	delivery.StatusCode = code
+	if err == nil {
+		delivery.Status = io.DeliveryDelivered
+		delivery.LastError = ""
+		return
+	}
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.attempts {
+		delivery.Status = io.DeliveryDead
+		d.logger.Log("webhook", w.ID, "delivery", delivery.ID, "status", io.DeliveryDead, "err", err)
+		return
+	}
+	wait := d.backoff << uint(delivery.Attempts-1)
+	if wait <= 0 || wait > maxBackoff {
+		wait = maxBackoff
+	}
+	delivery.NextAttempt = time.Now().Add(wait)
+}
+
+func (d *Dispatcher) post(w io.Webhook, delivery *io.WebhookDelivery) (int, error) {
+	payload := []byte(delivery.Payload)
+	r, err := http1.NewRequest("POST", w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	r.Header.Set("User-Agent", "todo-webhook")
+	r.Header.Set("X-Todo-Event", delivery.EventType)
+	r.Header.Set("X-Todo-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	r.Header.Set("X-Todo-Signature", Sign(w.Secret, payload))
+	resp, err := d.client.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
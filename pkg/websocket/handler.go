@@ -0,0 +1,414 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	http1 "net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+	endpoint "todo/pkg/endpoint"
+	events "todo/pkg/events"
+	io "todo/pkg/io"
+
+	endpoint1 "github.com/go-kit/kit/endpoint"
+	websocket "github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+// request is a message sent by a client. Method is a TodoService method
+// name, whose params are the JSON of the matching endpoint request, or one
+// of subscribe, view and leave.
+type request struct {
+	ID     string          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// message is sent to clients: the result of one of their requests, a
+// change event or a presence update.
+type message struct {
+	Type     string      `json:"type"`
+	ID       string      `json:"id,omitempty"`
+	Result   interface{} `json:"result,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Event    *io.Event   `json:"event,omitempty"`
+	Presence *presence   `json:"presence,omitempty"`
+}
+
+type presence struct {
+	TodoID     uint     `json:"todo_id"`
+	CategoryID uint     `json:"category_id"`
+	Viewers    []string `json:"viewers"`
+}
+
+type subscribeParams struct {
+	Categories []uint `json:"categories"`
+}
+
+type viewParams struct {
+	TodoID     uint `json:"todo_id"`
+	CategoryID uint `json:"category_id"`
+}
+
+type command struct {
+	endpoint endpoint1.Endpoint
+	request  reflect.Type
+}
+
+type hub struct {
+	commands map[string]command
+	broker   *events.Broker
+	visible  events.Visibility
+	upgrader websocket.Upgrader
+
+	mtx     sync.Mutex
+	conns   map[*conn]struct{}
+	viewers map[uint]map[*conn]viewParams
+}
+
+// NewHandler returns a handler that upgrades requests to WebSocket
+// connections. Clients call TodoService methods with JSON messages,
+// subscribe to the change events of categories they can see and announce
+// which todo they are viewing; everyone subscribed to the todo's category
+// receives the list of its viewers. Viewers are named by the name query
+// parameter. Browsers may only connect from the server's own origin or
+// one of origins, "*" allowing any. A client too slow to take its
+// messages is disconnected.
+func NewHandler(endpoints endpoint.Endpoints, broker *events.Broker, visible events.Visibility, origins []string) http1.Handler {
+	h := &hub{
+		commands: map[string]command{
+			"Get":                  {endpoints.GetEndpoint, reflect.TypeOf(endpoint.GetRequest{})},
+			"Add":                  {endpoints.AddEndpoint, reflect.TypeOf(endpoint.AddRequest{})},
+			"SetComplete":          {endpoints.SetCompleteEndpoint, reflect.TypeOf(endpoint.SetCompleteRequest{})},
+			"RemoveComplete":       {endpoints.RemoveCompleteEndpoint, reflect.TypeOf(endpoint.RemoveCompleteRequest{})},
+			"Delete":               {endpoints.DeleteEndpoint, reflect.TypeOf(endpoint.DeleteRequest{})},
+			"Update":               {endpoints.UpdateEndpoint, reflect.TypeOf(endpoint.UpdateRequest{})},
+			"SetStar":              {endpoints.SetStarEndpoint, reflect.TypeOf(endpoint.SetStarRequest{})},
+			"ReplyTo":              {endpoints.ReplyToEndpoint, reflect.TypeOf(endpoint.ReplyToRequest{})},
+			"GetChildes":           {endpoints.GetChildesEndpoint, reflect.TypeOf(endpoint.GetChildesRequest{})},
+			"GetCategory":          {endpoints.GetCategoryEndpoint, reflect.TypeOf(endpoint.GetCategoryRequest{})},
+			"AddCategory":          {endpoints.AddCategoryEndpoint, reflect.TypeOf(endpoint.AddCategoryRequest{})},
+			"UpdateCategory":       {endpoints.UpdateCategoryEndpoint, reflect.TypeOf(endpoint.UpdateCategoryRequest{})},
+			"DeleteCategory":       {endpoints.DeleteCategoryEndpoint, reflect.TypeOf(endpoint.DeleteCategoryRequest{})},
+			"GetCatChildes":        {endpoints.GetCatChildesEndpoint, reflect.TypeOf(endpoint.GetCatChildesRequest{})},
+			"AddWebhook":           {endpoints.AddWebhookEndpoint, reflect.TypeOf(endpoint.AddWebhookRequest{})},
+			"GetWebhooks":          {endpoints.GetWebhooksEndpoint, reflect.TypeOf(endpoint.GetWebhooksRequest{})},
+			"DeleteWebhook":        {endpoints.DeleteWebhookEndpoint, reflect.TypeOf(endpoint.DeleteWebhookRequest{})},
+			"GetWebhookDeliveries": {endpoints.GetWebhookDeliveriesEndpoint, reflect.TypeOf(endpoint.GetWebhookDeliveriesRequest{})},
+			"GetDeadLetters":       {endpoints.GetDeadLettersEndpoint, reflect.TypeOf(endpoint.GetDeadLettersRequest{})},
+			"RetryDelivery":        {endpoints.RetryDeliveryEndpoint, reflect.TypeOf(endpoint.RetryDeliveryRequest{})},
+			"GetTree":              {endpoints.GetTreeEndpoint, reflect.TypeOf(endpoint.GetTreeRequest{})},
+			"Move":                 {endpoints.MoveEndpoint, reflect.TypeOf(endpoint.MoveRequest{})},
+			"MoveCategory":         {endpoints.MoveCategoryEndpoint, reflect.TypeOf(endpoint.MoveCategoryRequest{})},
+			"ListTrash":            {endpoints.ListTrashEndpoint, reflect.TypeOf(endpoint.ListTrashRequest{})},
+			"Restore":              {endpoints.RestoreEndpoint, reflect.TypeOf(endpoint.RestoreRequest{})},
+			"Purge":                {endpoints.PurgeEndpoint, reflect.TypeOf(endpoint.PurgeRequest{})},
+			"GetCategoryTree":      {endpoints.GetCategoryTreeEndpoint, reflect.TypeOf(endpoint.GetCategoryTreeRequest{})},
+			"GetCategoryPath":      {endpoints.GetCategoryPathEndpoint, reflect.TypeOf(endpoint.GetCategoryPathRequest{})},
+			"Reorder":              {endpoints.ReorderEndpoint, reflect.TypeOf(endpoint.ReorderRequest{})},
+			"Transition":           {endpoints.TransitionEndpoint, reflect.TypeOf(endpoint.TransitionRequest{})},
+			"GetWorkflow":          {endpoints.GetWorkflowEndpoint, reflect.TypeOf(endpoint.GetWorkflowRequest{})},
+			"SetWorkflow":          {endpoints.SetWorkflowEndpoint, reflect.TypeOf(endpoint.SetWorkflowRequest{})},
+			"GetBoard":             {endpoints.GetBoardEndpoint, reflect.TypeOf(endpoint.GetBoardRequest{})},
+			"SetPriority":          {endpoints.SetPriorityEndpoint, reflect.TypeOf(endpoint.SetPriorityRequest{})},
+			"GetMatrix":            {endpoints.GetMatrixEndpoint, reflect.TypeOf(endpoint.GetMatrixRequest{})},
+			"AddDependency":        {endpoints.AddDependencyEndpoint, reflect.TypeOf(endpoint.AddDependencyRequest{})},
+			"RemoveDependency":     {endpoints.RemoveDependencyEndpoint, reflect.TypeOf(endpoint.RemoveDependencyRequest{})},
+			"GetBlocked":           {endpoints.GetBlockedEndpoint, reflect.TypeOf(endpoint.GetBlockedRequest{})},
+			"GetReady":             {endpoints.GetReadyEndpoint, reflect.TypeOf(endpoint.GetReadyRequest{})},
+			"GetDependencyGraph":   {endpoints.GetDependencyGraphEndpoint, reflect.TypeOf(endpoint.GetDependencyGraphRequest{})},
+			"GetCategoryPlan":      {endpoints.GetCategoryPlanEndpoint, reflect.TypeOf(endpoint.GetCategoryPlanRequest{})},
+			"GetTreePlan":          {endpoints.GetTreePlanEndpoint, reflect.TypeOf(endpoint.GetTreePlanRequest{})},
+			"StartTimer":           {endpoints.StartTimerEndpoint, reflect.TypeOf(endpoint.StartTimerRequest{})},
+			"StopTimer":            {endpoints.StopTimerEndpoint, reflect.TypeOf(endpoint.StopTimerRequest{})},
+			"AddTimeEntry":         {endpoints.AddTimeEntryEndpoint, reflect.TypeOf(endpoint.AddTimeEntryRequest{})},
+			"GetTimeEntries":       {endpoints.GetTimeEntriesEndpoint, reflect.TypeOf(endpoint.GetTimeEntriesRequest{})},
+			"UpdateTimeEntry":      {endpoints.UpdateTimeEntryEndpoint, reflect.TypeOf(endpoint.UpdateTimeEntryRequest{})},
+			"DeleteTimeEntry":      {endpoints.DeleteTimeEntryEndpoint, reflect.TypeOf(endpoint.DeleteTimeEntryRequest{})},
+			"GetTimeReport":        {endpoints.GetTimeReportEndpoint, reflect.TypeOf(endpoint.GetTimeReportRequest{})},
+			"GetBurndown":          {endpoints.GetBurndownEndpoint, reflect.TypeOf(endpoint.GetBurndownRequest{})},
+			"GetTodoHistory":       {endpoints.GetTodoHistoryEndpoint, reflect.TypeOf(endpoint.GetTodoHistoryRequest{})},
+			"GetCategoryHistory":   {endpoints.GetCategoryHistoryEndpoint, reflect.TypeOf(endpoint.GetCategoryHistoryRequest{})},
+			"Undo":                 {endpoints.UndoEndpoint, reflect.TypeOf(endpoint.UndoRequest{})},
+			"Redo":                 {endpoints.RedoEndpoint, reflect.TypeOf(endpoint.RedoRequest{})},
+			"FindTodos":            {endpoints.FindTodosEndpoint, reflect.TypeOf(endpoint.FindTodosRequest{})},
+			"FindCategories":       {endpoints.FindCategoriesEndpoint, reflect.TypeOf(endpoint.FindCategoriesRequest{})},
+		},
+		broker:   broker,
+		visible:  visible,
+		upgrader: websocket.Upgrader{CheckOrigin: events.CheckOrigin(origins)},
+		conns:    map[*conn]struct{}{},
+		viewers:  map[uint]map[*conn]viewParams{},
+	}
+	return h
+}
+
+func (h *hub) ServeHTTP(w http1.ResponseWriter, r *http1.Request) {
+	visible, all := h.visible(r)
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = r.RemoteAddr
+	}
+	c := &conn{
+		ws:      ws,
+		name:    name,
+		visible: visible,
+		all:     all,
+		send:    make(chan message, 64),
+		done:    make(chan struct{}),
+	}
+	h.mtx.Lock()
+	h.conns[c] = struct{}{}
+	h.mtx.Unlock()
+
+	_, _, changes, cancel := h.broker.Subscribe(math.MaxUint64)
+	go c.writeLoop()
+	go func() {
+		for {
+			select {
+			case <-c.done:
+				return
+			case e, ok := <-changes:
+				if !ok {
+					// Fell behind the broker: the client reconnects and
+					// reloads.
+					ws.Close()
+					return
+				}
+				if c.wants(e.CategoryID) {
+					c.push(message{Type: "event", Event: &e})
+				}
+			}
+		}
+	}()
+	h.readLoop(r.Context(), c)
+
+	cancel()
+	h.leaveAll(c)
+	h.mtx.Lock()
+	delete(h.conns, c)
+	h.mtx.Unlock()
+	close(c.done)
+	ws.Close()
+}
+
+func (h *hub) readLoop(ctx context.Context, c *conn) {
+	c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		var req request
+		if err := c.ws.ReadJSON(&req); err != nil {
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				c.push(message{Type: "result", Error: "malformed message"})
+				continue
+			}
+			return
+		}
+		result, err := h.handle(ctx, c, req)
+		reply := message{Type: "result", ID: req.ID, Result: result}
+		if err != nil {
+			reply.Result, reply.Error = nil, err.Error()
+		}
+		c.push(reply)
+	}
+}
+
+func (h *hub) handle(ctx context.Context, c *conn, req request) (interface{}, error) {
+	switch req.Method {
+	case "subscribe":
+		var p subscribeParams
+		if err := unmarshalParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		c.subscribe(p.Categories)
+		return p, nil
+	case "view":
+		var p viewParams
+		if err := unmarshalParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		h.view(c, p)
+		return p, nil
+	case "leave":
+		var p viewParams
+		if err := unmarshalParams(req.Params, &p); err != nil {
+			return nil, err
+		}
+		h.leave(c, p.TodoID)
+		return p, nil
+	}
+	cmd, ok := h.commands[req.Method]
+	if !ok {
+		return nil, fmt.Errorf("unknown method %q", req.Method)
+	}
+	r := reflect.New(cmd.request)
+	if err := unmarshalParams(req.Params, r.Interface()); err != nil {
+		return nil, err
+	}
+	response, err := cmd.endpoint(ctx, r.Elem().Interface())
+	if err != nil {
+		return nil, err
+	}
+	if f, ok := response.(endpoint.Failure); ok && f.Failed() != nil {
+		return nil, f.Failed()
+	}
+	return response, nil
+}
+
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(params, v)
+}
+
+func (h *hub) view(c *conn, p viewParams) {
+	if !c.canSee(p.CategoryID) {
+		return
+	}
+	h.mtx.Lock()
+	if h.viewers[p.TodoID] == nil {
+		h.viewers[p.TodoID] = map[*conn]viewParams{}
+	}
+	h.viewers[p.TodoID][c] = p
+	h.mtx.Unlock()
+	h.broadcastPresence(p.TodoID, p.CategoryID)
+}
+
+func (h *hub) leave(c *conn, todoID uint) {
+	h.mtx.Lock()
+	p, ok := h.viewers[todoID][c]
+	delete(h.viewers[todoID], c)
+	if len(h.viewers[todoID]) == 0 {
+		delete(h.viewers, todoID)
+	}
+	h.mtx.Unlock()
+	if ok {
+		h.broadcastPresence(todoID, p.CategoryID)
+	}
+}
+
+func (h *hub) leaveAll(c *conn) {
+	h.mtx.Lock()
+	var viewing []uint
+	for id, viewers := range h.viewers {
+		if _, ok := viewers[c]; ok {
+			viewing = append(viewing, id)
+		}
+	}
+	h.mtx.Unlock()
+	for _, id := range viewing {
+		h.leave(c, id)
+	}
+}
+
+// broadcastPresence sends the viewers of a todo to every connection
+// subscribed to its category.
+func (h *hub) broadcastPresence(todoID, categoryID uint) {
+	h.mtx.Lock()
+	p := &presence{TodoID: todoID, CategoryID: categoryID, Viewers: []string{}}
+	for c := range h.viewers[todoID] {
+		p.Viewers = append(p.Viewers, c.name)
+	}
+	var targets []*conn
+	for c := range h.conns {
+		if c.wants(categoryID) {
+			targets = append(targets, c)
+		}
+	}
+	h.mtx.Unlock()
+	sort.Strings(p.Viewers)
+	for _, c := range targets {
+		c.push(message{Type: "presence", Presence: p})
+	}
+}
+
+type conn struct {
+	ws      *websocket.Conn
+	name    string
+	visible map[uint]bool
+	all     bool
+	send    chan message
+	done    chan struct{}
+	slow    sync.Once
+
+	mtx        sync.Mutex
+	subscribed map[uint]bool
+}
+
+func (c *conn) canSee(categoryID uint) bool {
+	return c.all || c.visible[categoryID]
+}
+
+// subscribe replaces the categories c receives events and presence for;
+// no categories means every category c can see.
+func (c *conn) subscribe(categories []uint) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.subscribed = map[uint]bool{}
+	for _, id := range categories {
+		c.subscribed[id] = true
+	}
+}
+
+// wants reports whether c receives events and presence for categoryID.
+// Nothing is sent before the first subscribe.
+func (c *conn) wants(categoryID uint) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.subscribed == nil || !c.canSee(categoryID) {
+		return false
+	}
+	return len(c.subscribed) == 0 || c.subscribed[categoryID]
+}
+
+// push queues m for the client without waiting, so that a slow client
+// holds up no one else. A client whose queue is full is disconnected, and
+// m dropped, as it is when the connection is gone.
+func (c *conn) push(m message) {
+	select {
+	case c.send <- m:
+	case <-c.done:
+	default:
+		c.slow.Do(func() { c.ws.Close() })
+	}
+}
+
+func (c *conn) writeLoop() {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case m := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteJSON(m); err != nil {
+				c.ws.Close()
+				return
+			}
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.ws.Close()
+				return
+			}
+		}
+	}
+}